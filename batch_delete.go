@@ -0,0 +1,113 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ErrInvalidBatchSize is returned by BatchDelete when the batch size isn't positive.
+var ErrInvalidBatchSize = errors.NewKind("invalid batch size: %d")
+
+// ErrBatchDeleteIncomplete is returned by BatchDelete when its batches keep deleting full batches past the number of
+// rows that matched the condition when it started, such as when rows are inserted as fast as they are deleted.
+var ErrBatchDeleteIncomplete = errors.NewKind("batch delete of %s stopped after %d rows with matching rows remaining")
+
+// BatchDeleteOption configures how BatchDelete deletes its rows.
+type BatchDeleteOption func(*batchDeleteConfig)
+
+type batchDeleteConfig struct {
+	commit bool
+}
+
+// CommitBetweenBatches is a BatchDeleteOption committing the transaction of the session after every batch, so that
+// deleting many rows doesn't make a single huge transaction.
+func CommitBetweenBatches() BatchDeleteOption {
+	return func(c *batchDeleteConfig) {
+		c.commit = true
+	}
+}
+
+// BatchDelete deletes the rows of the table given, of the database given or of the current one if it's empty, that
+// match the condition given, an SQL expression, in batches of at most batchSize rows. It runs DELETE ... LIMIT
+// statements in the session of the context until one deletes fewer rows than the batch size. An empty condition
+// deletes every row. With CommitBetweenBatches, the transaction of the table's database is committed with
+// Catalog.CommitTransaction after every batch.
+//
+// The rows matching the condition are counted first, and BatchDelete runs no more batches than needed to delete
+// them: if the last of those is still full, it fails with ErrBatchDeleteIncomplete rather than run forever.
+//
+// The context is checked for cancellation, and the session for being killed, between batches. Returns the total
+// number of rows deleted, which on error includes the rows deleted by the batches before the one that failed.
+func (e *Engine) BatchDelete(ctx *sql.Context, db, table, where string, batchSize int, opts ...BatchDeleteOption) (int64, error) {
+	if batchSize <= 0 {
+		return 0, ErrInvalidBatchSize.New(batchSize)
+	}
+
+	var cfg batchDeleteConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if db == "" {
+		db = ctx.GetCurrentDatabase()
+	}
+	name := fmt.Sprintf("%s.%s", sql.QuoteIdentifier(db), sql.QuoteIdentifier(table))
+	condition := ""
+	if where != "" {
+		condition = fmt.Sprintf(" WHERE %s", where)
+	}
+
+	_, rows, err := e.queryRows(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s%s", name, condition))
+	if err != nil {
+		return 0, err
+	}
+	matching := rows[0][0].(int64)
+	maxBatches := matching/int64(batchSize) + 1
+
+	query := fmt.Sprintf("DELETE FROM %s%s LIMIT %d", name, condition, batchSize)
+	var total int64
+	for batches := int64(0); batches < maxBatches; batches++ {
+		if err := ctx.CancellationError(); err != nil {
+			return total, err
+		}
+		if err := ctx.CheckInterrupted(); err != nil {
+			return total, err
+		}
+
+		_, rows, err := e.queryRows(ctx, query)
+		if err != nil {
+			return total, err
+		}
+		deleted := int64(rows[0][0].(sql.OkResult).RowsAffected)
+		total += deleted
+
+		if cfg.commit {
+			if err := e.Catalog.CommitTransaction(ctx, db); err != nil {
+				return total, err
+			}
+		}
+
+		if deleted < int64(batchSize) {
+			return total, nil
+		}
+	}
+
+	return total, ErrBatchDeleteIncomplete.New(name, total)
+}
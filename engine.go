@@ -16,6 +16,7 @@ package sqle
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/go-kit/kit/metrics/discard"
@@ -36,6 +37,37 @@ type Config struct {
 	VersionPostfix string
 	// Auth used for authentication and authorization.
 	Auth auth.Auth
+	// PreQueryHook, if set, is called with every parsed statement before it's analyzed.
+	PreQueryHook PreQueryHook
+	// TableResolver, if set, is registered on the catalog to resolve table names before its databases are consulted.
+	TableResolver sql.TableResolver
+	// MaxExecutionTime is the execution timeout of SELECT statements for sessions that don't set max_execution_time.
+	// Zero means no timeout.
+	MaxExecutionTime time.Duration
+	// SlowQueryHandler, if set, is called with every statement that runs for longer than long_query_time.
+	SlowQueryHandler SlowQueryHandler
+	// BinlogWriter, if set, is registered on the catalog to log the DML statements of committed transactions.
+	BinlogWriter sql.BinlogWriter
+}
+
+// PreQueryHook is given the query and the statement parsed from it before the statement is analyzed. It returns the
+// statement to analyze instead, which may be the one given, or an error to reject the query, which is returned as is.
+type PreQueryHook func(ctx *sql.Context, query string, node sql.Node) (sql.Node, error)
+
+// SlowQueryHandler is given the statements that take longer than the long_query_time session variable, once their
+// rows are closed, with the context they ran with, which holds their session.
+type SlowQueryHandler func(ctx *sql.Context, query SlowQuery)
+
+// SlowQuery describes a statement given to a SlowQueryHandler.
+type SlowQuery struct {
+	// Query is the statement.
+	Query string
+	// Duration is the time from the start of the statement until its rows were closed.
+	Duration time.Duration
+	// RowsExamined is the number of rows read from tables by the statement.
+	RowsExamined int64
+	// RowsSent is the number of rows returned by the statement.
+	RowsSent int64
 }
 
 // Engine is a SQL engine.
@@ -44,6 +76,12 @@ type Engine struct {
 	Analyzer *analyzer.Analyzer
 	Auth     auth.Auth
 	LS       *sql.LockSubsystem
+	// PreQueryHook is called with every parsed statement before it's analyzed. See Config.PreQueryHook.
+	PreQueryHook PreQueryHook
+	// MaxExecutionTime is the default execution timeout of SELECT statements. See Config.MaxExecutionTime.
+	MaxExecutionTime time.Duration
+	// SlowQueryHandler is called with slow statements. See Config.SlowQueryHandler.
+	SlowQueryHandler SlowQueryHandler
 }
 
 type ColumnWithRawDefault struct {
@@ -114,7 +152,22 @@ func New(c *sql.Catalog, a *analyzer.Analyzer, cfg *Config) *Engine {
 		au = cfg.Auth
 	}
 
-	return &Engine{c, a, au, ls}
+	var preQueryHook PreQueryHook
+	var maxExecutionTime time.Duration
+	var slowQueryHandler SlowQueryHandler
+	if cfg != nil {
+		preQueryHook = cfg.PreQueryHook
+		maxExecutionTime = cfg.MaxExecutionTime
+		slowQueryHandler = cfg.SlowQueryHandler
+		if cfg.TableResolver != nil {
+			c.TableResolver = cfg.TableResolver
+		}
+		if cfg.BinlogWriter != nil {
+			c.BinlogWriter = cfg.BinlogWriter
+		}
+	}
+
+	return &Engine{c, a, au, ls, preQueryHook, maxExecutionTime, slowQueryHandler}
 }
 
 // NewDefault creates a new default Engine.
@@ -151,6 +204,77 @@ func (e *Engine) Query(
 	return e.QueryWithBindings(ctx, query, nil)
 }
 
+// Result is the result of one of the statements run by QueryMulti.
+type Result struct {
+	// Query is the statement.
+	Query string
+	// Schema is the schema of the rows returned by the statement.
+	Schema sql.Schema
+	// Rows are the rows returned by the statement.
+	Rows []sql.Row
+	// Err is the error the statement failed with, if any.
+	Err error
+}
+
+// QueryMultiOption configures how QueryMulti runs its statements.
+type QueryMultiOption func(*queryMultiConfig)
+
+type queryMultiConfig struct {
+	continueOnError bool
+}
+
+// ContinueOnError is a QueryMultiOption running the statements after one that fails instead of stopping at it.
+func ContinueOnError() QueryMultiOption {
+	return func(c *queryMultiConfig) {
+		c.continueOnError = true
+	}
+}
+
+// QueryMulti executes the semicolon-separated statements of the query string given one after the other in the session
+// of the context, as for a client with CLIENT_MULTI_STATEMENTS, and returns a result for each of them. The rows of a
+// statement are read in full before the next one starts. By default, it stops at the first statement that fails and
+// returns the results up to and including that one along with its error. With ContinueOnError, it runs every
+// statement and the errors are only in the results.
+func (e *Engine) QueryMulti(ctx *sql.Context, queries string, opts ...QueryMultiOption) ([]Result, error) {
+	var cfg queryMultiConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	statements, err := parse.SplitStatements(queries)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(statements))
+	for _, query := range statements {
+		result := Result{Query: query}
+		result.Schema, result.Rows, result.Err = e.queryRows(ctx, query)
+		results = append(results, result)
+		if result.Err != nil && !cfg.continueOnError {
+			return results, result.Err
+		}
+	}
+
+	return results, nil
+}
+
+// queryRows executes a query and reads all of its rows.
+func (e *Engine) queryRows(ctx *sql.Context, query string) (sql.Schema, []sql.Row, error) {
+	schema, iter, err := e.Query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := sql.RowIterToRows(ctx, iter)
+	if err != nil {
+		_ = iter.Close(ctx)
+		return nil, nil, err
+	}
+
+	return schema, rows, nil
+}
+
 func (e *Engine) QueryWithBindings(
 	ctx *sql.Context,
 	query string,
@@ -165,11 +289,28 @@ func (e *Engine) QueryWithBindings(
 	finish := observeQuery(ctx, query)
 	defer finish(err)
 
+	start := time.Now()
+	ctx.StartStatement()
+
+	// A session killed while idle aborts its next statement
+	if err = ctx.CheckInterrupted(); err != nil {
+		return nil, nil, err
+	}
+
 	parsed, err = parse.Parse(ctx, query)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	e.countStatement(ctx, parsed)
+
+	if e.PreQueryHook != nil {
+		parsed, err = e.PreQueryHook(ctx, query, parsed)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	var perm = auth.ReadPerm
 	var typ = sql.QueryProcess
 	switch parsed.(type) {
@@ -229,14 +370,224 @@ func (e *Engine) QueryWithBindings(
 		}
 	}
 
+	var binlogEvent *sql.BinlogEvent
+	if e.Catalog.BinlogWriter != nil && isDMLStatement(parsed) {
+		binlogEvent = sql.NewBinlogEvent(query, ctx.QueryTime(), binlogFormat(ctx, analyzed))
+		ctx = ctx.WithBinlogEvent(binlogEvent)
+	}
+
+	var cancelTimeout func()
+	if timeout := e.statementTimeout(ctx, query, parsed); timeout > 0 {
+		ctx, cancelTimeout = ctx.WithStatementTimeout(timeout)
+	}
+
 	iter, err = analyzed.RowIter(ctx, nil)
 	if err != nil {
+		if cancelTimeout != nil {
+			cancelTimeout()
+		}
 		return nil, nil, err
 	}
 
+	if binlogEvent != nil {
+		iter = &binlogIter{RowIter: iter, ctx: ctx, event: binlogEvent}
+	}
+
+	if cancelTimeout != nil {
+		iter = &timeoutIter{iter, cancelTimeout}
+	}
+
+	if e.SlowQueryHandler != nil && !isAdministrativeStatement(parsed) {
+		iter = &slowQueryIter{RowIter: iter, handler: e.SlowQueryHandler, ctx: ctx, query: query, start: start}
+	}
+
 	return analyzed.Schema(), iter, nil
 }
 
+// isAdministrativeStatement returns whether the statement given is one MySQL leaves out of its slow query log by
+// default, as it's expected to be slow, such as ALTER TABLE or CREATE INDEX.
+func isAdministrativeStatement(n sql.Node) bool {
+	switch n.(type) {
+	case *plan.CreateIndex, *plan.DropIndex, *plan.AlterIndex, *plan.AlterAutoIncrement, *plan.AddColumn,
+		*plan.DropColumn, *plan.RenameColumn, *plan.ModifyColumn, *plan.RenameTable:
+		return true
+	default:
+		return false
+	}
+}
+
+// slowQueryIter gives its statement to a SlowQueryHandler once its rows are closed if it ran for longer than
+// long_query_time. The duration is measured from start, read from the monotonic clock when the statement started.
+type slowQueryIter struct {
+	sql.RowIter
+	handler  SlowQueryHandler
+	ctx      *sql.Context
+	query    string
+	start    time.Time
+	rowsSent int64
+}
+
+func (i *slowQueryIter) Next() (sql.Row, error) {
+	row, err := i.RowIter.Next()
+	if err == nil {
+		i.rowsSent++
+	}
+	return row, err
+}
+
+// ResultSets implements the plan.ResultSetsRowIter interface.
+func (i *slowQueryIter) ResultSets() []plan.ResultSet {
+	if rsIter, ok := i.RowIter.(plan.ResultSetsRowIter); ok {
+		return rsIter.ResultSets()
+	}
+	return nil
+}
+
+func (i *slowQueryIter) Close(ctx *sql.Context) error {
+	err := i.RowIter.Close(ctx)
+
+	if duration := time.Since(i.start); duration > i.ctx.LongQueryTime() {
+		i.handler(i.ctx, SlowQuery{
+			Query:        i.query,
+			Duration:     duration,
+			RowsExamined: i.ctx.RowsExamined(),
+			RowsSent:     i.rowsSent,
+		})
+	}
+	return err
+}
+
+// countStatement increments the status variables counting the statement given, both for the session of the context
+// and globally.
+func (e *Engine) countStatement(ctx *sql.Context, n sql.Node) {
+	names := []string{sql.StatusQuestions}
+	switch n := n.(type) {
+	case *plan.InsertInto:
+		if !n.IsReplace {
+			names = append(names, sql.StatusComInsert)
+		}
+	case *plan.Update:
+		names = append(names, sql.StatusComUpdate)
+	case *plan.DeleteFrom:
+		names = append(names, sql.StatusComDelete)
+	default:
+		if isSelectStatement(n) {
+			names = append(names, sql.StatusComSelect)
+		}
+	}
+
+	counters := []*sql.StatusCounters{e.Catalog.GlobalStatus}
+	if ctx.Session != nil {
+		counters = append(counters, ctx.Status())
+	}
+	for _, status := range counters {
+		if status == nil {
+			continue
+		}
+		status.Increment(names...)
+	}
+}
+
+// statementTimeout returns the execution timeout of the query given, parsed as the node given, or 0 if it has none.
+// As in MySQL, only SELECT statements have a timeout: the one given by a MAX_EXECUTION_TIME(ms) optimizer hint, or
+// else by the max_execution_time session variable, or else the default of the engine.
+func (e *Engine) statementTimeout(ctx *sql.Context, query string, parsed sql.Node) time.Duration {
+	if !isSelectStatement(parsed) {
+		return 0
+	}
+	if timeout, ok := parse.MaxExecutionTime(ctx, query); ok {
+		return timeout
+	}
+	if timeout := ctx.MaxExecutionTime(); timeout > 0 {
+		return timeout
+	}
+	return e.MaxExecutionTime
+}
+
+// timeoutIter releases the timeout of a statement once its rows are closed.
+type timeoutIter struct {
+	sql.RowIter
+	cancel func()
+}
+
+func (i *timeoutIter) Close(ctx *sql.Context) error {
+	defer i.cancel()
+	return i.RowIter.Close(ctx)
+}
+
+// isSelectStatement returns whether the parsed statement given is a SELECT: one of the nodes a SELECT, a UNION of
+// them or a locking read of one is parsed to.
+func isSelectStatement(n sql.Node) bool {
+	switch n.(type) {
+	case *plan.Project, *plan.GroupBy, *plan.Window, *plan.Having, *plan.Distinct, *plan.Sort, *plan.Offset,
+		*plan.Limit, *plan.With, *plan.Union, *plan.LockingRead:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDMLStatement returns whether the statement given changes the rows of tables, and so is logged by the binlog
+// writer of the catalog.
+func isDMLStatement(n sql.Node) bool {
+	switch n.(type) {
+	case *plan.InsertInto, *plan.Update, *plan.DeleteFrom:
+		return true
+	default:
+		return false
+	}
+}
+
+// binlogFormat returns the format the analyzed DML statement given is logged in, given by the binlog_format session
+// variable. Under MIXED, statements with an expression that isn't deterministic are logged in row format, and the
+// others in statement format.
+func binlogFormat(ctx *sql.Context, n sql.Node) sql.BinlogFormat {
+	format := ctx.BinlogFormat()
+	if format != sql.BinlogFormatMixed {
+		return format
+	}
+
+	deterministic := true
+	plan.InspectExpressions(n, func(e sql.Expression) bool {
+		if !sql.IsDeterministic(e) {
+			deterministic = false
+		}
+		// IsDeterministic looks at the children of the expression already
+		return false
+	})
+	if deterministic {
+		return sql.BinlogFormatStatement
+	}
+	return sql.BinlogFormatRow
+}
+
+// binlogIter records the binlog event of a DML statement in the pending changes of its transaction once its rows are
+// closed, unless the statement failed. Events of statements that didn't change any row are dropped.
+type binlogIter struct {
+	sql.RowIter
+	ctx    *sql.Context
+	event  *sql.BinlogEvent
+	failed bool
+}
+
+func (i *binlogIter) Next() (sql.Row, error) {
+	row, err := i.RowIter.Next()
+	if err != nil && err != io.EOF {
+		i.failed = true
+	}
+	return row, err
+}
+
+func (i *binlogIter) Close(ctx *sql.Context) error {
+	if err := i.RowIter.Close(ctx); err != nil {
+		return err
+	}
+	if !i.failed {
+		i.ctx.PendingChanges().RecordBinlogEvent(*i.event)
+	}
+	return nil
+}
+
 // ParseDefaults takes in a schema, along with each column's default value in a string form, and returns the schema
 // with the default values parsed and resolved.
 func ResolveDefaults(tableName string, schema []*ColumnWithRawDefault) (sql.Schema, error) {
@@ -254,6 +254,164 @@ func TestReadOnly(t *testing.T, harness Harness) {
 	}
 }
 
+func TestPreQueryHook(t *testing.T, harness Harness) {
+	require := require.New(t)
+
+	db := harness.NewDatabase("mydb")
+	_, err := harness.NewTable(db, "mytable", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "mytable", PrimaryKey: true},
+	})
+	require.NoError(err)
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+
+	vetoed := fmt.Errorf("queries on other_table are not allowed")
+	var queries []string
+	cfg := &sqle.Config{
+		PreQueryHook: func(ctx *sql.Context, query string, node sql.Node) (sql.Node, error) {
+			queries = append(queries, query)
+			if strings.Contains(query, "other_table") {
+				return nil, vetoed
+			}
+			if _, ok := node.(*plan.DeleteFrom); ok {
+				// deletes are rewritten to no-op queries
+				return parse.Parse(ctx, "SELECT 1")
+			}
+			return node, nil
+		},
+	}
+	e := sqle.New(catalog, analyzer.NewBuilder(catalog).Build(), cfg)
+
+	TestQueryWithContext(t, NewContext(harness), e, "INSERT INTO mytable VALUES (1)", []sql.Row{{sql.NewOkResult(1)}}, nil, nil)
+	TestQueryWithContext(t, NewContext(harness), e, "DELETE FROM mytable", []sql.Row{{int8(1)}}, nil, nil)
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT i FROM mytable", []sql.Row{{int64(1)}}, nil, nil)
+
+	_, _, err = e.Query(NewContext(harness), "SELECT * FROM other_table")
+	require.Equal(vetoed, err)
+	require.Equal([]string{"INSERT INTO mytable VALUES (1)", "DELETE FROM mytable", "SELECT i FROM mytable", "SELECT * FROM other_table"}, queries)
+}
+
+type tableResolverFunc func(ctx *sql.Context, db, name string) (sql.Table, error)
+
+func (f tableResolverFunc) Resolve(ctx *sql.Context, db, name string) (sql.Table, error) {
+	return f(ctx, db, name)
+}
+
+func TestTableResolver(t *testing.T, harness Harness) {
+	require := require.New(t)
+
+	db := harness.NewDatabase("mydb")
+	table, err := harness.NewTable(db, "mytable", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "mytable", PrimaryKey: true},
+	})
+	require.NoError(err)
+	InsertRows(t, NewContext(harness), mustInsertableTable(t, table), sql.NewRow(int64(1)), sql.NewRow(int64(2)))
+
+	// The federated table is in a database the catalog doesn't have
+	remote := harness.NewDatabase("remote")
+	federated, err := harness.NewTable(remote, "federated", sql.Schema{
+		{Name: "f", Type: sql.Int64, Source: "federated"},
+	})
+	require.NoError(err)
+	InsertRows(t, NewContext(harness), mustInsertableTable(t, federated), sql.NewRow(int64(3)))
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+
+	cfg := &sqle.Config{
+		TableResolver: tableResolverFunc(func(ctx *sql.Context, db, name string) (sql.Table, error) {
+			switch strings.ToLower(name) {
+			case "synonym":
+				return nil, sql.TableAlias{Name: "mytable"}
+			case "federated":
+				return federated, nil
+			case "loop":
+				return nil, sql.TableAlias{Name: "loop"}
+			default:
+				return nil, sql.ErrTableNotFound.New(name)
+			}
+		}),
+	}
+	e := sqle.New(catalog, analyzer.NewBuilder(catalog).Build(), cfg)
+
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT i FROM synonym ORDER BY i", []sql.Row{{int64(1)}, {int64(2)}}, nil, nil)
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT i FROM mydb.synonym WHERE i = 2", []sql.Row{{int64(2)}}, nil, nil)
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT i, f FROM mytable JOIN federated ON i < f ORDER BY i", []sql.Row{{int64(1), int64(3)}, {int64(2), int64(3)}}, nil, nil)
+
+	_, _, err = e.Query(NewContext(harness), "SELECT * FROM loop")
+	require.True(sql.ErrTableAliasLoop.Is(err))
+	_, _, err = e.Query(NewContext(harness), "SELECT * FROM missing")
+	require.True(sql.ErrTableNotFound.Is(err))
+}
+
+func TestTemporaryTables(t *testing.T, harness Harness) {
+	require := require.New(t)
+
+	db := harness.NewDatabase("mydb")
+	if _, ok := db.(sql.TemporaryTableCreator); !ok {
+		t.Skip("database doesn't support temporary tables")
+	}
+	table, err := harness.NewTable(db, "mytable", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "mytable", PrimaryKey: true},
+	})
+	require.NoError(err)
+	InsertRows(t, NewContext(harness), mustInsertableTable(t, table), sql.NewRow(int64(1)), sql.NewRow(int64(2)))
+
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+	e := sqle.New(catalog, analyzer.NewBuilder(catalog).Build(), new(sqle.Config))
+
+	newContext := func() *sql.Context {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()), sql.WithIndexRegistry(sql.NewIndexRegistry()), sql.WithViewRegistry(sql.NewViewRegistry()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx
+	}
+	ctx, other := newContext(), newContext()
+
+	// A temporary table shadows the permanent table with the same name, only in its session
+	TestQueryWithContext(t, ctx, e, "CREATE TEMPORARY TABLE mytable (i bigint primary key, s varchar(10))", []sql.Row{}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "INSERT INTO mytable VALUES (10, 'temp')", []sql.Row{{sql.NewOkResult(1)}}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "SELECT i, s FROM mytable", []sql.Row{{int64(10), "temp"}}, nil, nil)
+	TestQueryWithContext(t, other, e, "SELECT i FROM mytable ORDER BY i", []sql.Row{{int64(1)}, {int64(2)}}, nil, nil)
+
+	_, _, err = e.Query(ctx, "CREATE TEMPORARY TABLE mytable (i bigint)")
+	require.True(sql.ErrTableAlreadyExists.Is(err))
+	TestQueryWithContext(t, ctx, e, "CREATE TEMPORARY TABLE IF NOT EXISTS mytable (i bigint)", []sql.Row{}, nil, nil)
+
+	TestQueryWithContext(t, ctx, e, "CREATE TEMPORARY TABLE scratch (x int)", []sql.Row{}, nil, nil)
+	_, _, err = e.Query(other, "SELECT * FROM scratch")
+	require.True(sql.ErrTableNotFound.Is(err))
+
+	// Dropping the temporary table reveals the permanent one again
+	TestQueryWithContext(t, ctx, e, "DROP TEMPORARY TABLE mytable", []sql.Row{}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "SELECT i FROM mytable ORDER BY i", []sql.Row{{int64(1)}, {int64(2)}}, nil, nil)
+	_, _, err = e.Query(ctx, "DROP TEMPORARY TABLE mytable")
+	require.True(sql.ErrTableNotFound.Is(err))
+	TestQueryWithContext(t, ctx, e, "DROP TEMPORARY TABLE IF EXISTS mytable", []sql.Row{}, nil, nil)
+
+	// DROP TEMPORARY TABLE drops nothing if any of the tables isn't temporary
+	_, _, err = e.Query(ctx, "DROP TEMPORARY TABLE scratch, mytable")
+	require.True(sql.ErrTableNotFound.Is(err))
+	require.Equal(1, ctx.TemporaryTables().Len())
+
+	// Read-only sessions may create and drop temporary tables, but not permanent ones
+	ctx.SetReadOnly(true)
+	TestQueryWithContext(t, ctx, e, "CREATE TEMPORARY TABLE readonly (x int)", []sql.Row{}, nil, nil)
+	_, _, err = e.Query(ctx, "CREATE TABLE permanent (x int)")
+	require.True(sql.ErrReadOnlyTransaction.Is(err))
+	_, _, err = e.Query(ctx, "DROP TABLE readonly, mytable")
+	require.True(sql.ErrReadOnlyTransaction.Is(err))
+	require.Equal(2, ctx.TemporaryTables().Len())
+	TestQueryWithContext(t, ctx, e, "DROP TEMPORARY TABLE readonly", []sql.Row{}, nil, nil)
+	ctx.SetReadOnly(false)
+
+	// DROP TABLE drops the temporary table first
+	TestQueryWithContext(t, ctx, e, "DROP TABLE scratch", []sql.Row{}, nil, nil)
+	require.Equal(0, ctx.TemporaryTables().Len())
+	TestQueryWithContext(t, other, e, "SELECT i FROM mytable ORDER BY i", []sql.Row{{int64(1)}, {int64(2)}}, nil, nil)
+}
+
 func TestExplode(t *testing.T, harness Harness) {
 	db := harness.NewDatabase("mydb")
 	table, err := harness.NewTable(db, "t", sql.Schema{
@@ -862,6 +1020,514 @@ func TestStoredProcedures(t *testing.T, harness Harness) {
 	for _, script := range ProcedureShowStatus {
 		TestScript(t, harness, script)
 	}
+	for _, script := range ProcedureShowCreate {
+		TestScript(t, harness, script)
+	}
+}
+
+// TestStatementStartTime tests that the engine records the start of every statement run with a context from the
+// system clock, not the one of NOW(), and that the statements run by a stored procedure don't restart it.
+func TestStatementStartTime(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE PROCEDURE p1() BEGIN SET @a = SLEEP(0.1); SET @b = NOW(); END")
+
+	clock := time.Unix(100, 0)
+	ctx := NewContext(harness)
+	ctx.ApplyOpts(sql.WithNowFunc(func() time.Time {
+		return clock
+	}))
+	queryTime := ctx.QueryTime()
+
+	run := func(query string) (time.Time, time.Time) {
+		before := time.Now()
+		_, iter, err := e.Query(ctx, query)
+		require.NoError(err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(err)
+		return before, time.Now()
+	}
+
+	before, after := run("SELECT 1")
+	start := ctx.StatementStartTime()
+	require.False(start.Before(before))
+	require.False(start.After(after))
+	before, _ = run("SELECT 2")
+	require.False(ctx.StatementStartTime().Before(before))
+
+	// The statements of the procedure run after the sleep, and would restart the statement at least 100ms later
+	before, after = run("CALL p1()")
+	start = ctx.StatementStartTime()
+	require.False(start.Before(before))
+	require.True(start.Before(after.Add(-100 * time.Millisecond)))
+	require.Equal(queryTime, ctx.QueryTime())
+}
+
+func TestStatementTimeout(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE t (i BIGINT PRIMARY KEY)")
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (1)")
+
+	run := func(e *sqle.Engine, ctx *sql.Context, query string) error {
+		_, iter, err := e.Query(ctx, query)
+		if err != nil {
+			return err
+		}
+		_, err = sql.RowIterToRows(ctx, iter)
+		if err != nil {
+			_ = iter.Close(ctx)
+		}
+		return err
+	}
+
+	ctx := NewContext(harness)
+	start := time.Now()
+	err := run(e, ctx, "SELECT /*+ MAX_EXECUTION_TIME(10) */ SLEEP(5)")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	require.True(time.Since(start) < 5*time.Second)
+
+	// The hint is taken from the parsed statement, after any leading comment or common table expressions
+	err = run(e, ctx, "/* comment */ SELECT /*+ MAX_EXECUTION_TIME(10) */ SLEEP(5)")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	err = run(e, ctx, "WITH c AS (SELECT i FROM t) SELECT /*+ MAX_EXECUTION_TIME(10) */ SLEEP(5) FROM c")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	err = run(e, ctx, "SELECT /*+ MAX_EXECUTION_TIME(10) */ SLEEP(5) FROM t FOR SHARE")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+
+	RunQuery(t, e, harness, "SET max_execution_time = 10")
+	err = run(e, ctx, "SELECT SLEEP(5) FROM t")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	err = run(e, ctx, "/* a comment */ SELECT SLEEP(5) FROM t FOR UPDATE")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	err = run(e, ctx, "WITH c AS (SELECT i FROM t) SELECT SLEEP(5) FROM c")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+
+	// The optimizer hint overrides the session variable
+	require.NoError(run(e, ctx, "SELECT /*+ MAX_EXECUTION_TIME(5000) */ SLEEP(0.05)"))
+
+	// Only SELECT statements have a timeout
+	require.NoError(run(e, ctx, "UPDATE t SET i = i + SLEEP(0.05)"))
+	RunQuery(t, e, harness, "SET max_execution_time = 0")
+	require.NoError(run(e, ctx, "SELECT SLEEP(0.05)"))
+
+	// Without max_execution_time, the default of the engine applies
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(harness.NewDatabase("otherdb"))
+	e = sqle.New(catalog, analyzer.NewDefault(catalog), &sqle.Config{MaxExecutionTime: 10 * time.Millisecond})
+	err = run(e, NewContext(harness), "SELECT SLEEP(5)")
+	require.True(sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+}
+
+// TestSlowQueryHandler tests that the statements running for longer than long_query_time are given to the
+// SlowQueryHandler of the engine.
+func TestSlowQueryHandler(t *testing.T, harness Harness) {
+	require := require.New(t)
+
+	var slow []sqle.SlowQuery
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(harness.NewDatabase("mydb"))
+	e := sqle.New(catalog, analyzer.NewDefault(catalog), &sqle.Config{
+		SlowQueryHandler: func(ctx *sql.Context, query sqle.SlowQuery) {
+			require.NotNil(ctx.Session)
+			slow = append(slow, query)
+		},
+	})
+	ctx := NewContext(harness)
+	run := func(query string) {
+		_, iter, err := e.Query(ctx, query)
+		require.NoError(err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(err)
+	}
+
+	run("CREATE TABLE t (i BIGINT PRIMARY KEY, v BIGINT)")
+	run("INSERT INTO t VALUES (1, 1), (2, 2), (3, 3)")
+	require.Empty(slow)
+
+	run("SET long_query_time = 0.05")
+	run("SELECT i, SLEEP(0.04) FROM t WHERE v > 1")
+	run("SELECT * FROM t")
+	require.Len(slow, 1)
+	require.Equal("SELECT i, SLEEP(0.04) FROM t WHERE v > 1", slow[0].Query)
+	require.True(slow[0].Duration > 50*time.Millisecond, "unexpected duration %v", slow[0].Duration)
+	require.Equal(int64(3), slow[0].RowsExamined)
+	require.Equal(int64(2), slow[0].RowsSent)
+
+	// With a threshold of 0 every statement is slow, except for administrative ones
+	slow = nil
+	run("SET long_query_time = 0")
+	run("SELECT 1")
+	run("CREATE INDEX idx ON t (v)")
+	require.Len(slow, 2)
+	require.Equal("SET long_query_time = 0", slow[0].Query)
+	require.Equal("SELECT 1", slow[1].Query)
+}
+
+// TestQueryMulti tests running a string of several statements with QueryMulti.
+func TestQueryMulti(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	ctx := NewContext(harness)
+
+	results, err := e.QueryMulti(ctx, `CREATE TABLE t (pk BIGINT PRIMARY KEY, v VARCHAR(20));
+		INSERT INTO t VALUES (1, 'a;b'), (2, "-- c");
+		/* a comment; with a semicolon */ SELECT pk, v FROM t ORDER BY pk;
+		SET @x = 2; SELECT v FROM t WHERE pk = @x;`)
+	require.NoError(err)
+	require.Len(results, 5)
+	require.Equal("INSERT INTO t VALUES (1, 'a;b'), (2, \"-- c\")", results[1].Query)
+	require.Equal([]sql.Row{{sql.NewOkResult(2)}}, results[1].Rows)
+	require.Equal([]sql.Row{{int64(1), "a;b"}, {int64(2), "-- c"}}, results[2].Rows)
+	require.Equal("v", results[4].Schema[0].Name)
+	require.Equal([]sql.Row{{"-- c"}}, results[4].Rows)
+	for _, result := range results {
+		require.NoError(result.Err)
+	}
+
+	// It stops at the first statement that fails
+	results, err = e.QueryMulti(ctx, "INSERT INTO t VALUES (3, 'c'); SELECT * FROM missing; INSERT INTO t VALUES (4, 'd')")
+	require.True(sql.ErrTableNotFound.Is(err), "unexpected error %v", err)
+	require.Len(results, 2)
+	require.NoError(results[0].Err)
+	require.Equal(err, results[1].Err)
+
+	// Unless it's told to continue
+	results, err = e.QueryMulti(ctx, "SELECT * FROM missing; INSERT INTO t VALUES (4, 'd'); SELECT COUNT(*) FROM t",
+		sqle.ContinueOnError())
+	require.NoError(err)
+	require.Len(results, 3)
+	require.True(sql.ErrTableNotFound.Is(results[0].Err))
+	require.NoError(results[1].Err)
+	require.Equal([]sql.Row{{int64(4)}}, results[2].Rows)
+}
+
+// TestStatusVariables tests the status counters of sessions and the server, and their reset with FLUSH STATUS.
+func TestStatusVariables(t *testing.T, harness Harness) {
+	require := require.New(t)
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(harness.NewDatabase("mydb"))
+	e := sqle.New(catalog, analyzer.NewDefault(catalog), new(sqle.Config))
+	ctx := NewContext(harness)
+	run := func(query string) {
+		_, iter, err := e.Query(ctx, query)
+		require.NoError(err)
+		_, err = sql.RowIterToRows(ctx, iter)
+		require.NoError(err)
+	}
+
+	run("CREATE TABLE t (i BIGINT PRIMARY KEY)")
+	run("INSERT INTO t VALUES (1), (2)")
+	run("SELECT * FROM t")
+	run("/* a comment */ SELECT 1")
+	run("SELECT 1 UNION SELECT 2")
+	run("UPDATE t SET i = 3 WHERE i = 2")
+	run("DELETE FROM t WHERE i = 3")
+	require.Equal(int64(7), ctx.Status().Get(sql.StatusQuestions))
+	require.Equal(int64(3), ctx.Status().Get(sql.StatusComSelect))
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusComInsert))
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusComUpdate))
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusComDelete))
+
+	// FLUSH STATUS resets the counters of the session, but not the global ones
+	run("FLUSH STATUS")
+	for _, v := range ctx.Status().All() {
+		require.Equal(int64(0), v.Value, v.Name)
+	}
+	require.Equal(int64(8), e.Catalog.GlobalStatus.Get(sql.StatusQuestions))
+	require.Equal(int64(3), e.Catalog.GlobalStatus.Get(sql.StatusComSelect))
+
+	run("SELECT * FROM t")
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusQuestions))
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusComSelect))
+
+	e.Catalog.GlobalStatus.Reset()
+	require.Equal(int64(0), e.Catalog.GlobalStatus.Get(sql.StatusQuestions))
+	require.Equal(int64(1), ctx.Status().Get(sql.StatusQuestions))
+}
+
+// TestDialect tests the behaviors that depend on the dialect of the session.
+func TestDialect(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	ctx := NewContext(harness)
+	assertNotFound := func(query string) {
+		_, iter, err := e.Query(ctx, query)
+		if err == nil {
+			_, err = sql.RowIterToRows(ctx, iter)
+		}
+		require.True(sql.ErrFunctionNotFound.Is(err), "unexpected error %v", err)
+	}
+
+	TestQueryWithContext(t, ctx, e, "SELECT LENGTH(UUID_TO_BIN(UUID()))", []sql.Row{{16}}, nil, nil)
+	assertNotFound("SELECT NVL(NULL, 1)")
+
+	ctx.Session.SetDialect(sql.Dialect_MariaDB)
+	TestQueryWithContext(t, ctx, e, "SELECT NVL(NULL, 1), NVL(2, 1)", []sql.Row{{1, 2}}, nil, nil)
+	assertNotFound("SELECT UUID_TO_BIN(UUID())")
+	TestQueryWithContext(t, ctx, e, "SELECT VERSION(), @@version, @@version_comment",
+		[]sql.Row{{"10.5.9-MariaDB", "10.5.9-MariaDB", "mariadb.org binary distribution"}}, nil, nil)
+	require.Equal(sql.Dialect_MariaDB, ctx.Dialect())
+}
+
+// TestStrictFoundRows tests the value of FOUND_ROWS() before a session issues any SELECT, with and without strict
+// semantics, and after it does.
+func TestStrictFoundRows(t *testing.T, harness Harness) {
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE t (pk BIGINT PRIMARY KEY)")
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (1), (2), (3)")
+
+	ctx := NewContext(harness)
+	TestQueryWithContext(t, ctx, e, "SELECT FOUND_ROWS()", []sql.Row{{int64(1)}}, nil, nil)
+
+	ctx = NewContext(harness)
+	sess, ok := ctx.Session.(sql.StrictFoundRowsSession)
+	require.True(t, ok)
+	sess.SetStrictFoundRows(true)
+	TestQueryWithContext(t, ctx, e, "SELECT FOUND_ROWS()", []sql.Row{{int64(0)}}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "SELECT * FROM t", []sql.Row{{int64(1)}, {int64(2)}, {int64(3)}}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "SELECT FOUND_ROWS()", []sql.Row{{int64(3)}}, nil, nil)
+}
+
+// TestExplicitTransaction tests that contexts report whether their statements run in a transaction started by BEGIN
+// or START TRANSACTION, including the statements of stored procedures.
+func TestExplicitTransaction(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE t (pk BIGINT PRIMARY KEY)")
+	RunQuery(t, e, harness, "CREATE PROCEDURE p() BEGIN INSERT INTO t VALUES (10); COMMIT; END")
+
+	require.False(NewContext(harness).InExplicitTransaction())
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (1)")
+	require.False(NewContext(harness).InExplicitTransaction())
+
+	RunQuery(t, e, harness, "BEGIN")
+	require.True(NewContext(harness).InExplicitTransaction())
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (2)")
+	require.True(NewContext(harness).InExplicitTransaction())
+	RunQuery(t, e, harness, "COMMIT")
+	require.False(NewContext(harness).InExplicitTransaction())
+
+	RunQuery(t, e, harness, "START TRANSACTION")
+	require.True(NewContext(harness).InExplicitTransaction())
+	RunQuery(t, e, harness, "ROLLBACK")
+	require.False(NewContext(harness).InExplicitTransaction())
+
+	// the statements of a procedure run in the transaction of its caller, so a COMMIT in its body ends it
+	RunQuery(t, e, harness, "BEGIN")
+	RunQuery(t, e, harness, "CALL p()")
+	require.False(NewContext(harness).InExplicitTransaction())
+}
+
+// TestSessionReset tests that resetting a session drops its transient state, as COM_RESET_CONNECTION does, while
+// keeping its identity.
+func TestSessionReset(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE t (pk BIGINT PRIMARY KEY)")
+
+	ctx := NewContext(harness)
+	sess := ctx.Session
+	id, client := sess.ID(), sess.Client()
+	RunQuery(t, e, harness, "CREATE TEMPORARY TABLE tmp (pk BIGINT PRIMARY KEY)")
+	RunQuery(t, e, harness, "SET @v = 1")
+	RunQuery(t, e, harness, "SET sql_mode = 'NO_BACKSLASH_ESCAPES'")
+	RunQuery(t, e, harness, "SELECT GET_LOCK('l', 0)")
+	RunQuery(t, e, harness, "BEGIN")
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (1)")
+	sess.Warn(&sql.Warning{Level: "Warning", Code: 1})
+	require.True(sess.InExplicitTransaction())
+	require.False(sess.PendingChanges().Empty())
+
+	_, err := e.LS.ReleaseAll(ctx)
+	require.NoError(err)
+	require.NoError(sess.Reset(ctx))
+
+	require.Equal(id, sess.ID())
+	require.Equal(client, sess.Client())
+	require.Equal("mydb", sess.GetCurrentDatabase())
+	require.False(sess.InExplicitTransaction())
+	require.True(sess.PendingChanges().Empty())
+	require.Equal(uint16(0), sess.WarningCount())
+	require.Equal(0, sess.TemporaryTables().Len())
+	require.NoError(sess.IterLocks(func(name string) error {
+		return fmt.Errorf("lock %s still held", name)
+	}))
+	require.Empty(sess.OverriddenVariables())
+
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT @v, @@sql_mode", []sql.Row{{nil, ""}}, nil, nil)
+	TestQueryWithContext(t, NewContext(harness), e, "SELECT IS_FREE_LOCK('l')", []sql.Row{{int8(1)}}, nil, nil)
+	AssertErr(t, e, harness, "SELECT * FROM tmp", sql.ErrTableNotFound)
+}
+
+// TestAmbiguousUpdateColumns tests that a multi-table UPDATE referencing a column present in more than one of its
+// tables without qualifying it is rejected, while a qualified reference is accepted.
+func TestAmbiguousUpdateColumns(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE a (pk BIGINT PRIMARY KEY, c BIGINT, x BIGINT)")
+	RunQuery(t, e, harness, "CREATE TABLE b (pk BIGINT PRIMARY KEY, c BIGINT)")
+
+	for _, q := range []string{
+		"UPDATE a JOIN b ON a.pk = b.pk SET c = 1",
+		"UPDATE a, b SET c = 1 WHERE a.pk = b.pk",
+		"UPDATE a JOIN b ON a.pk = b.pk SET a.c = c",
+	} {
+		_, err := e.AnalyzeQuery(NewContext(harness), q)
+		require.True(sql.ErrAmbiguousColumnName.Is(err), "unexpected error %v for %s", err, q)
+		require.Contains(err.Error(), `"c"`)
+		require.Contains(err.Error(), "a, b")
+		sqlErr, _ := sql.CastSQLError(err)
+		require.Equal(mysql.ERNonUniq, sqlErr.Number())
+	}
+
+	for _, q := range []string{
+		"UPDATE a JOIN b ON a.pk = b.pk SET a.c = 1",
+		"UPDATE a JOIN b ON a.pk = b.pk SET a.c = b.c",
+		"UPDATE a, b SET b.c = a.c WHERE a.pk = b.pk",
+		"UPDATE a JOIN b ON a.pk = b.pk SET x = 1",
+		"UPDATE a SET c = 1",
+	} {
+		_, err := e.AnalyzeQuery(NewContext(harness), q)
+		require.NoError(err, q)
+	}
+}
+
+type changeSetRecorder []sql.ChangeSet
+
+func (r *changeSetRecorder) TransactionCommitted(ctx *sql.Context, changes sql.ChangeSet) {
+	*r = append(*r, changes)
+}
+
+// TestBatchDelete tests that Engine.BatchDelete deletes the matching rows in batches until none remain, committing
+// the table's database between the batches if asked to.
+func TestBatchDelete(t *testing.T, harness Harness) {
+	require := require.New(t)
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE `select` (pk BIGINT PRIMARY KEY, v BIGINT)")
+	RunQuery(t, e, harness, "INSERT INTO `select` VALUES (1, 1), (2, 2), (3, 3), (4, 4), (5, 5), (6, 6), (7, 7), (8, 8), (9, 9), (10, 10)")
+
+	require.NoError(e.Catalog.CommitTransaction(NewContext(harness), "mydb"))
+	var changes changeSetRecorder
+	e.Catalog.AddChangeListener(&changes)
+
+	// the table's database is committed, even when it isn't the current one
+	ctx := NewContext(harness)
+	ctx.SetCurrentDatabase("")
+	deleted, err := e.BatchDelete(ctx, "mydb", "select", "v <= 7", 3, sqle.CommitBetweenBatches())
+	ctx.SetCurrentDatabase("mydb")
+	require.NoError(err)
+	require.Equal(int64(7), deleted)
+	require.Len(changes, 3)
+	for i, rows := range []int{3, 3, 1} {
+		require.Equal([]sql.TableChange{{Database: "mydb", Table: "select", RowsAffected: rows}}, changes[i].Tables)
+	}
+	TestQuery(t, harness, e, "SELECT pk FROM `select` ORDER BY pk", []sql.Row{{int64(8)}, {int64(9)}, {int64(10)}}, nil, nil)
+
+	// nothing left to delete
+	deleted, err = e.BatchDelete(NewContext(harness), "", "select", "v <= 7", 3)
+	require.NoError(err)
+	require.Equal(int64(0), deleted)
+
+	// a batch that deletes exactly the batch size is followed by an empty one, which commits no changes
+	deleted, err = e.BatchDelete(NewContext(harness), "", "select", "", 3, sqle.CommitBetweenBatches())
+	require.NoError(err)
+	require.Equal(int64(3), deleted)
+	require.Len(changes, 4)
+	TestQuery(t, harness, e, "SELECT COUNT(*) FROM `select`", []sql.Row{{int64(0)}}, nil, nil)
+
+	_, err = e.BatchDelete(NewContext(harness), "", "select", "", 0)
+	require.True(sqle.ErrInvalidBatchSize.Is(err), "unexpected error %v", err)
+
+	// cancelling the context stops before the next batch
+	RunQuery(t, e, harness, "INSERT INTO `select` VALUES (1, 1), (2, 2), (3, 3)")
+	cancelled, cancel := context.WithCancel(context.Background())
+	e.Catalog.AddChangeListener(cancelOnCommit(cancel))
+	deleted, err = e.BatchDelete(NewContext(harness).WithContext(cancelled), "", "select", "", 1, sqle.CommitBetweenBatches())
+	require.Equal(context.Canceled, err)
+	require.Equal(int64(1), deleted)
+	TestQuery(t, harness, e, "SELECT COUNT(*) FROM `select`", []sql.Row{{int64(2)}}, nil, nil)
+}
+
+// cancelOnCommit is a change listener calling its function for every transaction committed.
+type cancelOnCommit context.CancelFunc
+
+func (c cancelOnCommit) TransactionCommitted(ctx *sql.Context, changes sql.ChangeSet) {
+	c()
+}
+
+// TestStoredProcedureResultSets tests that a CALL exposes the result set of every SELECT run by the procedure,
+// including the ones run by the procedures it calls.
+func TestStoredProcedureResultSets(t *testing.T, harness Harness) {
+	e := NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+	RunQuery(t, e, harness, "CREATE TABLE t (pk BIGINT PRIMARY KEY, v BIGINT)")
+	RunQuery(t, e, harness, "INSERT INTO t VALUES (1, 10), (2, 20)")
+	RunQuery(t, e, harness, "CREATE PROCEDURE p1() SELECT pk FROM t ORDER BY pk")
+	RunQuery(t, e, harness, `CREATE PROCEDURE p2() BEGIN
+	SELECT pk FROM t ORDER BY pk;
+	UPDATE t SET v = v + 1;
+	SELECT v FROM t ORDER BY pk;
+END`)
+	RunQuery(t, e, harness, "CREATE PROCEDURE p3() BEGIN UPDATE t SET v = v + 1; END")
+	RunQuery(t, e, harness, "CREATE PROCEDURE p4() BEGIN CALL p1(); SELECT v FROM t ORDER BY pk; END")
+	RunQuery(t, e, harness, "CREATE PROCEDURE p5() BEGIN CALL p2(); CALL p3(); END")
+
+	callResultSets := func(t *testing.T, query string) ([]sql.Row, []plan.ResultSet) {
+		require := require.New(t)
+		ctx := NewContext(harness)
+		_, iter, err := e.Query(ctx, query)
+		require.NoError(err)
+		rows, err := sql.RowIterToRows(ctx, iter)
+		require.NoError(err)
+		rsIter, ok := iter.(plan.ResultSetsRowIter)
+		require.True(ok)
+		return rows, rsIter.ResultSets()
+	}
+
+	t.Run("procedure ending in a SELECT", func(t *testing.T) {
+		require := require.New(t)
+		rows, resultSets := callResultSets(t, "CALL p1()")
+		require.Equal([]sql.Row{{int64(1)}, {int64(2)}}, rows)
+		require.Nil(resultSets)
+	})
+
+	t.Run("procedure producing two result sets", func(t *testing.T) {
+		require := require.New(t)
+		rows, resultSets := callResultSets(t, "CALL p2()")
+		require.Equal([]sql.Row{{int64(11)}, {int64(21)}}, rows)
+		require.Len(resultSets, 2)
+		require.Equal("pk", resultSets[0].Schema[0].Name)
+		require.Equal([]sql.Row{{int64(1)}, {int64(2)}}, resultSets[0].Rows)
+		require.Equal("v", resultSets[1].Schema[0].Name)
+		require.Equal([]sql.Row{{int64(11)}, {int64(21)}}, resultSets[1].Rows)
+	})
+
+	t.Run("procedure with no SELECT", func(t *testing.T) {
+		require := require.New(t)
+		rows, resultSets := callResultSets(t, "CALL p3()")
+		require.Len(rows, 1)
+		require.Equal(uint64(2), rows[0][0].(sql.OkResult).RowsAffected)
+		require.Nil(resultSets)
+	})
+
+	t.Run("procedure calling a procedure ending in a SELECT", func(t *testing.T) {
+		require := require.New(t)
+		rows, resultSets := callResultSets(t, "CALL p4()")
+		require.Equal([]sql.Row{{int64(12)}, {int64(22)}}, rows)
+		require.Len(resultSets, 2)
+		require.Equal("pk", resultSets[0].Schema[0].Name)
+		require.Equal([]sql.Row{{int64(1)}, {int64(2)}}, resultSets[0].Rows)
+		require.Equal([]sql.Row{{int64(12)}, {int64(22)}}, resultSets[1].Rows)
+	})
+
+	t.Run("procedure calling a procedure producing two result sets", func(t *testing.T) {
+		require := require.New(t)
+		rows, resultSets := callResultSets(t, "CALL p5()")
+		require.Equal([]sql.Row{{int64(13)}, {int64(23)}}, rows)
+		require.Len(resultSets, 2)
+		require.Equal([]sql.Row{{int64(1)}, {int64(2)}}, resultSets[0].Rows)
+		require.Equal([]sql.Row{{int64(13)}, {int64(23)}}, resultSets[1].Rows)
+	})
 }
 
 func TestTriggerErrors(t *testing.T, harness Harness) {
@@ -2151,9 +2817,9 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS
 			`,
 			Expected: []sql.Row{
-				{"", 3, ""},
-				{"", 2, ""},
-				{"", 1, ""},
+				{"", uint32(3), ""},
+				{"", uint32(2), ""},
+				{"", uint32(1), ""},
 			},
 		},
 		{
@@ -2161,7 +2827,7 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS LIMIT 1
 			`,
 			Expected: []sql.Row{
-				{"", 3, ""},
+				{"", uint32(3), ""},
 			},
 		},
 		{
@@ -2169,8 +2835,8 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS LIMIT 1,2
 			`,
 			Expected: []sql.Row{
-				{"", 2, ""},
-				{"", 1, ""},
+				{"", uint32(2), ""},
+				{"", uint32(1), ""},
 			},
 		},
 		{
@@ -2178,9 +2844,9 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS LIMIT 0
 			`,
 			Expected: []sql.Row{
-				{"", 3, ""},
-				{"", 2, ""},
-				{"", 1, ""},
+				{"", uint32(3), ""},
+				{"", uint32(2), ""},
+				{"", uint32(1), ""},
 			},
 		},
 		{
@@ -2188,7 +2854,7 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS LIMIT 2,0
 			`,
 			Expected: []sql.Row{
-				{"", 1, ""},
+				{"", uint32(1), ""},
 			},
 		},
 		{
@@ -2196,9 +2862,9 @@ func TestWarnings(t *testing.T, harness Harness) {
 			SHOW WARNINGS LIMIT 10
 			`,
 			Expected: []sql.Row{
-				{"", 3, ""},
-				{"", 2, ""},
-				{"", 1, ""},
+				{"", uint32(3), ""},
+				{"", uint32(2), ""},
+				{"", uint32(1), ""},
 			},
 		},
 		{
@@ -2306,11 +2972,30 @@ func TestSessionSelectLimit(t *testing.T, harness Harness) {
 			Query:    "SELECT i FROM (SELECT i FROM mytable LIMIT 2) t ORDER BY i",
 			Expected: []sql.Row{{int64(1)}},
 		},
-		// TODO: this is broken: the session limit is applying inappropriately to the subquery
-		// {
-		// 	"SELECT i FROM (SELECT i FROM mytable ORDER BY i DESC) t ORDER BY i LIMIT 2",
-		// 	[]sql.Row{{int64(1)}},
-		// },
+		{
+			Query:    "SELECT i FROM (SELECT i FROM mytable ORDER BY i DESC) t ORDER BY i LIMIT 2",
+			Expected: []sql.Row{{int64(1)}, {int64(2)}},
+		},
+		{
+			Query:    "SELECT i FROM (SELECT i FROM mytable ORDER BY i DESC) t ORDER BY i",
+			Expected: []sql.Row{{int64(1)}},
+		},
+		{
+			Query:    "SELECT i FROM mytable WHERE i IN (SELECT i FROM mytable) ORDER BY i",
+			Expected: []sql.Row{{int64(1)}},
+		},
+		{
+			Query:    "WITH t AS (SELECT i FROM mytable) SELECT i FROM t ORDER BY i",
+			Expected: []sql.Row{{int64(1)}},
+		},
+		{
+			Query:    "SELECT i FROM mytable UNION SELECT i + 10 FROM mytable",
+			Expected: []sql.Row{{int64(1)}},
+		},
+		{
+			Query:    "SELECT COUNT(*) FROM (SELECT i FROM mytable) t",
+			Expected: []sql.Row{{int64(3)}},
+		},
 	}
 
 	e := NewEngine(t, harness)
@@ -2322,6 +3007,10 @@ func TestSessionSelectLimit(t *testing.T, harness Harness) {
 	for _, tt := range q {
 		TestQueryWithContext(t, ctx, e, tt.Query, tt.Expected, nil, tt.Bindings)
 	}
+
+	// the limit doesn't apply to the rows read by DML statements
+	TestQueryWithContext(t, ctx, e, "INSERT INTO mytable (i, s) SELECT i + 10, s FROM mytable", []sql.Row{{sql.NewOkResult(3)}}, nil, nil)
+	TestQueryWithContext(t, ctx, e, "SELECT COUNT(*) FROM mytable", []sql.Row{{int64(6)}}, nil, nil)
 }
 
 func TestTracing(t *testing.T, harness Harness) {
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/memory"
 	"github.com/dolthub/go-mysql-server/sql"
@@ -307,6 +309,54 @@ func TestStoredProcedures(t *testing.T) {
 	enginetest.TestStoredProcedures(t, enginetest.NewDefaultMemoryHarness())
 }
 
+func TestStatementStartTime(t *testing.T) {
+	enginetest.TestStatementStartTime(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestStatementTimeout(t *testing.T) {
+	enginetest.TestStatementTimeout(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestSlowQueryHandler(t *testing.T) {
+	enginetest.TestSlowQueryHandler(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestQueryMulti(t *testing.T) {
+	enginetest.TestQueryMulti(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestStatusVariables(t *testing.T) {
+	enginetest.TestStatusVariables(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestDialect(t *testing.T) {
+	enginetest.TestDialect(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestStrictFoundRows(t *testing.T) {
+	enginetest.TestStrictFoundRows(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestExplicitTransaction(t *testing.T) {
+	enginetest.TestExplicitTransaction(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestSessionReset(t *testing.T) {
+	enginetest.TestSessionReset(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestBatchDelete(t *testing.T) {
+	enginetest.TestBatchDelete(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestAmbiguousUpdateColumns(t *testing.T) {
+	enginetest.TestAmbiguousUpdateColumns(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestStoredProcedureResultSets(t *testing.T) {
+	enginetest.TestStoredProcedureResultSets(t, enginetest.NewDefaultMemoryHarness())
+}
+
 func TestTriggersErrors(t *testing.T) {
 	enginetest.TestTriggerErrors(t, enginetest.NewDefaultMemoryHarness())
 }
@@ -379,6 +429,18 @@ func TestReadOnly(t *testing.T) {
 	enginetest.TestReadOnly(t, enginetest.NewDefaultMemoryHarness())
 }
 
+func TestPreQueryHook(t *testing.T) {
+	enginetest.TestPreQueryHook(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestTableResolver(t *testing.T) {
+	enginetest.TestTableResolver(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestTemporaryTables(t *testing.T) {
+	enginetest.TestTemporaryTables(t, enginetest.NewDefaultMemoryHarness())
+}
+
 func TestViews(t *testing.T) {
 	enginetest.TestViews(t, enginetest.NewDefaultMemoryHarness())
 }
@@ -415,6 +477,205 @@ func TestShowTableStatus(t *testing.T) {
 	enginetest.TestShowTableStatus(t, enginetest.NewDefaultMemoryHarness())
 }
 
+func TestLockingReads(t *testing.T) {
+	require := require.New(t)
+	harness := enginetest.NewDefaultMemoryHarness()
+
+	db := memory.NewDatabase("mydb")
+	table := memory.NewPartitionedTable("t", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "t", PrimaryKey: true},
+	}, testNumPartitions)
+	for i := int64(1); i <= 4; i++ {
+		require.NoError(table.Insert(sql.NewEmptyContext(), sql.NewRow(i)))
+	}
+	locking := &lockingTable{Table: table, locked: map[int64]bool{2: true}}
+	db.AddTable("t", locking)
+	e := enginetest.NewEngineWithDbs(t, harness, []sql.Database{db}, nil)
+
+	query := func(q string) ([]sql.Row, error) {
+		locking.types = nil
+		locking.modes = nil
+		ctx := enginetest.NewContext(harness)
+		_, iter, err := e.Query(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(ctx, iter)
+	}
+
+	rows, err := query("SELECT i FROM t ORDER BY i")
+	require.NoError(err)
+	require.Len(rows, 4)
+	require.Empty(locking.modes)
+
+	rows, err = query("SELECT i FROM t ORDER BY i FOR UPDATE")
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}}, rows)
+	require.Len(locking.modes, 4)
+	require.Equal(sql.LockType_Exclusive, locking.types[0])
+	require.Equal(sql.LockMode_Wait, locking.modes[0])
+
+	_, err = query("SELECT i FROM t ORDER BY i FOR UPDATE NOWAIT")
+	require.True(sql.ErrLockNowait.Is(err), "unexpected error %v", err)
+
+	rows, err = query("SELECT i FROM t ORDER BY i FOR UPDATE SKIP LOCKED")
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}, {int64(3)}, {int64(4)}}, rows)
+	require.Equal(sql.LockMode_SkipLocked, locking.modes[0])
+
+	// Locked rows are skipped before the limit is applied
+	rows, err = query("SELECT i FROM t ORDER BY i LIMIT 2 FOR UPDATE SKIP LOCKED")
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}, {int64(3)}}, rows)
+
+	// Shared locks reach the table as such, with any lock mode
+	rows, err = query("SELECT i FROM t ORDER BY i LOCK IN SHARE MODE")
+	require.NoError(err)
+	require.Len(rows, 4)
+	require.Equal(sql.LockType_Shared, locking.types[0])
+	require.Equal(sql.LockMode_Wait, locking.modes[0])
+
+	rows, err = query("SELECT i FROM t ORDER BY i FOR SHARE SKIP LOCKED")
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1)}, {int64(3)}, {int64(4)}}, rows)
+	require.Equal(sql.LockType_Shared, locking.types[0])
+
+	_, err = query("SELECT i FROM t FOR SHARE NOWAIT")
+	require.True(sql.ErrLockNowait.Is(err), "unexpected error %v", err)
+	require.Equal(sql.LockType_Shared, locking.types[0])
+}
+
+// lockingTable is a sql.LockingTable whose locked rows, by their first column, are held by another transaction.
+type lockingTable struct {
+	*memory.Table
+	locked map[int64]bool
+	types  []sql.LockType
+	modes  []sql.LockMode
+}
+
+var _ sql.LockingTable = (*lockingTable)(nil)
+
+func (t *lockingTable) LockRow(ctx *sql.Context, row sql.Row, typ sql.LockType, mode sql.LockMode) (bool, error) {
+	t.types = append(t.types, typ)
+	t.modes = append(t.modes, mode)
+	if !t.locked[row[0].(int64)] {
+		return true, nil
+	}
+	switch mode {
+	case sql.LockMode_NoWait:
+		return false, sql.ErrLockNowait.New()
+	case sql.LockMode_SkipLocked:
+		return false, nil
+	default:
+		// the other transaction is assumed to release its lock
+		return true, nil
+	}
+}
+
+func TestFlushPrivileges(t *testing.T) {
+	require := require.New(t)
+	harness := enginetest.NewDefaultMemoryHarness()
+	e := enginetest.NewEngineWithDbs(t, harness, []sql.Database{harness.NewDatabase("mydb")}, nil)
+
+	// FLUSH PRIVILEGES does nothing without a reloader
+	enginetest.RunQuery(t, e, harness, "FLUSH PRIVILEGES")
+
+	reloader := &grantReloader{
+		storage: map[string]bool{"root": true},
+		grants:  map[string]bool{},
+	}
+	e.Catalog.PrivilegeReloader = reloader
+
+	enginetest.RunQuery(t, e, harness, "FLUSH PRIVILEGES")
+	require.Equal(map[string]bool{"root": true}, reloader.grants)
+
+	// a grant made directly to the storage takes effect once reloaded
+	reloader.storage["app"] = true
+	require.False(reloader.grants["app"])
+	enginetest.RunQuery(t, e, harness, "FLUSH LOCAL PRIVILEGES")
+	require.Equal(map[string]bool{"root": true, "app": true}, reloader.grants)
+
+	// a failed reload keeps the grants from before
+	reloader.storage["broken"] = true
+	reloader.fail = true
+	enginetest.AssertErr(t, e, harness, "FLUSH PRIVILEGES", nil)
+	require.Equal(map[string]bool{"root": true, "app": true}, reloader.grants)
+}
+
+// grantReloader is a sql.PrivilegeReloader whose grants are the users with any privilege, cached from its storage.
+type grantReloader struct {
+	storage map[string]bool
+	grants  map[string]bool
+	fail    bool
+}
+
+var _ sql.PrivilegeReloader = (*grantReloader)(nil)
+
+func (r *grantReloader) Reload(*sql.Context) error {
+	grants := make(map[string]bool, len(r.storage))
+	for user := range r.storage {
+		if r.fail && user == "broken" {
+			return fmt.Errorf("cannot read the grants of %s", user)
+		}
+		grants[user] = true
+	}
+	r.grants = grants
+	return nil
+}
+
+func TestDefaultStorageEngine(t *testing.T) {
+	require := require.New(t)
+	harness := enginetest.NewDefaultMemoryHarness()
+	db := &engineDatabase{Database: memory.NewDatabase("mydb"), engines: map[string]string{}}
+	e := enginetest.NewEngineWithDbs(t, harness, []sql.Database{db}, nil)
+	e.Catalog.StorageEngines = append(e.Catalog.StorageEngines, sql.Engine{Name: "MyISAM"})
+	defer enginetest.RunQuery(t, e, harness, "SET default_storage_engine = 'InnoDB'")
+
+	warnings := func() []int {
+		var codes []int
+		for _, w := range enginetest.NewContext(harness).Session.Warnings() {
+			codes = append(codes, w.Code)
+		}
+		return codes
+	}
+
+	enginetest.RunQuery(t, e, harness, "CREATE TABLE t1 (pk BIGINT PRIMARY KEY)")
+	require.Equal("InnoDB", db.engines["t1"])
+	require.Empty(warnings())
+
+	enginetest.RunQuery(t, e, harness, "SET default_storage_engine = 'myisam'")
+	enginetest.RunQuery(t, e, harness, "CREATE TABLE t2 (pk BIGINT PRIMARY KEY)")
+	require.Equal("MyISAM", db.engines["t2"])
+	enginetest.RunQuery(t, e, harness, "CREATE TABLE t3 (pk BIGINT PRIMARY KEY) ENGINE=InnoDB")
+	require.Equal("InnoDB", db.engines["t3"])
+
+	// an unknown engine falls back to the default of the session
+	enginetest.RunQuery(t, e, harness, "CREATE TABLE t4 (pk BIGINT PRIMARY KEY) ENGINE=Archive")
+	require.Equal("MyISAM", db.engines["t4"])
+	require.Equal([]int{1266, 1286}, warnings())
+
+	// the default can only be set to a supported engine
+	enginetest.AssertErr(t, e, harness, "SET default_storage_engine = 'nosuch'", sql.ErrUnknownStorageEngine)
+	enginetest.RunQuery(t, e, harness, "CREATE TABLE t5 (pk BIGINT PRIMARY KEY)")
+	require.Equal("MyISAM", db.engines["t5"])
+}
+
+// engineDatabase is a memory database that records the storage engine of the tables it creates.
+type engineDatabase struct {
+	*memory.Database
+	engines map[string]string
+}
+
+var _ sql.EngineTableCreator = (*engineDatabase)(nil)
+
+func (d *engineDatabase) CreateTableWithEngine(ctx *sql.Context, name string, schema sql.Schema, engine string) error {
+	if err := d.CreateTable(ctx, name, schema); err != nil {
+		return err
+	}
+	d.engines[name] = engine
+	return nil
+}
+
 func unmergableIndexDriver(dbs []sql.Database) sql.IndexDriver {
 	return memory.NewIndexDriver("mydb", map[string][]sql.DriverIndex{
 		"mytable": {
@@ -883,6 +883,73 @@ var ProcedureCallTests = []ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "SQL SECURITY context",
+		SetUpScript: []string{
+			"CREATE definer=bob PROCEDURE pd(OUT u TEXT) SQL SECURITY DEFINER SET u = CURRENT_USER()",
+			"CREATE definer=bob PROCEDURE pi(OUT u TEXT) SQL SECURITY INVOKER SET u = CURRENT_USER()",
+			"CREATE definer=alice PROCEDURE pdi(OUT u TEXT) SQL SECURITY DEFINER CALL pi(u)",
+			"CREATE definer=alice PROCEDURE pdd(OUT u TEXT) SQL SECURITY DEFINER CALL pd(u)",
+			"CREATE definer=alice PROCEDURE pnested() SQL SECURITY DEFINER BEGIN CALL pd(@inner); SET @outer = CURRENT_USER(); END;",
+			"CREATE definer=bob PROCEDURE pusers() SQL SECURITY DEFINER SELECT USER(), CURRENT_USER()",
+			"CREATE definer=bob PROCEDURE perr() SQL SECURITY DEFINER BEGIN SET @during = CURRENT_USER(); SIGNAL SQLSTATE '45000'; END;",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "CALL pd(@u)",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT @u",
+				Expected: []sql.Row{{"bob"}},
+			},
+			{
+				Query:    "CALL pi(@u)",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT @u",
+				Expected: []sql.Row{{"user"}},
+			},
+			{
+				// an invoker procedure called by a definer procedure runs with the privileges of the outer definer
+				Query:    "CALL pdi(@u)",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT @u",
+				Expected: []sql.Row{{"alice"}},
+			},
+			{
+				Query:    "CALL pdd(@u)",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT @u",
+				Expected: []sql.Row{{"bob"}},
+			},
+			{
+				Query:    "CALL pnested()",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT @inner, @outer",
+				Expected: []sql.Row{{"bob", "alice"}},
+			},
+			{
+				Query:    "CALL pusers()",
+				Expected: []sql.Row{{"user", "bob"}},
+			},
+			{
+				Query:          "CALL perr()",
+				ExpectedErrStr: "Unhandled user-defined exception condition (errno 1644) (sqlstate 45000)",
+			},
+			{
+				Query:    "SELECT @during, CURRENT_USER()",
+				Expected: []sql.Row{{"bob", "user"}},
+			},
+		},
+	},
 }
 
 var ProcedureDropTests = []ScriptTest{
@@ -1121,3 +1188,45 @@ var ProcedureShowStatus = []ScriptTest{
 		},
 	},
 }
+
+var ProcedureShowCreate = []ScriptTest{
+	{
+		Name: "SHOW CREATE PROCEDURE",
+		SetUpScript: []string{
+			"CREATE DEFINER=`user` PROCEDURE p1(IN a BIGINT, OUT b VARCHAR(20), INOUT c DOUBLE) DETERMINISTIC SQL SECURITY INVOKER COMMENT 'doubles c' BEGIN SET b = CONCAT(a, ''); SET c = c * 2; END",
+			"CREATE PROCEDURE p2() SELECT 7",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query: "SHOW CREATE PROCEDURE p1",
+				Expected: []sql.Row{
+					{
+						"p1", // Procedure
+						"",   // sql_mode
+						"CREATE DEFINER=`user` PROCEDURE p1(IN a BIGINT, OUT b VARCHAR(20), INOUT c DOUBLE) DETERMINISTIC SQL SECURITY INVOKER COMMENT 'doubles c' BEGIN SET b = CONCAT(a, ''); SET c = c * 2; END", // Create Procedure
+						"utf8mb4",            // character_set_client
+						"utf8mb4_0900_ai_ci", // collation_connection
+						"utf8mb4_0900_ai_ci", // Database Collation
+					},
+				},
+			},
+			{
+				Query: "SHOW CREATE PROCEDURE mydb.P2",
+				Expected: []sql.Row{
+					{
+						"p2",                             // Procedure
+						"",                               // sql_mode
+						"CREATE PROCEDURE p2() SELECT 7", // Create Procedure
+						"utf8mb4",                        // character_set_client
+						"utf8mb4_0900_ai_ci",             // collation_connection
+						"utf8mb4_0900_ai_ci",             // Database Collation
+					},
+				},
+			},
+			{
+				Query:       "SHOW CREATE PROCEDURE p3",
+				ExpectedErr: sql.ErrStoredProcedureDoesNotExist,
+			},
+		},
+	},
+}
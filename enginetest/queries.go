@@ -183,6 +183,15 @@ var QueryTests = []QueryTest{
 			{1, 50.0},
 		},
 	},
+	{
+		Query: "SELECT i2, COUNT(*), SUM(i) FROM (SELECT i, i2 FROM niltable ORDER BY i2 DESC) sq GROUP BY i2 ORDER BY i2",
+		Expected: []sql.Row{
+			{nil, int64(3), 9.0},
+			{int64(2), int64(1), 2.0},
+			{int64(4), int64(1), 4.0},
+			{int64(6), int64(1), 6.0},
+		},
+	},
 	{
 		Query:    "select max(pk),c2 from one_pk group by c1 order by 1",
 		Expected: []sql.Row{{0, 1}, {1, 11}, {2, 21}, {3, 31}},
@@ -2316,6 +2325,7 @@ var QueryTests = []QueryTest{
 		Expected: []sql.Row{
 			{"autocommit", int64(0)},
 			{"auto_increment_increment", int64(1)},
+			{"auto_increment_offset", int64(1)},
 			{"time_zone", "SYSTEM"},
 			{"system_time_zone", time.Now().UTC().Location().String()},
 			{"max_allowed_packet", math.MaxInt32},
@@ -2325,8 +2335,8 @@ var QueryTests = []QueryTest{
 			{"ndbinfo_version", ""},
 			{"sql_select_limit", math.MaxInt32},
 			{"transaction_isolation", "READ UNCOMMITTED"},
-			{"version", ""},
-			{"version_comment", ""},
+			{"version", "8.0.11"},
+			{"version_comment", "MySQL Community Server - GPL"},
 			{"character_set_client", sql.Collation_Default.CharacterSet().String()},
 			{"character_set_connection", sql.Collation_Default.CharacterSet().String()},
 			{"character_set_results", sql.Collation_Default.CharacterSet().String()},
@@ -2334,6 +2344,17 @@ var QueryTests = []QueryTest{
 			{"tmpdir", sql.GetTmpdirSessionVar()},
 			{"local_infile", int8(0)},
 			{"secure_file_priv", nil},
+			{"read_only", int8(0)},
+			{"super_read_only", int8(0)},
+			{"optimizer_switch", sql.DefaultOptimizerSwitch()},
+			{"max_execution_time", int64(0)},
+			{"wait_timeout", int64(28800)},
+			{"interactive_timeout", int64(28800)},
+			{"max_prepared_stmt_count", int64(16382)},
+			{"default_storage_engine", "InnoDB"},
+			{"binlog_format", "ROW"},
+			{"sql_safe_updates", int8(0)},
+			{"long_query_time", float64(10)},
 		},
 	},
 	{
@@ -3215,6 +3236,10 @@ var QueryTests = []QueryTest{
 		Query:    `SELECT NOW() - (NOW() - INTERVAL 1 SECOND)`,
 		Expected: []sql.Row{{int64(1)}},
 	},
+	{
+		Query:    `SELECT SYSDATE() >= NOW()`,
+		Expected: []sql.Row{{true}},
+	},
 	{
 		Query:    `SELECT SUBSTR(SUBSTRING('0123456789ABCDEF', 1, 10), -4)`,
 		Expected: []sql.Row{{"6789"}},
@@ -606,4 +606,487 @@ var ScriptTests = []ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "foreign key cascade actions",
+		SetUpScript: []string{
+			"create table parent (id int primary key, v int)",
+			"create table child (id int primary key, parent_id int, constraint fk_child foreign key (parent_id) references parent (id) on delete cascade on update cascade)",
+			"create table grandchild (id int primary key, child_id int, constraint fk_grandchild foreign key (child_id) references child (id) on delete cascade)",
+			"create table nullchild (id int primary key, parent_id int, constraint fk_nullchild foreign key (parent_id) references parent (id) on delete set null on update set null)",
+			"insert into parent values (1, 1), (2, 2), (3, 3)",
+			"insert into child values (10, 1), (20, 2), (30, null)",
+			"insert into grandchild values (100, 10), (200, 20)",
+			"insert into nullchild values (1, 1), (2, 2)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "delete from parent where id = 1",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from child order by id",
+				Expected: []sql.Row{{20, 2}, {30, nil}},
+			},
+			{
+				Query:    "select * from grandchild order by id",
+				Expected: []sql.Row{{200, 20}},
+			},
+			{
+				Query:    "select * from nullchild order by id",
+				Expected: []sql.Row{{1, nil}, {2, 2}},
+			},
+			{
+				Query:    "update parent set id = 5 where id = 2",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "select * from child order by id",
+				Expected: []sql.Row{{20, 5}, {30, nil}},
+			},
+			{
+				Query:    "select * from nullchild order by id",
+				Expected: []sql.Row{{1, nil}, {2, nil}},
+			},
+			{
+				Query:    "update parent set v = 50 where id = 5",
+				Expected: []sql.Row{{sql.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+			},
+			{
+				Query:    "select * from child order by id",
+				Expected: []sql.Row{{20, 5}, {30, nil}},
+			},
+		},
+	},
+	{
+		Name: "foreign key restrict actions",
+		SetUpScript: []string{
+			"create table parent (id int primary key)",
+			"create table child (id int primary key, parent_id int, constraint fk_child foreign key (parent_id) references parent (id) on delete restrict)",
+			"insert into parent values (1), (2)",
+			"insert into child values (10, 1)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "delete from parent where id = 1",
+				ExpectedErr: sql.ErrForeignKeyChildViolation,
+			},
+			{
+				Query:       "update parent set id = 3 where id = 1",
+				ExpectedErr: sql.ErrForeignKeyChildViolation,
+			},
+			{
+				Query:    "delete from parent where id = 2",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from parent",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "set foreign_key_checks = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "delete from parent where id = 1",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "set foreign_key_checks = 1",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "foreign key cascade depth",
+		SetUpScript: []string{
+			"create table chain (id int primary key, prev int, constraint fk_chain foreign key (prev) references chain (id) on delete cascade)",
+			"insert into chain values (1, null), (2, 1), (3, 2), (4, 3), (5, 4), (6, 5), (7, 6), (8, 7), (9, 8), (10, 9), (11, 10), (12, 11), (13, 12), (14, 13), (15, 14), (16, 15), (17, 16), (18, 17), (19, 18), (20, 19)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "delete from chain where id = 1",
+				ExpectedErr: sql.ErrForeignKeyDepthExceeded,
+			},
+			{
+				Query:    "select count(*) from chain",
+				Expected: []sql.Row{{20}},
+			},
+			{
+				Query:    "delete from chain where id = 15",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select count(*) from chain",
+				Expected: []sql.Row{{14}},
+			},
+		},
+	},
+	{
+		Name: "read-only session",
+		SetUpScript: []string{
+			"create table ro (id int primary key, v int)",
+			"insert into ro values (1, 1)",
+			"set global read_only = 1",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "update ro set v = 2 where id = 1",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:       "insert into ro values (2, 2)",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:       "delete from ro",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:       "create table ro2 (id int primary key)",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:    "select * from ro",
+				Expected: []sql.Row{{1, 1}},
+			},
+			{
+				Query:       "set read_only = 0",
+				ExpectedErr: sql.ErrGlobalOnlySystemVariable,
+			},
+			{
+				Query:       "set session super_read_only = 0",
+				ExpectedErr: sql.ErrGlobalOnlySystemVariable,
+			},
+			{
+				Query:    "set @@global.read_only = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "update ro set v = 2 where id = 1",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+		},
+	},
+	{
+		Name: "read-only session with temporary tables",
+		SetUpScript: []string{
+			"create table ro (id int primary key, v int)",
+			"set global read_only = 1",
+			"create temporary table tmp (id int primary key, v int)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "insert into tmp values (1, 1), (2, 2)",
+				Expected: []sql.Row{{sql.NewOkResult(2)}},
+			},
+			{
+				Query:    "update tmp set v = 3 where id = 1",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "delete from tmp where id = 2",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from tmp",
+				Expected: []sql.Row{{1, 3}},
+			},
+			{
+				Query:       "insert into ro values (1, 1)",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:       "insert into ro select * from tmp",
+				ExpectedErr: sql.ErrReadOnlyTransaction,
+			},
+			{
+				Query:    "set @@global.read_only = 0",
+				Expected: []sql.Row{{}},
+			},
+		},
+	},
+	{
+		Name: "safe updates mode",
+		SetUpScript: []string{
+			"create table safe (id int primary key, v int, w int)",
+			"insert into safe values (1, 1, 1), (2, 2, 2), (3, 3, 3)",
+			"set sql_safe_updates = 1",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "update safe set v = 0",
+				ExpectedErr: sql.ErrUpdateWithoutKeyInSafeMode,
+			},
+			{
+				Query:       "update safe set v = 0 where w = 1",
+				ExpectedErr: sql.ErrUpdateWithoutKeyInSafeMode,
+			},
+			{
+				Query:       "delete from safe",
+				ExpectedErr: sql.ErrUpdateWithoutKeyInSafeMode,
+			},
+			{
+				Query:       "delete from safe where w > 1",
+				ExpectedErr: sql.ErrUpdateWithoutKeyInSafeMode,
+			},
+			{
+				Query:    "update safe set v = 10 where id = 1",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "update safe set v = 20 where w = 2 limit 1",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "delete from safe where id = 3",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from safe order by id",
+				Expected: []sql.Row{{1, 10, 1}, {2, 20, 2}},
+			},
+			{
+				Query:    "set sql_safe_updates = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "update safe set v = 0",
+				Expected: []sql.Row{{newUpdateResult(2, 2)}},
+			},
+		},
+	},
+	{
+		Name: "NO_BACKSLASH_ESCAPES sql_mode",
+		SetUpScript: []string{
+			"create table esc (s varchar(20))",
+			`insert into esc values ('a\\b'), ('a_b'), ('a\\_b')`,
+			"set sql_mode = 'NO_BACKSLASH_ESCAPES'",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    `select 'a\nb', length('a\nb'), 'c\'`,
+				Expected: []sql.Row{{`a\nb`, int32(4), `c\`}},
+			},
+			{
+				Query:    `select s from esc where s = 'a\b'`,
+				Expected: []sql.Row{{`a\b`}},
+			},
+			{
+				Query:    `select s from esc where s like 'a\_b' order by s`,
+				Expected: []sql.Row{{`a\_b`}},
+			},
+			{
+				Query:    "set sql_mode = ''",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    `select 'a\nb', length('a\nb')`,
+				Expected: []sql.Row{{"a\nb", int32(3)}},
+			},
+			{
+				Query:    `select s from esc where s like 'a\\_b' order by s`,
+				Expected: []sql.Row{{"a_b"}},
+			},
+		},
+	},
+	{
+		Name: "COLLATE overrides",
+		SetUpScript: []string{
+			"create table coll (id int primary key, v varchar(20) collate utf8mb4_0900_ai_ci, b varchar(20) collate utf8mb4_bin)",
+			"insert into coll values (1, 'a', 'a'), (2, 'B', 'B'), (3, 'c', 'c')",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select id from coll where b = 'A'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select id from coll where b collate utf8mb4_0900_ai_ci = 'A'",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select id from coll where b collate utf8mb4_0900_ai_ci = 'Á'",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "select id from coll where b collate utf8mb4_0900_as_cs = 'á'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select id from coll where b = 'b' collate utf8mb4_general_ci",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "select id from coll where v collate utf8mb4_bin = 'b'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select v from coll order by v collate utf8mb4_bin",
+				Expected: []sql.Row{{"B"}, {"a"}, {"c"}},
+			},
+			{
+				Query:    "select b from coll order by b collate utf8mb4_0900_ai_ci desc",
+				Expected: []sql.Row{{"c"}, {"B"}, {"a"}},
+			},
+			{
+				Query:       "select id from coll where v collate latin1_swedish_ci = 'a'",
+				ExpectedErr: sql.ErrCollationCharsetMismatch,
+			},
+			{
+				Query:       "select id from coll where v = 'a' collate latin1_swedish_ci",
+				ExpectedErr: sql.ErrCollationCharsetMismatch,
+			},
+			{
+				Query:       "select id from coll where v collate no_such_collation = 'a'",
+				ExpectedErr: sql.ErrCollationNotSupported,
+			},
+			{
+				Query:       "select id from coll where v collate utf8mb4_bin = b collate utf8mb4_0900_ai_ci",
+				ExpectedErr: sql.ErrIllegalMixOfCollations,
+			},
+			{
+				Query:       "select id from coll where 'a' collate utf8mb4_bin = 'A' collate utf8mb4_general_ci",
+				ExpectedErr: sql.ErrIllegalMixOfCollations,
+			},
+			{
+				Query:    "select id from coll where v collate utf8mb4_bin = b collate utf8mb4_bin",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+			{
+				Query:    "select id from coll where id collate utf8mb4_0900_ai_ci < 10",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+		},
+	},
+	{
+		Name: "update with correlated subquery",
+		SetUpScript: []string{
+			"create table target (id int primary key, v varchar(20))",
+			"create table source (id int, v varchar(20))",
+			"insert into target values (1, 'a'), (2, 'b'), (3, 'c')",
+			"insert into source values (1, 'x'), (2, 'y'), (2, 'z')",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "update target set v = (select source.v from source where source.id = target.id) where id <> 2",
+				Expected: []sql.Row{{newUpdateResult(2, 2)}},
+			},
+			{
+				Query:    "select * from target order by id",
+				Expected: []sql.Row{{1, "x"}, {2, "b"}, {3, nil}},
+			},
+			{
+				Query:       "update target set v = (select source.v from source where source.id = target.id) where id = 2",
+				ExpectedErr: sql.ErrExpectedSingleRow,
+			},
+			{
+				Query:    "update target set v = (select max(source.v) from source where source.id = target.id) where id = 2",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "select * from target order by id",
+				Expected: []sql.Row{{1, "x"}, {2, "z"}, {3, nil}},
+			},
+		},
+	},
+	{
+		Name: "update validates the new rows against the schema",
+		SetUpScript: []string{
+			"create table validated (id int primary key, v varchar(3) not null, n int)",
+			"insert into validated values (1, 'a', 1), (2, 'b', 2)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "update validated set v = null where id = 2",
+				ExpectedErr: sql.ErrColumnNotNullable,
+			},
+			{
+				Query:       "update validated set v = 'abcd' where id = 2",
+				ExpectedErr: sql.ErrLengthBeyondLimit,
+			},
+			{
+				Query:    "update validated set n = '3' where id = 2",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "select * from validated order by id",
+				Expected: []sql.Row{{1, "a", 1}, {2, "b", 3}},
+			},
+		},
+	},
+	{
+		Name: "insert on duplicate key update counts inserted, updated and unchanged rows",
+		SetUpScript: []string{
+			"create table dupkeys (pk int primary key, v int, n int)",
+			"insert into dupkeys values (1, 1, 0), (2, 2, 0)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "insert into dupkeys values (3, 3, 0) on duplicate key update n = n + 1",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "insert into dupkeys values (1, 10, 0) on duplicate key update v = values(v), n = n + 1",
+				Expected: []sql.Row{{sql.NewOkResult(2)}},
+			},
+			{
+				Query:    "insert into dupkeys values (2, 2, 5) on duplicate key update v = values(v)",
+				Expected: []sql.Row{{sql.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into dupkeys values (2, 20, 0), (3, 30, 0), (4, 4, 0) on duplicate key update v = values(v)",
+				Expected: []sql.Row{{sql.NewOkResult(5)}},
+			},
+			{
+				Query:    "select * from dupkeys order by pk",
+				Expected: []sql.Row{{1, 10, 1}, {2, 20, 0}, {3, 30, 0}, {4, 4, 0}},
+			},
+		},
+	},
+	{
+		Name: "auto_increment_increment and auto_increment_offset",
+		SetUpScript: []string{
+			"create table steps (pk int primary key auto_increment, v int)",
+			"set auto_increment_increment = 10",
+			"set auto_increment_offset = 5",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "insert into steps (v) values (1), (2)",
+				Expected: []sql.Row{{sql.NewOkResult(2)}},
+			},
+			{
+				Query:    "insert into steps values (27, 3)",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "insert into steps (v) values (4)",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "set auto_increment_offset = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "insert into steps (v) values (5)",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from steps order by pk",
+				Expected: []sql.Row{{5, 1}, {15, 2}, {27, 3}, {35, 4}, {41, 5}},
+			},
+			{
+				Query:    "set auto_increment_increment = 1",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "insert into steps (v) values (6)",
+				Expected: []sql.Row{{sql.NewOkResult(1)}},
+			},
+			{
+				Query:    "select max(pk) from steps",
+				Expected: []sql.Row{{42}},
+			},
+		},
+	},
 }
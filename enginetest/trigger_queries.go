@@ -1138,6 +1138,83 @@ end;`,
 			},
 		},
 	},
+	{
+		Name: "update triggers with signal",
+		SetUpScript: []string{
+			"create table a (x int primary key, y int)",
+			"create table b (y int primary key)",
+			"insert into a values (1, 1), (2, 2)",
+			`create trigger before_signal before update on a for each row
+begin
+	declare cond_name condition for sqlstate '45000';
+	if new.y = 5 then signal cond_name set message_text = 'before err';
+	end if;
+end;`,
+			`create trigger after_signal after update on a for each row
+begin
+	declare cond_name condition for sqlstate '45000';
+	if new.y = 6 then signal cond_name set message_text = 'after err';
+	end if;
+	insert into b values (new.y);
+end;`,
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:          "update a set y = 5 where x = 2",
+				ExpectedErrStr: "before err (errno 1644) (sqlstate 45000)",
+			},
+			{
+				Query:          "update a set y = 6 where x = 2",
+				ExpectedErrStr: "after err (errno 1644) (sqlstate 45000)",
+			},
+			{
+				Query: "update a set y = 3 where x = 2",
+				Expected: []sql.Row{
+					{sql.OkResult{
+						RowsAffected: 1,
+						Info: plan.UpdateInfo{
+							Matched: 1,
+							Updated: 1,
+						},
+					}},
+				},
+			},
+			{
+				Query: "select y from b order by 1",
+				Expected: []sql.Row{
+					{3},
+				},
+			},
+		},
+	},
+	{
+		Name: "trigger before update, set new value to old value is a no-op",
+		SetUpScript: []string{
+			"create table a (x int primary key, y int)",
+			"insert into a values (1, 1), (2, 2)",
+			"create trigger keep_y before update on a for each row set new.y = old.y",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query: "update a set y = y + 10",
+				Expected: []sql.Row{
+					{sql.OkResult{
+						RowsAffected: 0,
+						Info: plan.UpdateInfo{
+							Matched: 2,
+							Updated: 0,
+						},
+					}},
+				},
+			},
+			{
+				Query: "select x, y from a order by 1",
+				Expected: []sql.Row{
+					{1, 1}, {2, 2},
+				},
+			},
+		},
+	},
 	// Information schema scripts
 	{
 		Name: "infoschema for multiple triggers before and after insert, with precedes / follows",
@@ -78,6 +78,24 @@ var UpdateTests = []WriteQueryTest{
 		SelectQuery:         "SELECT i,b FROM niltable WHERE f IS NULL;",
 		ExpectedSelect:      []sql.Row{{int64(1), nil}, {int64(2), nil}, {int64(3), nil}},
 	},
+	{
+		WriteQuery:          "UPDATE niltable SET b = NULL WHERE b IS NULL;",
+		ExpectedWriteResult: []sql.Row{{newUpdateResult(2, 0)}},
+		SelectQuery:         "SELECT i,b FROM niltable WHERE b IS NULL ORDER BY i;",
+		ExpectedSelect:      []sql.Row{{int64(1), nil}, {int64(4), nil}},
+	},
+	{
+		WriteQuery:          "UPDATE niltable SET i2 = NULL WHERE i <= 2;",
+		ExpectedWriteResult: []sql.Row{{newUpdateResult(2, 1)}},
+		SelectQuery:         "SELECT i,i2 FROM niltable WHERE i <= 2 ORDER BY i;",
+		ExpectedSelect:      []sql.Row{{int64(1), nil}, {int64(2), nil}},
+	},
+	{
+		WriteQuery:          "UPDATE niltable SET i2 = 1 WHERE i <= 2;",
+		ExpectedWriteResult: []sql.Row{{newUpdateResult(2, 2)}},
+		SelectQuery:         "SELECT i,i2 FROM niltable WHERE i <= 2 ORDER BY i;",
+		ExpectedSelect:      []sql.Row{{int64(1), int64(1)}, {int64(2), int64(1)}},
+	},
 	{
 		WriteQuery:          "UPDATE mytable SET s = 'updated' ORDER BY i ASC LIMIT 2;",
 		ExpectedWriteResult: []sql.Row{{newUpdateResult(2, 2)}},
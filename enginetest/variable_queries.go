@@ -16,6 +16,7 @@ package enginetest
 
 import (
 	"math"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql"
 )
@@ -59,7 +60,7 @@ var VariableQueries = []ScriptTest{
 		},
 		Query: "SELECT @@autocommit, @@session.sql_mode",
 		Expected: []sql.Row{
-			{1, 0},
+			{1, "0"},
 		},
 	},
 	{
@@ -69,7 +70,7 @@ var VariableQueries = []ScriptTest{
 		},
 		Query: "SELECT @@autocommit, @@session.sql_mode",
 		Expected: []sql.Row{
-			{1, 0},
+			{1, "0"},
 		},
 	},
 	{
@@ -79,7 +80,7 @@ var VariableQueries = []ScriptTest{
 		},
 		Query: "SELECT @@autocommit, @@session.sql_mode",
 		Expected: []sql.Row{
-			{1, 0},
+			{1, "0"},
 		},
 	},
 	{
@@ -114,6 +115,16 @@ var VariableQueries = []ScriptTest{
 			{"utf8mb4", "utf8mb4", "utf8mb4"},
 		},
 	},
+	{
+		Name: "set character_set_results to NULL",
+		SetUpScript: []string{
+			`set character_set_results = NULL`,
+		},
+		Query: "SELECT @@character_set_client, @@character_set_results",
+		Expected: []sql.Row{
+			{"utf8mb4", nil},
+		},
+	},
 	// TODO: we should validate the character set here
 	{
 		Name: "set names quoted",
@@ -209,6 +220,18 @@ var VariableQueries = []ScriptTest{
 			{1234, 1234},
 		},
 	},
+	{
+		Name: "set optimizer_switch keeps the flags not listed",
+		SetUpScript: []string{
+			`set optimizer_switch = 'index_merge=off'`,
+			`set optimizer_switch = 'HASH_JOIN=off, skip_scan=default'`,
+		},
+		Query: "SELECT @@optimizer_switch",
+		Expected: []sql.Row{
+			{strings.Replace(strings.Replace(sql.DefaultOptimizerSwitch(),
+				"index_merge=on,", "index_merge=off,", 1), "hash_join=on", "hash_join=off", 1)},
+		},
+	},
 }
 
 var VariableErrorTests = []QueryErrorTest{
@@ -222,4 +245,12 @@ var VariableErrorTests = []QueryErrorTest{
 		Query:       "set @myvar = bareword",
 		ExpectedErr: sql.ErrColumnNotFound,
 	},
+	{
+		Query:       "set optimizer_switch = 'index_merge=off,no_such_flag=on'",
+		ExpectedErr: sql.ErrUnknownOptimizerFlag,
+	},
+	{
+		Query:       "set optimizer_switch = 'index_merge'",
+		ExpectedErr: sql.ErrInvalidOptimizerSwitch,
+	},
 }
@@ -144,6 +144,15 @@ func (d *Database) CreateTable(ctx *sql.Context, name string, schema sql.Schema)
 	return nil
 }
 
+// CreateTemporaryTable implements the sql.TemporaryTableCreator interface.
+func (d *Database) CreateTemporaryTable(ctx *sql.Context, name string, schema sql.Schema) (sql.Table, error) {
+	table := NewTable(name, schema)
+	if d.primaryKeyIndexes {
+		table.EnablePrimaryKeyIndexes()
+	}
+	return table, nil
+}
+
 // DropTable drops the table with the given name
 func (d *Database) DropTable(ctx *sql.Context, name string) error {
 	_, ok := d.tables[name]
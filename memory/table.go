@@ -71,6 +71,7 @@ var _ sql.CheckAlterableTable = (*Table)(nil)
 var _ sql.CheckTable = (*Table)(nil)
 var _ sql.AutoIncrementTable = (*Table)(nil)
 var _ sql.StatisticsTable = (*Table)(nil)
+var _ sql.PrimaryKeyTable = (*Table)(nil)
 
 // PushdownTable is an extension to Table that implements sql.FilteredTable and sql.ProjectedTable. This is mostly just
 // for demonstration and testing purposes -- these new interfaces do not significantly speed up query execution.
@@ -228,6 +229,40 @@ func (t *Table) NumRows(ctx *sql.Context) (uint64, error) {
 	return count, nil
 }
 
+// GetRowByKey implements the sql.PrimaryKeyTable interface.
+func (t *Table) GetRowByKey(ctx *sql.Context, key sql.Row) (sql.Row, bool, error) {
+	var pkIdxs []int
+	for i, col := range t.schema {
+		if col.PrimaryKey {
+			pkIdxs = append(pkIdxs, i)
+		}
+	}
+	if len(pkIdxs) == 0 || len(pkIdxs) != len(key) {
+		return nil, false, nil
+	}
+
+	for _, rows := range t.partitions {
+	rowLoop:
+		for _, row := range rows {
+			for j, idx := range pkIdxs {
+				if row[idx] == nil || key[j] == nil {
+					continue rowLoop
+				}
+				cmp, err := t.schema[idx].Type.Compare(row[idx], key[j])
+				if err != nil {
+					return nil, false, err
+				}
+				if cmp != 0 {
+					continue rowLoop
+				}
+			}
+			return projectOnRow(t.columns, row), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
 func (t *Table) DataLength(ctx *sql.Context) (uint64, error) {
 	var numBytesPerRow uint64 = 0
 	for _, col := range t.schema {
@@ -1098,7 +1133,7 @@ func (t *Table) GetForeignKeys(_ *sql.Context) ([]sql.ForeignKeyConstraint, erro
 	return t.foreignKeys, nil
 }
 
-// CreateForeignKey implements sql.ForeignKeyAlterableTable. Foreign keys are not enforced on update / delete.
+// CreateForeignKey implements sql.ForeignKeyAlterableTable. Foreign keys are not enforced on insert.
 func (t *Table) CreateForeignKey(_ *sql.Context, fkName string, columns []string, referencedTable string, referencedColumns []string, onUpdate, onDelete sql.ForeignKeyReferenceOption) error {
 	for _, key := range t.foreignKeys {
 		if key.Name == fkName {
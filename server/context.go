@@ -31,7 +31,10 @@ type SessionBuilder func(ctx context.Context, conn *mysql.Conn, addr string) (sq
 // it can be disposed.
 type DoneFunc func()
 
-// DefaultSessionBuilder is a SessionBuilder that returns a base session.
+// DefaultSessionBuilder is a SessionBuilder that returns a base session. The connection doesn't keep the connection
+// attributes or the CLIENT_INTERACTIVE flag sent by the client, so the client of the session has no attributes and
+// isn't interactive; builders that get them from elsewhere can set them with SetClientAttributes and
+// SetClientInteractive.
 func DefaultSessionBuilder(ctx context.Context, c *mysql.Conn, addr string) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error) {
 	client := c.RemoteAddr().String()
 	return sql.NewSession(addr, client, c.User, c.ConnectionID), sql.NewIndexRegistry(), sql.NewViewRegistry(), nil
@@ -51,6 +54,8 @@ type SessionManager struct {
 	idxRegs   map[uint32]*sql.IndexRegistry
 	viewRegs  map[uint32]*sql.ViewRegistry
 	pid       uint64
+	// the store of the temporary files of the queries, or nil for the default store of their context
+	spillStore sql.SpillStore
 }
 
 // NewSessionManager creates a SessionManager with the given SessionBuilder.
@@ -114,6 +119,13 @@ func (s *SessionManager) session(conn *mysql.Conn) sql.Session {
 	return s.sessions[conn.ConnectionID]
 }
 
+// sessionByID returns the session of the connection with the ID given, or nil if there is none.
+func (s *SessionManager) sessionByID(connID uint32) sql.Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions[connID]
+}
+
 // NewContext creates a new context for the session at the given conn.
 func (s *SessionManager) NewContext(conn *mysql.Conn) (*sql.Context, error) {
 	return s.NewContextWithQuery(conn, "")
@@ -160,16 +172,21 @@ func (s *SessionManager) NewContextWithQuery(conn *mysql.Conn, query string) (*s
 		sql.WithRootSpan(s.tracer.StartSpan("query")),
 		sql.WithIndexRegistry(ir),
 		sql.WithViewRegistry(vr),
+		sql.WithSpillStore(s.spillStore),
 	)
 
 	return context, nil
 }
 
 // CloseConn closes the connection in the session manager and all its
-// associated contexts, which are cancelled.
+// associated contexts, which are cancelled. The temporary tables of its
+// session are dropped.
 func (s *SessionManager) CloseConn(conn *mysql.Conn) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if sess, ok := s.sessions[conn.ConnectionID]; ok {
+		sess.TemporaryTables().Clear()
+	}
 	delete(s.sessions, conn.ConnectionID)
 	delete(s.idxRegs, conn.ConnectionID)
 	delete(s.viewRegs, conn.ConnectionID)
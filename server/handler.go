@@ -113,24 +113,89 @@ func (h *Handler) ComInitDB(c *mysql.Conn, schemaName string) error {
 	return h.sm.SetDB(c, schemaName)
 }
 
+// ComPrepare prepares the query given, registering it with the session of the connection so that it counts toward
+// max_prepared_stmt_count.
 func (h *Handler) ComPrepare(c *mysql.Conn, query string) ([]*query.Field, error) {
 	ctx, err := h.sm.NewContextWithQuery(c, query)
 	if err != nil {
 		return nil, err
 	}
+
+	// The server closes statements on COM_STMT_CLOSE without telling the handler, so the statements it no longer has
+	// are removed from the session first. The server assigns the statement its ID before calling this method.
+	for id := range ctx.Session.PreparedStatements() {
+		if _, ok := c.PrepareData[id]; !ok {
+			ctx.Session.RemovePreparedStatement(id)
+		}
+	}
+	id := c.StatementID
+	if err := ctx.Session.AddPreparedStatement(id, query); err != nil {
+		return nil, err
+	}
+
 	schema, err := h.e.AnalyzeQuery(ctx, query)
 	if err != nil {
+		ctx.Session.RemovePreparedStatement(id)
 		return nil, err
 	}
-	return schemaToFields(schema), nil
+	return schemaToFields(ctx, schema), nil
 }
 
 func (h *Handler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) error {
-	return h.errorWrappedDoQuery(c, prepare.PrepareStmt, prepare.BindVars, callback)
+	return h.errorWrappedDoQuery(c, prepare.PrepareStmt, prepare.BindVars, lastResultSet(callback))
 }
 
+// ComResetConnection returns the session of the connection to the state of a new one, releasing its named locks and
+// table locks, while keeping its user.
 func (h *Handler) ComResetConnection(c *mysql.Conn) {
-	// TODO: handle reset logic
+	ctx, err := h.sm.NewContext(c)
+	if err != nil {
+		logrus.Errorf("unable to reset connection %d: %s", c.ConnectionID, err)
+		return
+	}
+	h.releaseLocks(ctx, c)
+	if err := ctx.Session.Reset(ctx); err != nil {
+		logrus.Errorf("unable to reset connection %d: %s", c.ConnectionID, err)
+	}
+}
+
+// ChangeUser authenticates the user given with the engine's Auth and, if it succeeds, resets the session of the
+// connection and binds it to that user, as COM_CHANGE_USER does. The auth response is the one of the COM_CHANGE_USER
+// packet, computed by the client from the salt the server sent the connection, which the caller gives along. The
+// session is left as it was if authentication fails. Once changed, the user is the effective user of the statements
+// of the session and the user of its processes in the process list. The server doesn't dispatch COM_CHANGE_USER, so
+// connection layers that support it call this method instead.
+func (h *Handler) ChangeUser(c *mysql.Conn, user string, authResponse, salt []byte) error {
+	if h.e.Auth != nil {
+		if _, err := h.e.Auth.Mysql().ValidateHash(salt, user, authResponse, c.RemoteAddr()); err != nil {
+			return err
+		}
+	}
+
+	ctx, err := h.sm.NewContext(c)
+	if err != nil {
+		return err
+	}
+	h.releaseLocks(ctx, c)
+
+	client := ctx.Session.Client()
+	client.User = user
+	if err := ctx.Session.ChangeUser(ctx, client); err != nil {
+		return err
+	}
+	h.e.Catalog.ProcessList.SetConnectionUser(c.ConnectionID, user)
+	c.User = user
+	return nil
+}
+
+// releaseLocks releases the named locks and the table locks held by the session of the connection.
+func (h *Handler) releaseLocks(ctx *sql.Context, c *mysql.Conn) {
+	if _, err := h.e.LS.ReleaseAll(ctx); err != nil {
+		logrus.Errorf("unable to release named locks of connection %d: %s", c.ConnectionID, err)
+	}
+	if err := h.e.Catalog.UnlockTables(ctx, c.ConnectionID); err != nil {
+		logrus.Errorf("unable to unlock tables of connection %d: %s", c.ConnectionID, err)
+	}
 }
 
 // ConnectionClosed reports that a connection has been closed.
@@ -143,7 +208,7 @@ func (h *Handler) ConnectionClosed(c *mysql.Conn) {
 	h.mu.Unlock()
 
 	// If connection was closed, kill only its associated queries.
-	h.e.Catalog.ProcessList.KillOnlyQueries(c.ConnectionID)
+	h.e.Catalog.ProcessList.ConnectionClosed(c.ConnectionID)
 	if err := h.e.Catalog.UnlockTables(ctx, c.ConnectionID); err != nil {
 		logrus.Errorf("unable to unlock tables on session close: %s", err)
 	}
@@ -151,15 +216,38 @@ func (h *Handler) ConnectionClosed(c *mysql.Conn) {
 	logrus.Infof("ConnectionClosed: client %v", c.ConnectionID)
 }
 
-// ComQuery executes a SQL query on the SQLe engine.
+// ComQuery executes a SQL query on the SQLe engine. The Handler interface of vitess has a single result set per
+// query, so for a CALL running several SELECT statements only the result set of the last one is given to the
+// callback. See ComQueryResultSets.
 func (h *Handler) ComQuery(
 	c *mysql.Conn,
 	query string,
 	callback func(*sqltypes.Result) error,
+) error {
+	return h.errorWrappedDoQuery(c, query, nil, lastResultSet(callback))
+}
+
+// ComQueryResultSets executes a SQL query like ComQuery, but gives the callback every result set of the query: for a
+// CALL, the result set of every SELECT statement it runs, including the ones run by the procedures it calls. more is
+// true for the results of every result set but the last.
+func (h *Handler) ComQueryResultSets(
+	c *mysql.Conn,
+	query string,
+	callback func(res *sqltypes.Result, more bool) error,
 ) error {
 	return h.errorWrappedDoQuery(c, query, nil, callback)
 }
 
+// lastResultSet returns a callback for doQuery that gives the callback given the results of the last result set only.
+func lastResultSet(callback func(*sqltypes.Result) error) func(*sqltypes.Result, bool) error {
+	return func(r *sqltypes.Result, more bool) error {
+		if more {
+			return nil
+		}
+		return callback(r)
+	}
+}
+
 func bindingsToExprs(bindings map[string]*query.BindVariable) (map[string]sql.Expression, error) {
 	res := make(map[string]sql.Expression, len(bindings))
 	for k, v := range bindings {
@@ -271,7 +359,7 @@ func (h *Handler) doQuery(
 	c *mysql.Conn,
 	query string,
 	bindings map[string]*query.BindVariable,
-	callback func(*sqltypes.Result) error,
+	callback func(*sqltypes.Result, bool) error,
 ) error {
 	logrus.Tracef("received query %s", query)
 
@@ -281,6 +369,13 @@ func (h *Handler) doQuery(
 		return err
 	}
 
+	// KILL QUERY only aborts a single statement: the one running when the session was killed, or the next one
+	defer ctx.ClearKilled()
+	// The session is idle from the end of the statement, not its start
+	defer func() {
+		ctx.SetLastActivity(time.Now())
+	}()
+
 	if !h.e.Async(ctx, query) {
 		newCtx, cancel := context.WithCancel(ctx)
 		ctx = ctx.WithContext(newCtx)
@@ -294,7 +389,7 @@ func (h *Handler) doQuery(
 	}
 
 	if handled {
-		return callback(&sqltypes.Result{})
+		return callback(&sqltypes.Result{}, false)
 	}
 
 	start := time.Now()
@@ -345,6 +440,13 @@ func (h *Handler) doQuery(
 		return ErrConnectionWasClosed.New()
 	}
 
+	// A CALL runs all of its statements before its rows are read, so the result sets before the last one, which its
+	// rows are, are sent first
+	if err = sendEarlierResultSets(ctx, rows, callback); err != nil {
+		_ = rows.Close(ctx)
+		return err
+	}
+
 	var r *sqltypes.Result
 	var proccesedAtLeastOneBatch bool
 
@@ -389,11 +491,11 @@ func (h *Handler) doQuery(
 rowLoop:
 	for {
 		if r == nil {
-			r = &sqltypes.Result{Fields: schemaToFields(schema)}
+			r = &sqltypes.Result{Fields: schemaToFields(ctx, schema)}
 		}
 
 		if r.RowsAffected == rowsBatch {
-			if err := callback(r); err != nil {
+			if err := callback(r, false); err != nil {
 				close(quit)
 				return err
 			}
@@ -453,7 +555,7 @@ rowLoop:
 
 	_, statementIsCommit := parsedQuery.(*sqlparser.Commit)
 	if statementIsCommit || (autoCommit && statementNeedsCommit(parsedQuery, parseErr)) {
-		if err := ctx.Session.CommitTransaction(ctx, getTransactionDbName(ctx)); err != nil {
+		if err := h.e.Catalog.CommitTransaction(ctx, getTransactionDbName(ctx)); err != nil {
 			return err
 		}
 	}
@@ -466,7 +568,36 @@ rowLoop:
 		return nil
 	}
 
-	return callback(r)
+	return callback(r, false)
+}
+
+// sendEarlierResultSets gives the callback the result sets of the row iterator given before the last one, if it has
+// several, with more set.
+func sendEarlierResultSets(ctx *sql.Context, rows sql.RowIter, callback func(*sqltypes.Result, bool) error) error {
+	rsIter, ok := rows.(plan.ResultSetsRowIter)
+	if !ok {
+		return nil
+	}
+	resultSets := rsIter.ResultSets()
+	if len(resultSets) < 2 {
+		return nil
+	}
+
+	for _, rs := range resultSets[:len(resultSets)-1] {
+		r := &sqltypes.Result{Fields: schemaToFields(ctx, rs.Schema)}
+		for _, row := range rs.Rows {
+			outputRow, err := rowToSQL(rs.Schema, row)
+			if err != nil {
+				return err
+			}
+			r.Rows = append(r.Rows, outputRow)
+			r.RowsAffected++
+		}
+		if err := callback(r, true); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Call doQuery and cast known errors to SQLError
@@ -474,7 +605,7 @@ func (h *Handler) errorWrappedDoQuery(
 	c *mysql.Conn,
 	query string,
 	bindings map[string]*query.BindVariable,
-	callback func(*sqltypes.Result) error,
+	callback func(*sqltypes.Result, bool) error,
 ) error {
 	err := h.doQuery(c, query, bindings, callback)
 	err, ok := sql.CastSQLError(err)
@@ -534,16 +665,10 @@ func (h *Handler) pollForClosedConnection(nc conntainer, errChan chan error, qui
 }
 
 func isSessionAutocommit(ctx *sql.Context) bool {
-	typ, autoCommitSessionVar := ctx.Get(sql.AutoCommitSessionVar)
+	_, autoCommitSessionVar := ctx.Get(sql.AutoCommitSessionVar)
 	autoCommit := false
 	if autoCommitSessionVar != nil {
-		switch typ {
-		case sql.Int64:
-			autoCommit = autoCommitSessionVar.(int64) == int64(1)
-		case sql.Boolean:
-			autoCommit, _ = sql.ConvertToBool(autoCommitSessionVar)
-		default:
-		}
+		autoCommit, _ = sql.ConvertToBool(autoCommitSessionVar)
 	}
 	return autoCommit
 }
@@ -605,6 +730,38 @@ func (h *Handler) WarningCount(c *mysql.Conn) uint16 {
 	return 0
 }
 
+// ExpireIdleSessions closes the connections whose sessions have been idle for longer than their wait_timeout at the
+// time given, as checked by sql.IsIdleExpired, and returns their IDs. Connections running a query aren't idle. The
+// server doesn't run it on its own: integrators call it periodically, such as from a time.Ticker.
+func (h *Handler) ExpireIdleSessions(now time.Time) []uint32 {
+	running := make(map[uint32]bool)
+	for _, proc := range h.e.Catalog.ProcessList.Processes() {
+		running[proc.Connection] = true
+	}
+
+	h.mu.Lock()
+	var expired []conntainer
+	for connID, c := range h.c {
+		if running[connID] {
+			continue
+		}
+		if sess := h.sm.sessionByID(connID); sess != nil && sql.IsIdleExpired(sess, now) {
+			expired = append(expired, c)
+			delete(h.c, connID)
+		}
+	}
+	h.mu.Unlock()
+
+	ids := make([]uint32, len(expired))
+	for i, c := range expired {
+		logrus.Infof("closing idle connection: id %d", c.MysqlConn.ConnectionID)
+		ids[i] = c.MysqlConn.ConnectionID
+		h.sm.CloseConn(c.MysqlConn)
+		c.MysqlConn.Close()
+	}
+	return ids
+}
+
 func (h *Handler) handleKill(conn *mysql.Conn, query string) (bool, error) {
 	q := strings.ToLower(query)
 	s := regKillCmd.FindStringSubmatch(q)
@@ -632,6 +789,9 @@ func (h *Handler) handleKill(conn *mysql.Conn, query string) (bool, error) {
 	// It terminates the connection associated with the given processlist_id,
 	// after terminating any statement the connection is executing.
 	connID := uint32(id)
+	if sess := h.sm.sessionByID(connID); sess != nil {
+		sess.MarkKilled()
+	}
 	h.e.Catalog.Kill(connID)
 	if s[1] != "query" {
 		logrus.Infof("kill connection: id %d", connID)
@@ -671,12 +831,19 @@ func rowToSQL(s sql.Schema, row sql.Row) ([]sqltypes.Value, error) {
 	return o, nil
 }
 
-func schemaToFields(s sql.Schema) []*query.Field {
+// schemaToFields returns the fields describing the columns of the schema given to the client. When the session has
+// turned off the conversion of results with character_set_results = NULL, string columns report the collation of the
+// column, as their values are sent in its character set.
+func schemaToFields(ctx *sql.Context, s sql.Schema) []*query.Field {
+	_, converted := ctx.ResultsCharacterSet()
+
 	fields := make([]*query.Field, len(s))
 	for i, c := range s {
 		var charset uint32 = mysql.CharacterSetUtf8
 		if sql.IsBlob(c.Type) {
 			charset = mysql.CharacterSetBinary
+		} else if st, ok := c.Type.(sql.StringType); ok && !converted {
+			charset = uint32(st.Collation().ID())
 		}
 
 		fields[i] = &query.Field{
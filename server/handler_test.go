@@ -17,7 +17,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -28,6 +31,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 )
@@ -205,6 +209,93 @@ func TestHandlerKill(t *testing.T) {
 	assertNoConnProcesses(t, e, conn1.ConnectionID)
 }
 
+func TestHandlerKillMarksSession(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	handler.ComInitDB(conn1, "test")
+	handler.ComInitDB(conn2, "test")
+
+	noop := func(res *sqltypes.Result) error { return nil }
+
+	// KILL QUERY on an idle connection aborts its next statement only
+	sess1 := handler.sm.session(conn1)
+	require.NoError(handler.ComQuery(conn2, "KILL QUERY 1", noop))
+	require.True(sess1.IsKilled())
+
+	err := handler.ComQuery(conn1, "SELECT * FROM test", noop)
+	require.Error(err)
+	require.Equal(mysql.ERQueryInterrupted, err.(*mysql.SQLError).Number())
+	require.False(sess1.IsKilled())
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", noop))
+
+	// KILL CONNECTION also closes the session
+	require.NoError(handler.ComQuery(conn2, "KILL CONNECTION 1", noop))
+	require.True(sess1.IsKilled())
+	require.Nil(handler.sm.session(conn1))
+	require.False(handler.sm.session(conn2).IsKilled())
+}
+
+func TestHandlerTemporaryTables(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	require.NoError(handler.ComInitDB(conn2, "test"))
+
+	var rows int
+	countRows := func(res *sqltypes.Result) error {
+		rows += len(res.Rows)
+		return nil
+	}
+
+	require.NoError(handler.ComQuery(conn1, "CREATE TEMPORARY TABLE test (c1 int)", countRows))
+	rows = 0
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", countRows))
+	require.Equal(0, rows)
+	rows = 0
+	require.NoError(handler.ComQuery(conn2, "SELECT * FROM test", countRows))
+	require.Equal(1010, rows)
+
+	// Closing the connection drops the temporary tables of its session
+	sess1 := handler.sm.session(conn1)
+	require.Equal(1, sess1.TemporaryTables().Len())
+	handler.ConnectionClosed(conn1)
+	require.Equal(0, sess1.TemporaryTables().Len())
+}
+
 func assertNoConnProcesses(t *testing.T, e *sqle.Engine, conn uint32) {
 	t.Helper()
 
@@ -230,7 +321,20 @@ func TestSchemaToFields(t *testing.T) {
 		{Name: "baz", Type: query.Type_INT64, Charset: mysql.CharacterSetUtf8},
 	}
 
-	fields := schemaToFields(schema)
+	ctx := sql.NewEmptyContext()
+	fields := schemaToFields(ctx, schema)
+	require.Equal(expected, fields)
+
+	// Without the conversion of results, string columns report their own collation
+	schema = append(schema, &sql.Column{Name: "qux", Type: sql.MustCreateString(query.Type_VARCHAR, 10, sql.Collation_latin1_swedish_ci)})
+	require.NoError(ctx.Set(ctx, "character_set_results", sql.LongText, nil))
+	expected = []*query.Field{
+		{Name: "foo", Type: query.Type_BLOB, Charset: mysql.CharacterSetBinary},
+		{Name: "bar", Type: query.Type_TEXT, Charset: uint32(sql.Collation_Default.ID())},
+		{Name: "baz", Type: query.Type_INT64, Charset: mysql.CharacterSetUtf8},
+		{Name: "qux", Type: query.Type_VARCHAR, Charset: uint32(sql.Collation_latin1_swedish_ci.ID())},
+	}
+	fields = schemaToFields(ctx, schema)
 	require.Equal(expected, fields)
 }
 
@@ -320,6 +424,425 @@ func TestOkClosedConnection(t *testing.T) {
 	require.NoError(err)
 }
 
+type warningsCommitSession struct {
+	sql.Session
+	committed [][]*sql.Warning
+}
+
+func (s *warningsCommitSession) CommitTransactionWithWarnings(ctx *sql.Context, dbName string, warnings []*sql.Warning) error {
+	s.committed = append(s.committed, warnings)
+	return s.Session.CommitTransaction(ctx, dbName)
+}
+
+func TestHandlerCommitWarnings(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	sess := &warningsCommitSession{Session: sql.NewSession("foo", "127.0.0.1:34567", "", 1)}
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			func(ctx context.Context, conn *mysql.Conn, addr string) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error) {
+				return sess, sql.NewIndexRegistry(), sql.NewViewRegistry(), nil
+			},
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	noopCallback := func(res *sqltypes.Result) error { return nil }
+
+	sess.Warn(&sql.Warning{Level: "Warning", Code: 1, Message: "rolled back"})
+	require.NoError(handler.ComQuery(conn, "ROLLBACK", noopCallback))
+	require.Empty(sess.committed)
+
+	sess.Warn(&sql.Warning{Level: "Warning", Code: 2, Message: "committed"})
+	require.NoError(handler.ComQuery(conn, "COMMIT", noopCallback))
+	require.Len(sess.committed, 1)
+	require.NotEmpty(sess.committed[0])
+	require.Equal(2, sess.committed[0][0].Code)
+}
+
+func TestHandlerChangeUser(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	dir, err := ioutil.TempDir("", "users")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "users.json")
+	require.NoError(ioutil.WriteFile(file, []byte(`[
+		{"name": "user1", "password": "pass1", "permissions": ["read", "write"]},
+		{"name": "user2", "password": "pass2", "permissions": ["read", "write"]}
+	]`), 0644))
+	e.Auth, err = auth.NewNativeFile(file)
+	require.NoError(err)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	conn.User = "user1"
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	userVar := func() interface{} {
+		var v interface{}
+		require.NoError(handler.ComQuery(conn, "SELECT @v", func(res *sqltypes.Result) error {
+			if !res.Rows[0][0].IsNull() {
+				v = res.Rows[0][0].ToString()
+			}
+			return nil
+		}))
+		return v
+	}
+	user := func() string {
+		ctx, err := handler.sm.NewContext(conn)
+		require.NoError(err)
+		return ctx.Session.Client().User
+	}
+
+	// COM_RESET_CONNECTION keeps the user
+	require.NoError(handler.ComQuery(conn, "SET @v = 1", noop))
+	require.NoError(handler.ComQuery(conn, "SELECT GET_LOCK('l', 0)", noop))
+	handler.ComResetConnection(conn)
+	require.Nil(userVar())
+	require.Equal("user1", user())
+	state, _ := e.LS.GetLockState("l")
+	require.Equal(sql.LockFree, state)
+
+	// the client answers COM_CHANGE_USER with the password scrambled with the salt of the connection
+	salt, err := e.Auth.Mysql().Salt()
+	require.NoError(err)
+
+	// a user that fails authentication leaves the session as it was
+	require.NoError(handler.ComQuery(conn, "SET @v = 1", noop))
+	require.Error(handler.ChangeUser(conn, "user2", mysql.ScramblePassword(salt, []byte("wrong")), salt))
+	require.Equal("1", userVar())
+	require.Equal("user1", user())
+
+	require.NoError(handler.ChangeUser(conn, "user2", mysql.ScramblePassword(salt, []byte("pass2")), salt))
+	require.Nil(userVar())
+	require.Equal("user2", user())
+	require.Equal("user2", conn.User)
+
+	// the statements of the session run as the new user
+	var currentUser string
+	require.NoError(handler.ComQuery(conn, "SELECT CURRENT_USER()", func(res *sqltypes.Result) error {
+		currentUser = res.Rows[0][0].ToString()
+		return nil
+	}))
+	require.Contains(currentUser, "user2")
+}
+
+func TestHandlerMaxPreparedStatements(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	conn.PrepareData = make(map[uint32]*mysql.PrepareData)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+	require.NoError(handler.ComQuery(conn, "SET max_prepared_stmt_count = 2", func(res *sqltypes.Result) error {
+		return nil
+	}))
+
+	// prepares a statement as the server does, which assigns it its ID before calling the handler
+	prepare := func(query string) error {
+		conn.StatementID++
+		conn.PrepareData[conn.StatementID] = &mysql.PrepareData{StatementID: conn.StatementID, PrepareStmt: query}
+		_, err := handler.ComPrepare(conn, query)
+		return err
+	}
+	prepared := func() map[uint32]string {
+		ctx, err := handler.sm.NewContext(conn)
+		require.NoError(err)
+		return ctx.Session.PreparedStatements()
+	}
+
+	require.NoError(prepare("SELECT * FROM test WHERE c1 = ?"))
+	require.NoError(prepare("SELECT * FROM test WHERE c1 > ?"))
+	err := prepare("SELECT * FROM test WHERE c1 < ?")
+	require.True(sql.ErrMaxPreparedStmtCountReached.Is(err), "unexpected error %v", err)
+	require.Equal(map[uint32]string{1: "SELECT * FROM test WHERE c1 = ?", 2: "SELECT * FROM test WHERE c1 > ?"}, prepared())
+
+	// a statement that fails to prepare doesn't count
+	delete(conn.PrepareData, 1)
+	require.Error(prepare("SELECT * FROM nonexistent"))
+	require.Equal(map[uint32]string{2: "SELECT * FROM test WHERE c1 > ?"}, prepared())
+
+	// closed statements make room for new ones
+	require.NoError(prepare("SELECT * FROM test WHERE c1 < ?"))
+	require.Equal(map[uint32]string{2: "SELECT * FROM test WHERE c1 > ?", 5: "SELECT * FROM test WHERE c1 < ?"}, prepared())
+
+	handler.ComResetConnection(conn)
+	require.Empty(prepared())
+}
+
+type changeRecorder []sql.ChangeSet
+
+func (r *changeRecorder) TransactionCommitted(ctx *sql.Context, changes sql.ChangeSet) {
+	*r = append(*r, changes)
+}
+
+func TestHandlerChangeListener(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	var changes changeRecorder
+	e.Catalog.AddChangeListener(&changes)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	noop := func(res *sqltypes.Result) error { return nil }
+
+	// A rolled back transaction is never delivered. The memory database doesn't undo the rows it inserted, so the
+	// statements below don't touch them.
+	require.NoError(handler.ComQuery(conn, "INSERT INTO test VALUES (2000), (2001)", noop))
+	require.NoError(handler.ComQuery(conn, "ROLLBACK", noop))
+	require.NoError(handler.ComQuery(conn, "COMMIT", noop))
+	require.Empty(changes)
+
+	require.NoError(handler.ComQuery(conn, "INSERT INTO test VALUES (2002), (2003)", noop))
+	require.NoError(handler.ComQuery(conn, "DELETE FROM test WHERE c1 = 2002", noop))
+	require.NoError(handler.ComQuery(conn, "COMMIT", noop))
+	require.Equal(changeRecorder{{
+		Sequence:  1,
+		SessionID: 1,
+		Tables:    []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 3}},
+	}}, changes)
+
+	// With autocommit, every statement changing data is delivered in order
+	require.NoError(handler.ComQuery(conn, "SET autocommit = 1", noop))
+	require.NoError(handler.ComQuery(conn, "DELETE FROM test WHERE c1 = 2003", noop))
+	require.NoError(handler.ComQuery(conn, "SELECT * FROM test", noop))
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 3000 WHERE c1 = 1", noop))
+	require.Len(changes, 3)
+	for i, change := range changes {
+		require.Equal(uint64(i+1), change.Sequence)
+	}
+	require.Equal([]sql.TableChange{{Database: "test", Table: "test", RowsAffected: 1}}, changes[2].Tables)
+}
+
+func TestHandlerCallResultSets(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	require.NoError(handler.ComQuery(conn, "CREATE PROCEDURE p1() BEGIN SELECT 1; SELECT 2, 3; END", noop))
+	require.NoError(handler.ComQuery(conn, "CREATE PROCEDURE p2() BEGIN CALL p1(); SELECT c1 FROM test WHERE c1 < 2 ORDER BY c1; END", noop))
+
+	type result struct {
+		fields int
+		rows   []string
+		more   bool
+	}
+	var results []result
+	require.NoError(handler.ComQueryResultSets(conn, "CALL p2()", func(res *sqltypes.Result, more bool) error {
+		r := result{fields: len(res.Fields), more: more}
+		for _, row := range res.Rows {
+			r.rows = append(r.rows, fmt.Sprint(row))
+		}
+		results = append(results, r)
+		return nil
+	}))
+	require.Equal([]result{
+		{fields: 1, rows: []string{"[INT8(1)]"}, more: true},
+		{fields: 2, rows: []string{"[INT8(2) INT8(3)]"}, more: true},
+		{fields: 1, rows: []string{"[INT32(0)]", "[INT32(1)]"}, more: false},
+	}, results)
+
+	// ComQuery gives the last result set only
+	results = nil
+	require.NoError(handler.ComQuery(conn, "CALL p2()", func(res *sqltypes.Result) error {
+		results = append(results, result{fields: len(res.Fields), rows: []string{fmt.Sprint(len(res.Rows))}})
+		return nil
+	}))
+	require.Equal([]result{{fields: 1, rows: []string{"2"}}}, results)
+}
+
+type binlogRecorder []sql.BinlogEvent
+
+func (r *binlogRecorder) WriteEvents(ctx *sql.Context, events []sql.BinlogEvent) error {
+	*r = append(*r, events...)
+	return nil
+}
+
+func TestHandlerBinlogWriter(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	var events binlogRecorder
+	e.Catalog.BinlogWriter = &events
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+	conn := newConn(1)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	takeEvents := func() binlogRecorder {
+		taken := events
+		events = nil
+		for i := range taken {
+			require.False(taken[i].Timestamp.IsZero())
+			taken[i].Timestamp = time.Time{}
+		}
+		return taken
+	}
+
+	// Rolled back statements, and statements that change nothing, are never written
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 2000 WHERE c1 = 1000", noop))
+	require.NoError(handler.ComQuery(conn, "ROLLBACK", noop))
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 1 WHERE c1 = 1", noop))
+	require.NoError(handler.ComQuery(conn, "COMMIT", noop))
+	require.Empty(events)
+
+	// By default, the rows changed are written, leaving out the matched ones that didn't change
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 2 WHERE c1 IN (1, 2)", noop))
+	require.NoError(handler.ComQuery(conn, "COMMIT", noop))
+	require.Equal(binlogRecorder{{
+		Query:  "UPDATE test SET c1 = 2 WHERE c1 IN (1, 2)",
+		Format: sql.BinlogFormatRow,
+		Tables: []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 1}},
+		Rows:   []sql.BinlogRowChange{{Database: "test", Table: "test", Before: sql.NewRow(int32(1)), After: sql.NewRow(int32(2))}},
+	}}, takeEvents())
+
+	// In statement format, only the statement is written
+	require.NoError(handler.ComQuery(conn, "SET binlog_format = 'STATEMENT'", noop))
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 3000 WHERE c1 = 2", noop))
+	require.NoError(handler.ComQuery(conn, "DELETE FROM test WHERE c1 = 3", noop))
+	require.NoError(handler.ComQuery(conn, "COMMIT", noop))
+	require.Equal(binlogRecorder{{
+		Query:  "UPDATE test SET c1 = 3000 WHERE c1 = 2",
+		Format: sql.BinlogFormatStatement,
+		Tables: []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 2}},
+	}, {
+		Query:  "DELETE FROM test WHERE c1 = 3",
+		Format: sql.BinlogFormatStatement,
+		Tables: []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 1}},
+	}}, takeEvents())
+
+	// In mixed format, statements that aren't deterministic are written in row format
+	require.NoError(handler.ComQuery(conn, "SET binlog_format = 'MIXED'", noop))
+	require.NoError(handler.ComQuery(conn, "SET autocommit = 1", noop))
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 4000 WHERE c1 = 4", noop))
+	require.NoError(handler.ComQuery(conn, "UPDATE test SET c1 = 5000 + FLOOR(RAND()) WHERE c1 = 5", noop))
+	require.Equal(binlogRecorder{{
+		Query:  "UPDATE test SET c1 = 4000 WHERE c1 = 4",
+		Format: sql.BinlogFormatStatement,
+		Tables: []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 1}},
+	}, {
+		Query:  "UPDATE test SET c1 = 5000 + FLOOR(RAND()) WHERE c1 = 5",
+		Format: sql.BinlogFormatRow,
+		Tables: []sql.TableChange{{Database: "test", Table: "test", RowsAffected: 1}},
+		Rows:   []sql.BinlogRowChange{{Database: "test", Table: "test", Before: sql.NewRow(int32(5)), After: sql.NewRow(int32(5000))}},
+	}}, takeEvents())
+}
+
+func TestHandlerExpireIdleSessions(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			opentracing.NoopTracer{},
+			func(db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			"foo",
+		),
+		0,
+	)
+
+	noop := func(res *sqltypes.Result) error { return nil }
+	var conns []*mysql.Conn
+	for i := uint32(1); i <= 3; i++ {
+		conn := newConn(i)
+		conns = append(conns, conn)
+		handler.NewConnection(conn)
+		require.NoError(handler.ComInitDB(conn, "test"))
+		require.NoError(handler.ComQuery(conn, "SET wait_timeout = 10", noop))
+	}
+	// the default wait_timeout is much longer
+	require.NoError(handler.ComQuery(conns[1], "SET wait_timeout = DEFAULT", noop))
+	// the third connection has a transaction open
+	require.NoError(handler.ComQuery(conns[2], "INSERT INTO test VALUES (2000)", noop))
+
+	require.Empty(handler.ExpireIdleSessions(time.Now()))
+	require.Equal([]uint32{1}, handler.ExpireIdleSessions(time.Now().Add(time.Minute)))
+	require.Nil(handler.sm.session(conns[0]))
+	require.NotNil(handler.sm.session(conns[1]))
+	require.NotNil(handler.sm.session(conns[2]))
+
+	require.NoError(handler.ComQuery(conns[2], "COMMIT", noop))
+	require.Equal([]uint32{3}, handler.ExpireIdleSessions(time.Now().Add(time.Minute)))
+	require.Len(handler.c, 1)
+}
+
 func TestBindingsToExprs(t *testing.T) {
 	type tc struct {
 		Name     string
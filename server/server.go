@@ -22,6 +22,7 @@ import (
 
 	sqle "github.com/dolthub/go-mysql-server"
 	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
 )
 
 // Server is a MySQL server for SQLe engines.
@@ -49,6 +50,9 @@ type Config struct {
 	ConnWriteTimeout time.Duration
 	// MaxConnections is the maximum number of simultaneous connections that the server will allow.
 	MaxConnections uint64
+	// SpillStore, if set, is where the queries of the server keep their temporary files, instead of the directory of
+	// the tmpdir session variable.
+	SpillStore sql.SpillStore
 }
 
 // NewDefaultServer creates a Server with the default session builder.
@@ -78,14 +82,14 @@ func NewServer(cfg Config, e *sqle.Engine, sb SessionBuilder) (*Server, error) {
 		cfg.MaxConnections = 0
 	}
 
-	handler := NewHandler(e,
-		NewSessionManager(
-			sb,
-			tracer,
-			e.Catalog.HasDB,
-			e.Catalog.MemoryManager,
-			cfg.Address),
-		cfg.ConnReadTimeout)
+	sm := NewSessionManager(
+		sb,
+		tracer,
+		e.Catalog.HasDB,
+		e.Catalog.MemoryManager,
+		cfg.Address)
+	sm.spillStore = cfg.SpillStore
+	handler := NewHandler(e, sm, cfg.ConnReadTimeout)
 	a := cfg.Auth.Mysql()
 	l, err := NewListener(cfg.Protocol, cfg.Address, handler)
 	if err != nil {
@@ -124,3 +128,9 @@ func (s *Server) Close() error {
 	s.Listener.Close()
 	return nil
 }
+
+// ExpireIdleSessions closes the connections of the server that have been idle for longer than their wait_timeout, and
+// returns their IDs. See Handler.ExpireIdleSessions.
+func (s *Server) ExpireIdleSessions() []uint32 {
+	return s.h.ExpireIdleSessions(time.Now())
+}
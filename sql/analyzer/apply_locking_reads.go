@@ -0,0 +1,68 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// applyLockingReads puts a LockingTableAccess around every table read by a locking read that is a sql.LockingTable,
+// so that the table locks the rows it returns with the lock type and mode of the read. It runs once the tables are replaced by
+// their indexed accesses, so that only the rows found with an index are locked, as in MySQL.
+func applyLockingReads(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
+	if !n.Resolved() {
+		return n, nil
+	}
+
+	return plan.TransformUp(n, func(n sql.Node) (sql.Node, error) {
+		lr, ok := n.(*plan.LockingRead)
+		if !ok || hasLockingTableAccess(lr) {
+			return n, nil
+		}
+
+		return plan.TransformUp(lr, func(n sql.Node) (sql.Node, error) {
+			var rt *plan.ResolvedTable
+			switch n := n.(type) {
+			case *plan.ResolvedTable:
+				rt = n
+			case *plan.IndexedTableAccess:
+				rt = n.ResolvedTable
+			case *plan.PrimaryKeyLookup:
+				rt = n.ResolvedTable
+			default:
+				return n, nil
+			}
+
+			lt, ok := rt.Table.(sql.LockingTable)
+			if !ok {
+				return n, nil
+			}
+			a.Log("locking rows of table %q %s %s", rt.Name(), lr.Type, lr.Mode)
+			return plan.NewLockingTableAccess(lt, lr.Type, lr.Mode, n), nil
+		})
+	})
+}
+
+func hasLockingTableAccess(n sql.Node) bool {
+	var found bool
+	plan.Inspect(n, func(n sql.Node) bool {
+		if _, ok := n.(*plan.LockingTableAccess); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// applyPrimaryKeyLookups replaces the table scanned by an UPDATE or DELETE with a PrimaryKeyLookup when the table is a
+// sql.PrimaryKeyTable and the filter is an equality on every column of its primary key. The filter is kept, so the
+// lookup only needs to return a superset of the matching rows.
+func applyPrimaryKeyLookups(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
+	if !n.Resolved() {
+		return n, nil
+	}
+
+	return plan.TransformUp(n, func(n sql.Node) (sql.Node, error) {
+		switch n := n.(type) {
+		case *plan.Update, *plan.DeleteFrom:
+			return plan.TransformUp(n, func(n sql.Node) (sql.Node, error) {
+				filter, ok := n.(*plan.Filter)
+				if !ok {
+					return n, nil
+				}
+				rt, ok := filter.Child.(*plan.ResolvedTable)
+				if !ok {
+					return n, nil
+				}
+				if _, ok := rt.Table.(sql.PrimaryKeyTable); !ok {
+					return n, nil
+				}
+
+				keyExprs := primaryKeyLookupExprs(rt.Schema(), filter.Expression)
+				if keyExprs == nil {
+					return n, nil
+				}
+
+				a.Log("applying primary key lookup to table %s", rt.Name())
+				return filter.WithChildren(plan.NewPrimaryKeyLookup(rt, keyExprs))
+			})
+		default:
+			return n, nil
+		}
+	})
+}
+
+// primaryKeyLookupExprs returns the expressions giving the value of each primary key column of the schema, in schema
+// order, from the equalities in the filter given. Returns nil if any primary key column isn't compared to a constant
+// expression.
+func primaryKeyLookupExprs(schema sql.Schema, filter sql.Expression) []sql.Expression {
+	var pkCols []*sql.Column
+	for _, col := range schema {
+		if col.PrimaryKey {
+			pkCols = append(pkCols, col)
+		}
+	}
+	if len(pkCols) == 0 {
+		return nil
+	}
+
+	keyExprs := make([]sql.Expression, len(pkCols))
+	for _, e := range splitConjunction(filter) {
+		eq, ok := e.(*expression.Equals)
+		if !ok {
+			continue
+		}
+
+		gf, val := fieldAndConstant(eq.Left(), eq.Right())
+		if gf == nil {
+			gf, val = fieldAndConstant(eq.Right(), eq.Left())
+		}
+		if gf == nil {
+			continue
+		}
+
+		for i, col := range pkCols {
+			if keyExprs[i] == nil && strings.EqualFold(col.Name, gf.Name()) && strings.EqualFold(col.Source, gf.Table()) {
+				keyExprs[i] = val
+				break
+			}
+		}
+	}
+
+	for _, e := range keyExprs {
+		if e == nil {
+			return nil
+		}
+	}
+	return keyExprs
+}
+
+// fieldAndConstant returns the arguments given if the first is a field and the second is a constant expression, one
+// that doesn't depend on the row or change during the statement.
+func fieldAndConstant(field, constant sql.Expression) (*expression.GetField, sql.Expression) {
+	gf, ok := field.(*expression.GetField)
+	if !ok {
+		return nil, nil
+	}
+
+	isConstant := true
+	sql.Inspect(constant, func(e sql.Expression) bool {
+		switch e.(type) {
+		case *expression.GetField, *plan.Subquery, *expression.BindVar, *expression.UserVar, *expression.SystemVar:
+			isConstant = false
+		}
+		return isConstant
+	})
+	if !isConstant || !sql.IsDeterministic(constant) {
+		return nil, nil
+	}
+
+	return gf, constant
+}
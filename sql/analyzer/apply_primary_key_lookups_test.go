@@ -0,0 +1,89 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+func TestApplyPrimaryKeyLookups(t *testing.T) {
+	table := memory.NewTable("t", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "t", PrimaryKey: true},
+		{Name: "b", Type: sql.Text, Source: "t", PrimaryKey: true},
+		{Name: "c", Type: sql.Int64, Source: "t", Nullable: true},
+	})
+	rt := plan.NewResolvedTable(table, nil, nil)
+
+	a := expression.NewGetFieldWithTable(0, sql.Int64, "t", "a", false)
+	b := expression.NewGetFieldWithTable(1, sql.Text, "t", "b", false)
+	c := expression.NewGetFieldWithTable(2, sql.Int64, "t", "c", true)
+	one := expression.NewLiteral(int64(1), sql.Int64)
+	x := expression.NewLiteral("x", sql.Text)
+
+	fullKey := expression.NewAnd(
+		expression.NewEquals(x, b),
+		expression.NewAnd(
+			expression.NewEquals(a, one),
+			expression.NewEquals(c, one),
+		),
+	)
+
+	tests := []analyzerFnTestCase{
+		{
+			name: "delete on the full primary key",
+			node: plan.NewDeleteFrom(plan.NewFilter(fullKey, rt)),
+			expected: plan.NewDeleteFrom(
+				plan.NewFilter(fullKey, plan.NewPrimaryKeyLookup(rt, []sql.Expression{one, x})),
+			),
+		},
+		{
+			name: "update on the full primary key",
+			node: plan.NewUpdate(plan.NewFilter(fullKey, rt), nil),
+			expected: plan.NewUpdate(
+				plan.NewFilter(fullKey, plan.NewPrimaryKeyLookup(rt, []sql.Expression{one, x})),
+				nil,
+			),
+		},
+		{
+			name: "partial primary key",
+			node: plan.NewDeleteFrom(plan.NewFilter(expression.NewEquals(a, one), rt)),
+		},
+		{
+			name: "disjunction on the primary key",
+			node: plan.NewDeleteFrom(plan.NewFilter(
+				expression.NewOr(expression.NewEquals(a, one), expression.NewEquals(b, x)),
+				rt,
+			)),
+		},
+		{
+			name: "key compared to another column",
+			node: plan.NewDeleteFrom(plan.NewFilter(
+				expression.NewAnd(expression.NewEquals(a, c), expression.NewEquals(b, x)),
+				rt,
+			)),
+		},
+		{
+			name: "select on the full primary key",
+			node: plan.NewFilter(fullKey, rt),
+		},
+	}
+
+	runTestCases(t, sql.NewEmptyContext(), tests, NewDefault(sql.NewCatalog()), getRule("apply_primary_key_lookups"))
+}
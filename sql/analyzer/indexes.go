@@ -111,6 +111,11 @@ func getIndexes(
 			return nil, nil
 		}
 
+		// Using an index for a disjunction means merging the lookups of both sides, which can be turned off
+		if flags := ctx.OptimizerSwitch(); !flags["index_merge"] || !flags["index_merge_union"] {
+			return nil, nil
+		}
+
 		leftIndexes, err := getIndexes(ctx, a, ia, e.Left, tableAliases)
 		if err != nil {
 			return nil, err
@@ -23,6 +23,7 @@ import (
 	"github.com/dolthub/go-mysql-server/memory"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 )
 
@@ -1137,6 +1138,40 @@ func TestGetIndexes(t *testing.T) {
 	}
 }
 
+func TestGetIndexesOptimizerSwitch(t *testing.T) {
+	require := require.New(t)
+
+	idxReg := sql.NewIndexRegistry()
+	done, ready, err := idxReg.AddIndex(&memory.MergeableIndex{
+		TableName: "t1",
+		Exprs:     []sql.Expression{col(0, "t1", "bar")},
+	})
+	require.NoError(err)
+	close(done)
+	<-ready
+
+	a := NewDefault(sql.NewCatalog())
+	ctx := sql.NewContext(context.Background(), sql.WithIndexRegistry(idxReg))
+	ia, err := getIndexesForNode(ctx, a, nil)
+	require.NoError(err)
+	expr := or(eq(col(0, "t1", "bar"), lit(1)), eq(col(0, "t1", "bar"), lit(2)))
+
+	result, err := getIndexes(ctx, a, ia, expr, nil)
+	require.NoError(err)
+	require.Contains(result, "t1")
+
+	require.NoError(ctx.SetOptimizerFlag("index_merge", false))
+	result, err = getIndexes(ctx, a, ia, expr, nil)
+	require.NoError(err)
+	require.Empty(result)
+
+	require.NoError(ctx.SetOptimizerFlag("index_merge", true))
+	require.NoError(ctx.SetOptimizerFlag("index_merge_union", false))
+	result, err = getIndexes(ctx, a, ia, expr, nil)
+	require.NoError(err)
+	require.Empty(result)
+}
+
 func TestGetMultiColumnIndexes(t *testing.T) {
 	require := require.New(t)
 
@@ -1416,3 +1451,51 @@ func TestCanMergeIndexes(t *testing.T) {
 	require.False(canMergeIndexes(new(memory.MergeableIndexLookup), new(DummyIndexLookup)))
 	require.True(canMergeIndexes(new(memory.MergeableIndexLookup), new(memory.MergeableIndexLookup)))
 }
+
+func TestGetIndexesFunctionalIndex(t *testing.T) {
+	require := require.New(t)
+
+	idx := &memory.MergeableIndex{
+		TableName: "t1",
+		Exprs:     []sql.Expression{function.NewLower(col(0, "t1", "name"))},
+	}
+	idxReg := sql.NewIndexRegistry()
+	done, ready, err := idxReg.AddIndex(idx)
+	require.NoError(err)
+	close(done)
+	<-ready
+
+	a := NewDefault(sql.NewCatalog())
+	ctx := sql.NewContext(context.Background(), sql.WithIndexRegistry(idxReg))
+	ia, err := getIndexesForNode(ctx, a, nil)
+	require.NoError(err)
+
+	name := litT("foo", sql.LongText)
+	lowerName := function.NewLower(col(0, "t1", "name"))
+	aliases := TableAliases{"t": &plan.ResolvedTable{Table: memory.NewTable("t1", nil)}}
+	for _, test := range []struct {
+		expr  sql.Expression
+		table string
+	}{
+		{eq(lowerName, name), "t1"},
+		{eq(name, lowerName), "t1"},
+		{eq(function.NewLower(col(0, "t", "name")), name), "t"},
+	} {
+		result, err := getIndexes(ctx, a, ia, test.expr, aliases)
+		require.NoError(err)
+		require.Contains(result, test.table, test.expr.String())
+		require.Equal([]sql.Index{idx}, result[test.table].indexes)
+		require.Equal(&memory.MergeableIndexLookup{Key: []interface{}{"foo"}, Index: idx}, result[test.table].lookup)
+	}
+
+	// Only the expression the index was built on can use it
+	for _, expr := range []sql.Expression{
+		eq(col(0, "t1", "name"), name),
+		eq(function.NewUpper(col(0, "t1", "name")), name),
+		eq(function.NewLower(col(0, "t1", "other")), name),
+	} {
+		result, err := getIndexes(ctx, a, ia, expr, nil)
+		require.NoError(err)
+		require.Empty(result, expr.String())
+	}
+}
@@ -15,6 +15,8 @@
 package analyzer
 
 import (
+	"strconv"
+
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 	"github.com/dolthub/go-mysql-server/sql/plan"
@@ -71,6 +73,75 @@ func optimizeDistinct(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope
 	return node, nil
 }
 
+// optimizeGroupBy substitutes a GroupBy node for an OrderedGroupBy node when the child of GroupBy is a Sort on its
+// grouping expressions, or a subquery alias whose query is. The OrderedGroupBy node returns every group as soon as the
+// next one starts, so it doesn't have to buffer all of them. It runs after parallelize, so the order of the Sort can't
+// be lost to an Exchange node inserted between them.
+func optimizeGroupBy(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope) (sql.Node, error) {
+	span, _ := ctx.Span("optimize_group_by")
+	defer span.Finish()
+
+	return plan.TransformUp(node, func(node sql.Node) (sql.Node, error) {
+		g, ok := node.(*plan.GroupBy)
+		if !ok || len(g.GroupByExprs) == 0 {
+			return node, nil
+		}
+
+		var sorted bool
+		switch child := g.Child.(type) {
+		case *plan.Sort:
+			sorted = isSortedOn(child.SortFields, g.GroupByExprs, sql.Expression.String)
+		case *plan.SubqueryAlias:
+			// the grouping expressions refer to the columns of the subquery by name, so only the columns themselves
+			// can be matched to the sort fields, by their index
+			if sort, ok := child.Child.(*plan.Sort); ok {
+				sorted = isSortedOn(sort.SortFields, g.GroupByExprs, fieldIndex)
+			}
+		}
+		if !sorted {
+			return node, nil
+		}
+
+		a.Log("group by optimized for ordered input")
+		return plan.NewOrderedGroupBy(g.SelectedExprs, g.GroupByExprs, g.Child), nil
+	})
+}
+
+// isSortedOn returns whether rows sorted on the fields given have the rows with the same values of the expressions
+// given next to each other, which is the case when the leading sort fields are the expressions, in any order. Sort
+// fields and expressions are matched by the key function given, which returns "" for those that can't be matched.
+func isSortedOn(fields sql.SortFields, exprs []sql.Expression, key func(sql.Expression) string) bool {
+	remaining := make(map[string]bool, len(exprs))
+	for _, e := range exprs {
+		k := key(e)
+		if k == "" {
+			return false
+		}
+		remaining[k] = true
+	}
+
+	for _, f := range fields {
+		if len(remaining) == 0 {
+			break
+		}
+		k := key(f.Column)
+		if !remaining[k] {
+			return false
+		}
+		delete(remaining, k)
+	}
+
+	return len(remaining) == 0
+}
+
+// fieldIndex returns the index of the column the expression given is, or "" if it isn't a column.
+func fieldIndex(e sql.Expression) string {
+	if gf, ok := e.(*expression.GetField); ok {
+		return strconv.Itoa(gf.Index())
+	}
+	return ""
+}
+
 // moveJoinConditionsToFilter looks for expressions in a join condition that reference only tables in the left or right
 // side of the join, and move those conditions to a new Filter node instead. If the join condition is empty after these
 // moves, the join is converted to a CrossJoin.
@@ -282,7 +353,8 @@ func evalFilter(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope) (sql
 				}
 
 				return e, nil
-			case *expression.Literal, expression.Tuple, *expression.Interval:
+			case *expression.Literal, expression.Tuple, *expression.Interval, *expression.Collate:
+				// A COLLATE clause is kept, so that it still applies to the comparison it's in
 				return e, nil
 			default:
 				if !isEvaluable(e) {
@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/memory"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 )
 
@@ -128,6 +129,91 @@ func TestOptimizeDistinct(t *testing.T) {
 	}
 }
 
+func TestOptimizeGroupBy(t *testing.T) {
+	t1 := memory.NewTable("foo", sql.Schema{
+		{Name: "a", Source: "foo"},
+		{Name: "b", Source: "foo"},
+		{Name: "c", Source: "foo"},
+	})
+	table := plan.NewResolvedTable(t1, nil, nil)
+	sorted := func(child sql.Node, fields ...sql.Expression) sql.Node {
+		sortFields := make([]sql.SortField, len(fields))
+		for i, f := range fields {
+			sortFields[i] = sql.SortField{Column: f}
+		}
+		return plan.NewSort(sortFields, child)
+	}
+
+	testCases := []struct {
+		name      string
+		grouping  []sql.Expression
+		child     sql.Node
+		optimized bool
+	}{
+		{
+			"without sort",
+			[]sql.Expression{gf(0, "foo", "a")},
+			table,
+			false,
+		},
+		{
+			"without grouping",
+			nil,
+			sorted(table, gf(0, "foo", "a")),
+			false,
+		},
+		{
+			"sort on grouping column",
+			[]sql.Expression{gf(0, "foo", "a")},
+			sorted(table, gf(0, "foo", "a")),
+			true,
+		},
+		{
+			"sort on grouping columns and more",
+			[]sql.Expression{gf(1, "foo", "b"), gf(0, "foo", "a")},
+			sorted(table, gf(0, "foo", "a"), gf(1, "foo", "b"), gf(2, "foo", "c")),
+			true,
+		},
+		{
+			"sort on other column first",
+			[]sql.Expression{gf(0, "foo", "a")},
+			sorted(table, gf(1, "foo", "b"), gf(0, "foo", "a")),
+			false,
+		},
+		{
+			"sort on some grouping columns",
+			[]sql.Expression{gf(0, "foo", "a"), gf(1, "foo", "b")},
+			sorted(table, gf(0, "foo", "a")),
+			false,
+		},
+		{
+			"sorted subquery",
+			[]sql.Expression{gf(1, "sq", "b")},
+			plan.NewSubqueryAlias("sq", "", sorted(table, gf(1, "foo", "b"))),
+			true,
+		},
+		{
+			"subquery sorted on other column",
+			[]sql.Expression{gf(1, "sq", "b")},
+			plan.NewSubqueryAlias("sq", "", sorted(table, gf(0, "foo", "a"))),
+			false,
+		},
+	}
+
+	rule := getRuleFrom(OnceAfterAll, "optimize_group_by")
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			selected := append([]sql.Expression{aggregation.NewCount(expression.NewStar())}, tt.grouping...)
+			node, err := rule.Apply(sql.NewEmptyContext(), nil, plan.NewGroupBy(selected, tt.grouping, tt.child), nil)
+			require.NoError(t, err)
+
+			_, ok := node.(*plan.OrderedGroupBy)
+			require.Equal(t, tt.optimized, ok)
+		})
+	}
+}
+
 func TestMoveJoinConditionsToFilter(t *testing.T) {
 	t1 := memory.NewTable("t1", sql.Schema{
 		{Name: "a", Source: "t1", Type: sql.Int64},
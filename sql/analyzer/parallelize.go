@@ -53,9 +53,16 @@ func parallelize(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope) (sq
 		if !isParallelizable(node) {
 			return node, nil
 		}
-		ParallelQueryCounter.With("parallelism", strconv.Itoa(a.Parallelism)).Add(1)
+		parallelism, err := tableParallelism(ctx, node, a.Parallelism)
+		if err != nil {
+			return nil, err
+		}
+		if parallelism <= 1 {
+			return node, nil
+		}
+		ParallelQueryCounter.With("parallelism", strconv.Itoa(parallelism)).Add(1)
 
-		return plan.NewExchange(a.Parallelism, node), nil
+		return plan.NewExchange(parallelism, node), nil
 	})
 
 	if err != nil {
@@ -65,6 +72,45 @@ func parallelize(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope) (sq
 	return plan.TransformUp(node, removeRedundantExchanges)
 }
 
+// tableParallelism returns the number of goroutines to read the partitions of the table of a parallelizable node with,
+// given the parallelism of the analyzer. A sql.ParallelTable is read with no more goroutines than it has partitions,
+// and with only one if its partitions can't be read concurrently.
+func tableParallelism(ctx *sql.Context, node sql.Node, parallelism int) (int, error) {
+	var table sql.Table
+	plan.Inspect(node, func(node sql.Node) bool {
+		if t, ok := node.(sql.Table); ok {
+			table = t
+			return false
+		}
+		return true
+	})
+	for {
+		if rt, ok := table.(*plan.ResolvedTable); ok {
+			table = rt.Table
+		} else if tw, ok := table.(sql.TableWrapper); ok {
+			table = tw.Underlying()
+		} else {
+			break
+		}
+	}
+
+	pt, ok := table.(sql.ParallelTable)
+	if !ok {
+		return parallelism, nil
+	}
+	if !pt.ConcurrentPartitions(ctx) {
+		return 1, nil
+	}
+	count, err := pt.PartitionCount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if count < int64(parallelism) {
+		return int(count), nil
+	}
+	return parallelism, nil
+}
+
 // removeRedundantExchanges removes all the exchanges except for the topmost
 // of all.
 func removeRedundantExchanges(node sql.Node) (sql.Node, error) {
@@ -130,7 +176,7 @@ func isParallelizable(node sql.Node) bool {
 			}
 		// IndexedTablesAccess already uses an index for lookups, so parallelizing it won't help in most cases (and can
 		// blow up the query execution graph)
-		case *plan.IndexedTableAccess:
+		case *plan.IndexedTableAccess, *plan.PrimaryKeyLookup:
 			parallelizable = false
 			return false
 		case sql.Table:
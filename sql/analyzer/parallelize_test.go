@@ -299,3 +299,65 @@ func TestRemoveRedundantExchanges(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expected, result)
 }
+
+func TestParallelizeParallelTable(t *testing.T) {
+	rule := getRuleFrom(OnceAfterAll, "parallelize")
+	schema := sql.Schema{{Name: "i", Type: sql.Int64, Source: "t"}}
+	partitioned := memory.NewPartitionedTable("t", schema, 3)
+	for i := int64(0); i < 30; i++ {
+		require.NoError(t, partitioned.Insert(sql.NewEmptyContext(), sql.NewRow(i)))
+	}
+
+	testCases := []struct {
+		name        string
+		concurrent  bool
+		parallelism int
+		expected    int
+	}{
+		{"fewer partitions than goroutines", true, 5, 3},
+		{"more partitions than goroutines", true, 2, 2},
+		{"partitions can't be read concurrently", false, 5, 0},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			table := &parallelTable{partitioned, tt.concurrent}
+			node := plan.NewFilter(
+				expression.NewGreaterThan(
+					expression.NewGetFieldWithTable(0, sql.Int64, "t", "i", false),
+					expression.NewLiteral(int64(10), sql.Int64),
+				),
+				plan.NewResolvedTable(table, nil, nil),
+			)
+
+			ctx := sql.NewEmptyContext()
+			result, err := rule.Apply(ctx, &Analyzer{Parallelism: tt.parallelism}, node, nil)
+			require.NoError(err)
+			if tt.expected == 0 {
+				require.Equal(node, result)
+				return
+			}
+			require.Equal(plan.NewExchange(tt.expected, node), result)
+
+			// the parallel read returns the same rows as the sequential one, in any order
+			sequential, err := sql.NodeToRows(ctx, node)
+			require.NoError(err)
+			parallel, err := sql.NodeToRows(ctx, result)
+			require.NoError(err)
+			require.Len(sequential, 19)
+			require.ElementsMatch(sequential, parallel)
+		})
+	}
+}
+
+// parallelTable is a sql.ParallelTable whose partitions can be read concurrently or not.
+type parallelTable struct {
+	*memory.Table
+	concurrent bool
+}
+
+var _ sql.ParallelTable = (*parallelTable)(nil)
+
+func (t *parallelTable) ConcurrentPartitions(*sql.Context) bool {
+	return t.concurrent
+}
@@ -17,12 +17,22 @@ package analyzer
 import (
 	"sort"
 	"strings"
+	"sync/atomic"
 
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 )
 
+// ErrInvalidProcedurePage is returned when a page of stored procedures is requested with a negative offset or limit.
+var ErrInvalidProcedurePage = errors.NewKind("invalid page of stored procedures: offset %d, limit %d")
+
 // ProcedureCache contains all of the stored procedures for each database.
 type ProcedureCache struct {
+	// hits and misses are accessed atomically, and come first to be 64-bit aligned on 32-bit platforms.
+	hits             uint64
+	misses           uint64
 	dbToProcedureMap map[string]map[string]*plan.Procedure
 	IsPopulating     bool
 }
@@ -35,9 +45,40 @@ func NewProcedureCache() *ProcedureCache {
 	}
 }
 
+// ProcedureCacheStats are the number of lookups of a ProcedureCache that found a stored procedure and that didn't.
+type ProcedureCacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
 // Get returns the stored procedure with the given name from the given database. All names are case-insensitive. If the
-// procedure does not exist, then this returns nil.
+// procedure does not exist, then this returns nil. Every call counts as a hit or a miss in the Stats of the cache.
 func (pc *ProcedureCache) Get(dbName, procedureName string) *plan.Procedure {
+	procedure := pc.get(dbName, procedureName)
+	if procedure != nil {
+		atomic.AddUint64(&pc.hits, 1)
+	} else {
+		atomic.AddUint64(&pc.misses, 1)
+	}
+	return procedure
+}
+
+// Stats returns the number of hits and misses of Get since the cache was created or its stats were last reset.
+// Procedures can't be overloaded, so a lookup either finds the procedure with the name given or misses.
+func (pc *ProcedureCache) Stats() ProcedureCacheStats {
+	return ProcedureCacheStats{
+		Hits:   atomic.LoadUint64(&pc.hits),
+		Misses: atomic.LoadUint64(&pc.misses),
+	}
+}
+
+// ResetStats sets the hits and misses of the cache back to zero.
+func (pc *ProcedureCache) ResetStats() {
+	atomic.StoreUint64(&pc.hits, 0)
+	atomic.StoreUint64(&pc.misses, 0)
+}
+
+func (pc *ProcedureCache) get(dbName, procedureName string) *plan.Procedure {
 	dbName = strings.ToLower(dbName)
 	procedureName = strings.ToLower(procedureName)
 	if procMap, ok := pc.dbToProcedureMap[dbName]; ok {
@@ -67,6 +108,27 @@ func (pc *ProcedureCache) AllForDatabase(dbName string) []*plan.Procedure {
 	return procedures
 }
 
+// AllForDatabasePaged returns the page of at most limit stored procedures starting at the offset given, in the order
+// of AllForDatabase, along with the total number of stored procedures for the database. An offset beyond the last
+// procedure returns an empty page.
+func (pc *ProcedureCache) AllForDatabasePaged(dbName string, offset, limit int) ([]*plan.Procedure, int, error) {
+	if offset < 0 || limit < 0 {
+		return nil, 0, ErrInvalidProcedurePage.New(offset, limit)
+	}
+
+	procedures := pc.AllForDatabase(dbName)
+	total := len(procedures)
+	if offset >= total {
+		return nil, total, nil
+	}
+
+	end := offset + limit
+	if end > total || end < offset {
+		end = total
+	}
+	return procedures[offset:end], total, nil
+}
+
 // Register adds the given stored procedure to the cache. Will overwrite any procedures that already exist with the
 // same name for the given database name.
 func (pc *ProcedureCache) Register(dbName string, procedure *plan.Procedure) {
@@ -77,3 +139,14 @@ func (pc *ProcedureCache) Register(dbName string, procedure *plan.Procedure) {
 		pc.dbToProcedureMap[dbName] = map[string]*plan.Procedure{strings.ToLower(procedure.Name): procedure}
 	}
 }
+
+// RegisterStrict adds the given stored procedure to the cache, returning sql.ErrStoredProcedureAlreadyExists if a
+// procedure with the same name already exists for the given database name. As in MySQL, procedures can't be overloaded,
+// so the parameters of the procedures are not considered. Register should be used to replace a procedure.
+func (pc *ProcedureCache) RegisterStrict(dbName string, procedure *plan.Procedure) error {
+	if pc.get(dbName, procedure.Name) != nil {
+		return sql.ErrStoredProcedureAlreadyExists.New(procedure.Name)
+	}
+	pc.Register(dbName, procedure)
+	return nil
+}
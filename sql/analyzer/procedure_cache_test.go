@@ -0,0 +1,130 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+func TestProcedureCacheAllForDatabasePaged(t *testing.T) {
+	pc := NewProcedureCache()
+	for i := 9; i >= 0; i-- {
+		pc.Register("MyDb", plan.NewProcedure(fmt.Sprintf("proc%d", i), "", nil, plan.ProcedureSecurityContext_Definer,
+			"", nil, "", plan.NewBlock(nil), time.Unix(0, 0), time.Unix(0, 0)))
+	}
+
+	names := func(procedures []*plan.Procedure) []string {
+		var names []string
+		for _, procedure := range procedures {
+			names = append(names, procedure.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		offset   int
+		limit    int
+		expected []string
+	}{
+		{"first page", 0, 3, []string{"proc0", "proc1", "proc2"}},
+		{"middle page", 3, 3, []string{"proc3", "proc4", "proc5"}},
+		{"last partial page", 8, 3, []string{"proc8", "proc9"}},
+		{"offset beyond the end", 10, 3, nil},
+		{"empty page", 2, 0, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page, total, err := pc.AllForDatabasePaged("mydb", test.offset, test.limit)
+			require.NoError(t, err)
+			require.Equal(t, 10, total)
+			require.Equal(t, test.expected, names(page))
+		})
+	}
+
+	page, total, err := pc.AllForDatabasePaged("otherdb", 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, 0, total)
+	require.Empty(t, page)
+
+	_, _, err = pc.AllForDatabasePaged("mydb", -1, 3)
+	require.True(t, ErrInvalidProcedurePage.Is(err))
+}
+
+func TestProcedureCacheRegisterStrict(t *testing.T) {
+	newProcedure := func(name string, params ...plan.ProcedureParam) *plan.Procedure {
+		return plan.NewProcedure(name, "", params, plan.ProcedureSecurityContext_Definer,
+			"", nil, "", plan.NewBlock(nil), time.Unix(0, 0), time.Unix(0, 0))
+	}
+
+	pc := NewProcedureCache()
+	require.NoError(t, pc.RegisterStrict("mydb", newProcedure("p1")))
+	require.NoError(t, pc.RegisterStrict("otherdb", newProcedure("p1")))
+
+	err := pc.RegisterStrict("MyDb", newProcedure("P1"))
+	require.True(t, sql.ErrStoredProcedureAlreadyExists.Is(err))
+
+	param := plan.ProcedureParam{Direction: plan.ProcedureParamDirection_In, Name: "x", Type: sql.Int64}
+	err = pc.RegisterStrict("mydb", newProcedure("p1", param))
+	require.True(t, sql.ErrStoredProcedureAlreadyExists.Is(err))
+	require.Empty(t, pc.Get("mydb", "p1").Params)
+
+	pc.Register("mydb", newProcedure("p1", param))
+	require.Len(t, pc.Get("mydb", "p1").Params, 1)
+}
+
+func TestProcedureCacheStats(t *testing.T) {
+	pc := NewProcedureCache()
+	pc.Register("mydb", plan.NewProcedure("p1", "", nil, plan.ProcedureSecurityContext_Definer,
+		"", nil, "", plan.NewBlock(nil), time.Unix(0, 0), time.Unix(0, 0)))
+	require.Equal(t, ProcedureCacheStats{}, pc.Stats())
+
+	require.NotNil(t, pc.Get("MyDb", "P1"))
+	require.Equal(t, ProcedureCacheStats{Hits: 1}, pc.Stats())
+
+	require.Nil(t, pc.Get("mydb", "p2"))
+	require.Nil(t, pc.Get("otherdb", "p1"))
+	require.Equal(t, ProcedureCacheStats{Hits: 1, Misses: 2}, pc.Stats())
+
+	// Registering doesn't count as a lookup
+	require.Error(t, pc.RegisterStrict("mydb", pc.Get("mydb", "p1")))
+	require.Equal(t, ProcedureCacheStats{Hits: 2, Misses: 2}, pc.Stats())
+
+	pc.ResetStats()
+	require.Equal(t, ProcedureCacheStats{}, pc.Stats())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				pc.Get("mydb", "p1")
+				pc.Get("mydb", "p2")
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, ProcedureCacheStats{Hits: 1000, Misses: 1000}, pc.Stats())
+}
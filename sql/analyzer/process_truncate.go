@@ -69,6 +69,11 @@ func deleteToTruncate(ctx *sql.Context, a *Analyzer, deletePlan *plan.DeleteFrom
 	}
 	tblName := strings.ToLower(tbl.Name())
 
+	// a DELETE without a WHERE clause must be rejected by validate_safe_updates, not run as a TRUNCATE
+	if safeUpdatesEnabled(ctx) {
+		return deletePlan, nil
+	}
+
 	// auto_increment behaves differently for TRUNCATE and DELETE
 	for _, col := range tbl.Schema() {
 		if col.AutoIncrement {
@@ -555,7 +555,17 @@ func resolveSystemVariable(ctx *sql.Context, a *Analyzer, col column) (sql.Expre
 	typ, _ := ctx.Get(name)
 
 	a.Log("resolved column %s to system variable (type %s)", col, typ)
-	return expression.NewSystemVar(name, typ), nil
+	return newSystemVar(col.Name(), typ), nil
+}
+
+// newSystemVar returns a SystemVar of the type given for the variable name given, which may be qualified with its
+// scope, such as @@global.sql_mode.
+func newSystemVar(name string, typ sql.Type) *expression.SystemVar {
+	sysVar := expression.NewSystemVar(trimVarName(name), typ)
+	if strings.HasPrefix(strings.TrimLeft(strings.ToLower(name), "@"), globalPrefix) {
+		sysVar.Scope = sql.SystemVariableScope_Global
+	}
+	return sysVar
 }
 
 func trimVarName(name string) string {
@@ -74,5 +74,9 @@ func resolveCreateLike(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope)
 		IdxDefs: idxDefs,
 	}
 
-	return plan.NewCreateTable(planCreate.Database(), planCreate.Name(), planCreate.IfNotExists(), tableSpec), nil
+	newCreate := plan.NewCreateTable(planCreate.Database(), planCreate.Name(), planCreate.IfNotExists(), tableSpec)
+	if planCreate.Temporary() {
+		newCreate = newCreate.AsTemporary()
+	}
+	return newCreate, nil
 }
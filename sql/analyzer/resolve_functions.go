@@ -30,11 +30,11 @@ func resolveFunctions(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (
 			return n, nil
 		}
 
-		return plan.TransformExpressionsUp(n, resolveFunctionsInExpr(a))
+		return plan.TransformExpressionsUp(n, resolveFunctionsInExpr(ctx, a))
 	})
 }
 
-func resolveFunctionsInExpr(a *Analyzer) sql.TransformExprFunc {
+func resolveFunctionsInExpr(ctx *sql.Context, a *Analyzer) sql.TransformExprFunc {
 	return func(e sql.Expression) (sql.Expression, error) {
 		if e.Resolved() {
 			return e, nil
@@ -46,6 +46,9 @@ func resolveFunctionsInExpr(a *Analyzer) sql.TransformExprFunc {
 		}
 
 		n := uf.Name()
+		if !ctx.Dialect().HasFunction(n) {
+			return nil, sql.ErrFunctionNotFound.New(n)
+		}
 		f, err := a.Catalog.Function(n)
 		if err != nil {
 			return nil, err
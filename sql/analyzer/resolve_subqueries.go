@@ -29,10 +29,19 @@ func resolveSubqueries(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope)
 		case *plan.SubqueryAlias:
 			a.Log("found subquery %q with child of type %T", n.Name(), n.Child)
 
-			// subqueries do not have access to outer scope
-			child, err := a.Analyze(ctx, n.Child, nil)
-			if err != nil {
-				return nil, err
+			// The same definition is shared by every reference to a view, and the analysis of a subquery doesn't depend
+			// on its surroundings, so it's only analyzed once per statement
+			child, ok := ctx.AnalyzedNode(n.Child)
+			if ok {
+				a.Log("subquery %q already analyzed", n.Name())
+			} else {
+				// subqueries do not have access to outer scope
+				analyzed, err := a.Analyze(ctx, n.Child, nil)
+				if err != nil {
+					return nil, err
+				}
+				child = stripQueryProcess(analyzed)
+				ctx.SetAnalyzedNode(n.Child, child)
 			}
 
 			if len(n.Columns) > 0 {
@@ -42,7 +51,7 @@ func resolveSubqueries(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope)
 				}
 			}
 
-			return n.WithChildren(stripQueryProcess(child))
+			return n.WithChildren(child)
 		default:
 			return n, nil
 		}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dolthub/go-mysql-server/sql/expression/function"
 
 	"github.com/dolthub/go-mysql-server/memory"
@@ -110,6 +112,58 @@ func TestResolveSubqueries(t *testing.T) {
 	runTestCases(t, ctx, testCases, a, getRule("resolve_subqueries"))
 }
 
+func TestResolveSubqueriesAnalyzesViewsOnce(t *testing.T) {
+	require := require.New(t)
+
+	db := memory.NewDatabase("mydb")
+	db.AddTable("mytable", memory.NewTable("mytable", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "mytable"},
+	}))
+	catalog := sql.NewCatalog()
+	catalog.AddDatabase(db)
+
+	viewReg := sql.NewViewRegistry()
+	require.NoError(viewReg.Register("mydb", sql.NewView("myview", plan.NewSubqueryAlias(
+		"myview", "select i from mytable",
+		plan.NewProject(
+			[]sql.Expression{expression.NewUnresolvedColumn("i")},
+			plan.NewUnresolvedTable("mytable", ""),
+		),
+	), "select i from mytable")))
+
+	// every analysis, of the query or of one of its subqueries, runs the rules before the default ones once
+	var analyses int
+	a := NewBuilder(catalog).AddPreAnalyzeRule("count_analyses", func(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
+		analyses++
+		return n, nil
+	}).Build()
+
+	// SELECT * FROM myview v1, myview v2, myview v3, myview v4
+	var node sql.Node = plan.NewTableAlias("v1", plan.NewUnresolvedTable("myview", ""))
+	for _, alias := range []string{"v2", "v3", "v4"} {
+		node = plan.NewCrossJoin(node, plan.NewTableAlias(alias, plan.NewUnresolvedTable("myview", "")))
+	}
+	node = plan.NewProject([]sql.Expression{expression.NewStar()}, node)
+
+	ctx := sql.NewContext(context.Background(),
+		sql.WithIndexRegistry(sql.NewIndexRegistry()),
+		sql.WithViewRegistry(viewReg)).WithCurrentDB("mydb")
+	ctx.StartStatement()
+	analyzed, err := a.Analyze(ctx, node, nil)
+	require.NoError(err)
+	require.True(analyzed.Resolved())
+	require.Len(analyzed.Schema(), 4)
+	// the query, and the definition of the view once rather than once per reference
+	require.Equal(2, analyses)
+
+	// the analyzed definition doesn't outlive the statement
+	analyses = 0
+	ctx.StartStatement()
+	_, err = a.Analyze(ctx, node, nil)
+	require.NoError(err)
+	require.Equal(2, analyses)
+}
+
 func TestResolveSubqueryExpressions(t *testing.T) {
 	table := memory.NewTable("mytable", sql.Schema{
 		{Name: "i", Type: sql.Int64, Source: "mytable"},
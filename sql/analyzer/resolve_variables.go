@@ -76,7 +76,7 @@ func resolveSetVariables(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope
 					}
 				}
 
-				return sf.WithChildren(expression.NewSystemVar(varName, typ), setVal)
+				return sf.WithChildren(newSystemVar(sf.Left.String(), typ), setVal)
 			}
 
 			if isUserVariable(uc) {
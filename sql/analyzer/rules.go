@@ -88,6 +88,8 @@ var OnceAfterDefault = []Rule{
 	{"apply_triggers", applyTriggers},
 	{"apply_procedures", applyProcedures},
 	{"apply_row_update_accumulators", applyUpdateAccumulators},
+	{"apply_primary_key_lookups", applyPrimaryKeyLookups},
+	{"apply_locking_reads", applyLockingReads},
 }
 
 // OnceAfterAll contains the rules to be applied just once after all other
@@ -95,6 +97,7 @@ var OnceAfterDefault = []Rule{
 var OnceAfterAll = []Rule{
 	{"track_process", trackProcess},
 	{"parallelize", parallelize},
+	{"optimize_group_by", optimizeGroupBy},
 	{"clear_warnings", clearWarnings},
 }
 
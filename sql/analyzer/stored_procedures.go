@@ -39,6 +39,9 @@ func loadStoredProcedures(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scop
 		} else if _, ok := n.(*plan.ShowProcedureStatus); ok {
 			referencesProcedures = true
 			return false
+		} else if _, ok := n.(*plan.ShowCreateProcedure); ok {
+			referencesProcedures = true
+			return false
 		}
 		return true
 	})
@@ -304,6 +307,8 @@ func applyProcedures(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (s
 			return applyProceduresCall(ctx, a, n, scope)
 		case *plan.ShowProcedureStatus:
 			return applyProceduresShowProcedure(ctx, a, n, scope)
+		case *plan.ShowCreateProcedure:
+			return applyProceduresShowCreateProcedure(ctx, a, n, scope)
 		default:
 			return n, nil
 		}
@@ -399,3 +404,14 @@ func applyProceduresShowProcedure(ctx *sql.Context, a *Analyzer, n *plan.ShowPro
 	n.Procedures = a.ProcedureCache.AllForDatabase(ctx.GetCurrentDatabase())
 	return n, nil
 }
+
+// applyProceduresShowCreateProcedure applies the stored procedure named by the given *plan.ShowCreateProcedure.
+func applyProceduresShowCreateProcedure(ctx *sql.Context, a *Analyzer, n *plan.ShowCreateProcedure, scope *Scope) (sql.Node, error) {
+	procedure := a.ProcedureCache.Get(n.Database().Name(), n.ProcedureName)
+	if procedure == nil {
+		return nil, sql.ErrStoredProcedureDoesNotExist.New(n.ProcedureName)
+	}
+	nn := *n
+	nn.Procedure = procedure
+	return &nn, nil
+}
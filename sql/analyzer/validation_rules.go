@@ -39,6 +39,8 @@ const (
 	validateExplodeUsageRule      = "validate_explode_usage"
 	validateSubqueryColumnsRule   = "validate_subquery_columns"
 	validateUnionSchemasMatchRule = "validate_union_schemas_match"
+	validateSafeUpdatesRule       = "validate_safe_updates"
+	validateCollationsRule        = "validate_collations"
 )
 
 var (
@@ -109,6 +111,8 @@ var DefaultValidationRules = []Rule{
 	{validateExplodeUsageRule, validateExplodeUsage},
 	{validateSubqueryColumnsRule, validateSubqueryColumns},
 	{validateUnionSchemasMatchRule, validateUnionSchemasMatch},
+	{validateSafeUpdatesRule, validateSafeUpdates},
+	{validateCollationsRule, validateCollations},
 }
 
 func validateIsResolved(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
@@ -473,6 +477,85 @@ func validateSubqueryColumns(ctx *sql.Context, a *Analyzer, n sql.Node, scope *S
 	return n, nil
 }
 
+// validateCollations returns ErrCollationCharsetMismatch if a COLLATE clause gives a collation that isn't valid for the
+// character set of the expression it applies to, and ErrIllegalMixOfCollations if the two sides of a comparison have
+// COLLATE clauses giving different collations.
+func validateCollations(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
+	var err error
+	plan.InspectExpressions(n, func(e sql.Expression) bool {
+		switch e := e.(type) {
+		case *expression.Collate:
+			_, err = e.ResolvedCollation()
+		case expression.Comparer:
+			_, _, err = expression.ComparisonCollation(e.Left(), e.Right())
+		}
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// validateSafeUpdates returns ErrUpdateWithoutKeyInSafeMode for an UPDATE or DELETE that neither looks its rows up by a
+// key nor has a LIMIT when the sql_safe_updates session variable is on. As in MySQL, a WHERE clause that can't use a
+// key doesn't make a statement safe.
+func validateSafeUpdates(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope) (sql.Node, error) {
+	if !safeUpdatesEnabled(ctx) {
+		return n, nil
+	}
+
+	var err error
+	plan.Inspect(n, func(n sql.Node) bool {
+		var child sql.Node
+		switch n := n.(type) {
+		case *plan.Update:
+			child = n.Child
+		case *plan.DeleteFrom:
+			child = n.Child
+		default:
+			return err == nil
+		}
+		if !usesKeyOrLimit(child) {
+			err = sql.ErrUpdateWithoutKeyInSafeMode.New()
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// safeUpdatesEnabled returns whether the sql_safe_updates session variable is on for the context given.
+func safeUpdatesEnabled(ctx *sql.Context) bool {
+	if ctx.Session == nil {
+		return false
+	}
+	_, val := ctx.Get(sql.SQLSafeUpdatesSessionVar)
+	if val == nil {
+		return false
+	}
+	enabled, err := sql.ConvertToBool(val)
+	return err == nil && enabled
+}
+
+// usesKeyOrLimit returns whether the rows of the node given are read with an index or primary key lookup, or limited
+// by a LIMIT clause.
+func usesKeyOrLimit(n sql.Node) bool {
+	safe := false
+	plan.Inspect(n, func(n sql.Node) bool {
+		switch n.(type) {
+		case *plan.Limit, *plan.IndexedTableAccess, *plan.PrimaryKeyLookup:
+			safe = true
+		}
+		return !safe
+	})
+	return safe
+}
+
 func stringContains(strs []string, target string) bool {
 	for _, s := range strs {
 		if s == target {
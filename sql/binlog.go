@@ -0,0 +1,83 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "time"
+
+// BinlogFormat is the format DML statements are logged in, as set by the binlog_format session variable.
+type BinlogFormat string
+
+const (
+	// BinlogFormatStatement logs the text of the statements.
+	BinlogFormatStatement BinlogFormat = "STATEMENT"
+	// BinlogFormatRow logs the rows changed by the statements.
+	BinlogFormatRow BinlogFormat = "ROW"
+	// BinlogFormatMixed logs the text of the statements, unless they aren't deterministic, as reported by
+	// IsDeterministic, in which case the rows they changed are logged instead.
+	BinlogFormatMixed BinlogFormat = "MIXED"
+)
+
+// BinlogWriter writes the DML statements of committed transactions to a log, for instance to feed a replica. The
+// writer of a catalog is given the events of every transaction committed with Catalog.CommitTransaction that changed
+// data. Rolled back transactions are never written.
+type BinlogWriter interface {
+	// WriteEvents is called after every successful commit that changed data, in commit order, with the events of the
+	// statements of the transaction in the order they ran. An error is returned by the commit, but the transaction
+	// stays committed.
+	WriteEvents(ctx *Context, events []BinlogEvent) error
+}
+
+// BinlogEvent describes a DML statement that changed data.
+type BinlogEvent struct {
+	// Query is the text of the statement.
+	Query string
+	// Timestamp is the time the statement started at.
+	Timestamp time.Time
+	// Format is the format the statement is logged in: BinlogFormatStatement or BinlogFormatRow, never
+	// BinlogFormatMixed.
+	Format BinlogFormat
+	// Tables are the tables changed by the statement, in the order they were first changed.
+	Tables []TableChange
+	// Rows are the rows changed by the statement, in the order they were changed, if it's logged in row format.
+	Rows []BinlogRowChange
+}
+
+// BinlogRowChange is a row changed by a statement logged in row format. Before is nil for inserted rows and After is
+// nil for deleted ones.
+type BinlogRowChange struct {
+	Database string
+	Table    string
+	Before   Row
+	After    Row
+}
+
+// NewBinlogEvent returns a new BinlogEvent for the statement given, without any change.
+func NewBinlogEvent(query string, timestamp time.Time, format BinlogFormat) *BinlogEvent {
+	return &BinlogEvent{Query: query, Timestamp: timestamp, Format: format}
+}
+
+// RecordTable adds the rows affected in the table given to the tables changed by the statement.
+func (e *BinlogEvent) RecordTable(db, table string, rowsAffected int) {
+	e.Tables = addTableChange(e.Tables, db, table, rowsAffected)
+}
+
+// RecordRow adds a row changed in the table given to the rows of the statement. Rows are only kept for statements
+// logged in row format.
+func (e *BinlogEvent) RecordRow(db, table string, before, after Row) {
+	if e.Format != BinlogFormatRow {
+		return
+	}
+	e.Rows = append(e.Rows, BinlogRowChange{Database: db, Table: table, Before: before, After: after})
+}
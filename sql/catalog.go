@@ -37,15 +37,43 @@ var ErrAsOfNotSupported = errors.NewKind("AS OF not supported for database %s")
 // expression with a view when the view definition has its own AS OF expressions.
 var ErrIncompatibleAsOf = errors.NewKind("incompatible use of AS OF: %s")
 
+// PrivilegeReloader is implemented by integrators that keep the grants of their users in their own storage, such as
+// grant tables, and cache them in memory. FLUSH PRIVILEGES calls Reload so that changes made directly to that storage
+// take effect.
+type PrivilegeReloader interface {
+	// Reload reads the grants from their storage again. If it fails, implementations must keep the grants they had
+	// before, rather than any partially read ones.
+	Reload(ctx *Context) error
+}
+
 // Catalog holds databases, tables and functions.
 type Catalog struct {
 	FunctionRegistry
+	// UserFunctions are the user-defined functions that can be registered while the engine is running.
+	UserFunctions *UserFunctionRegistry
 	*ProcessList
 	*MemoryManager
+	// TableResolver, if set, is consulted before the databases to resolve table names. See TableResolver.
+	TableResolver TableResolver
+	// GlobalStatus holds the counters of the global status variables, which count the statements of every session.
+	GlobalStatus *StatusCounters
+	// PrivilegeReloader, if set, is reloaded by FLUSH PRIVILEGES. See PrivilegeReloader.
+	PrivilegeReloader PrivilegeReloader
+	// BinlogWriter, if set, is given the DML statements of the transactions committed with CommitTransaction. See
+	// BinlogWriter.
+	BinlogWriter BinlogWriter
+	// StorageEngines are the storage engines supported by the server: the ones listed by INFORMATION_SCHEMA.ENGINES,
+	// and the ones CREATE TABLE and default_storage_engine accept. NewCatalog sets them to SupportedEngines.
+	StorageEngines []Engine
 
 	mu    sync.RWMutex
 	dbs   Databases
 	locks sessionLocks
+
+	// commitMu serializes commits, so the change listeners and the binlog writer see them in commit order
+	commitMu        sync.Mutex
+	changeListeners []ChangeListener
+	changeSequence  uint64
 }
 
 type tableLocks map[string]struct{}
@@ -56,14 +84,61 @@ type sessionLocks map[uint32]dbLocks
 
 // NewCatalog returns a new empty Catalog.
 func NewCatalog() *Catalog {
+	builtins := NewFunctionRegistry()
 	return &Catalog{
-		FunctionRegistry: NewFunctionRegistry(),
+		FunctionRegistry: builtins,
+		UserFunctions:    NewUserFunctionRegistry(builtins),
 		MemoryManager:    NewMemoryManager(ProcessMemory),
 		ProcessList:      NewProcessList(),
+		GlobalStatus:     NewStatusCounters(),
+		StorageEngines:   append([]Engine(nil), SupportedEngines...),
 		locks:            make(sessionLocks),
 	}
 }
 
+// Function returns the function with the given name, looking at the user-defined functions first.
+func (c *Catalog) Function(name string) (Function, error) {
+	if c.UserFunctions == nil {
+		return c.FunctionRegistry.Function(name)
+	}
+	return c.UserFunctions.Function(name)
+}
+
+// AddChangeListener registers a listener notified of every transaction committed with CommitTransaction that changed
+// data.
+func (c *Catalog) AddChangeListener(l ChangeListener) {
+	c.commitMu.Lock()
+	defer c.commitMu.Unlock()
+	c.changeListeners = append(c.changeListeners, l)
+}
+
+// CommitTransaction commits the current transaction of the session in the context given with CommitSessionTransaction,
+// then delivers the changes of the transaction to the change listeners and its binlog events to the BinlogWriter.
+// Nothing is delivered if the commit fails.
+func (c *Catalog) CommitTransaction(ctx *Context, dbName string) error {
+	c.commitMu.Lock()
+	defer c.commitMu.Unlock()
+
+	if err := CommitSessionTransaction(ctx, dbName); err != nil {
+		return err
+	}
+
+	tables := ctx.PendingChanges().Take()
+	if len(tables) > 0 && len(c.changeListeners) > 0 {
+		c.changeSequence++
+		changes := ChangeSet{Sequence: c.changeSequence, SessionID: ctx.ID(), Tables: tables}
+		for _, l := range c.changeListeners {
+			l.TransactionCommitted(ctx, changes)
+		}
+	}
+
+	events := ctx.PendingChanges().TakeBinlogEvents()
+	if len(events) > 0 && c.BinlogWriter != nil {
+		return c.BinlogWriter.WriteEvents(ctx, events)
+	}
+	return nil
+}
+
 // AllDatabases returns all databases in the catalog.
 func (c *Catalog) AllDatabases() Databases {
 	c.mu.RLock()
@@ -103,13 +178,37 @@ func (c *Catalog) Database(db string) (Database, error) {
 	return c.dbs.Database(db)
 }
 
-// Table returns the table in the given database with the given name.
+// Table returns the table in the given database with the given name. The temporary tables of the session are consulted
+// first, then the TableResolver of the catalog, if any. Either way, the table comes with the database of the catalog it
+// names, or nil if there is none.
 func (c *Catalog) Table(ctx *Context, db, table string) (Table, Database, error) {
+	if ctx != nil && ctx.Session != nil {
+		if t, ok := ctx.TemporaryTables().Table(db, table); ok {
+			return t, c.databaseOrNil(db), nil
+		}
+	}
+
+	t, db, table, err := c.resolveTable(ctx, db, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t != nil {
+		return t, c.databaseOrNil(db), nil
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 	return c.dbs.Table(ctx, db, table)
 }
 
+func (c *Catalog) databaseOrNil(db string) Database {
+	database, err := c.Database(db)
+	if err != nil {
+		return nil
+	}
+	return database
+}
+
 // TableAsOf returns the table in the given database with the given name, as it existed at the time given. The database
 // named must support timed queries.
 func (c *Catalog) TableAsOf(ctx *Context, db, table string, time interface{}) (Table, Database, error) {
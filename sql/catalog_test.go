@@ -136,3 +136,57 @@ func (l *lockableTable) Unlock(ctx *sql.Context, id uint32) error {
 	l.unlocks++
 	return nil
 }
+
+type synonymResolver map[string]sql.TableAlias
+
+func (r synonymResolver) Resolve(ctx *sql.Context, db, name string) (sql.Table, error) {
+	if alias, ok := r[name]; ok {
+		return nil, alias
+	}
+	return nil, sql.ErrTableNotFound.New(name)
+}
+
+func TestCatalogTableResolver(t *testing.T) {
+	require := require.New(t)
+
+	c := sql.NewCatalog()
+	ctx := sql.NewEmptyContext()
+
+	foo := memory.NewDatabase("foo")
+	c.AddDatabase(foo)
+	bar := memory.NewTable("bar", nil)
+	foo.AddTable("bar", bar)
+	other := memory.NewDatabase("other")
+	c.AddDatabase(other)
+	baz := memory.NewTable("baz", nil)
+	other.AddTable("baz", baz)
+
+	c.TableResolver = synonymResolver{
+		"syn":      {Name: "bar"},
+		"syn2":     {Name: "syn"},
+		"otherbaz": {Database: "other", Name: "baz"},
+		"loop1":    {Name: "loop2"},
+		"loop2":    {Name: "loop1"},
+	}
+
+	table, db, err := c.Table(ctx, "foo", "bar")
+	require.NoError(err)
+	require.Equal(bar, table)
+	require.Equal(foo, db)
+
+	table, db, err = c.Table(ctx, "foo", "syn2")
+	require.NoError(err)
+	require.Equal(bar, table)
+	require.Equal(foo, db)
+
+	table, db, err = c.Table(ctx, "foo", "otherbaz")
+	require.NoError(err)
+	require.Equal(baz, table)
+	require.Equal(other, db)
+
+	_, _, err = c.Table(ctx, "foo", "loop1")
+	require.True(sql.ErrTableAliasLoop.Is(err))
+
+	_, _, err = c.Table(ctx, "foo", "missing")
+	require.True(sql.ErrTableNotFound.Is(err))
+}
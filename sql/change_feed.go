@@ -0,0 +1,137 @@
+// Copyright 2020-2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"strings"
+	"sync"
+)
+
+// TableChange is the number of rows of a table affected by the statements of a transaction.
+type TableChange struct {
+	Database     string
+	Table        string
+	RowsAffected int
+}
+
+// ChangeSet describes the changes of a committed transaction.
+type ChangeSet struct {
+	// Sequence is the position of the commit among the commits delivered by the catalog, starting at 1.
+	Sequence uint64
+	// SessionID is the ID of the session that committed the transaction.
+	SessionID uint32
+	// Tables are the tables changed by the transaction, in the order they were first changed.
+	Tables []TableChange
+}
+
+// ChangeListener is notified of the transactions committed through a Catalog, for instance to feed a replica.
+type ChangeListener interface {
+	// TransactionCommitted is called after every successful commit that changed data, in commit order. Rolled back
+	// transactions are never delivered. ROLLBACK only discards the changes pending delivery: with databases that
+	// don't support transactions, such as the memory database, the rows it changed stay changed, and are never
+	// delivered.
+	TransactionCommitted(ctx *Context, changes ChangeSet)
+}
+
+// PendingChanges accumulates the changes of the current transaction of a session, until it's committed or rolled
+// back. A nil PendingChanges records nothing.
+type PendingChanges struct {
+	mu     sync.Mutex
+	tables []TableChange
+	events []BinlogEvent
+}
+
+// NewPendingChanges returns an empty PendingChanges.
+func NewPendingChanges() *PendingChanges {
+	return &PendingChanges{}
+}
+
+// Record adds the rows affected in the table given to the changes of the transaction.
+func (p *PendingChanges) Record(db, table string, rowsAffected int) {
+	if p == nil || rowsAffected == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tables = addTableChange(p.tables, db, table, rowsAffected)
+}
+
+// addTableChange adds the rows affected in the table given to the changes given and returns them.
+func addTableChange(tables []TableChange, db, table string, rowsAffected int) []TableChange {
+	for i := range tables {
+		if strings.EqualFold(tables[i].Database, db) && strings.EqualFold(tables[i].Table, table) {
+			tables[i].RowsAffected += rowsAffected
+			return tables
+		}
+	}
+	return append(tables, TableChange{Database: db, Table: table, RowsAffected: rowsAffected})
+}
+
+// RecordBinlogEvent adds the binlog event of a statement to the changes of the transaction. Events of statements that
+// didn't change any table are ignored.
+func (p *PendingChanges) RecordBinlogEvent(event BinlogEvent) {
+	if p == nil || len(event.Tables) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+// TakeBinlogEvents returns the binlog events recorded since the last call to TakeBinlogEvents or Discard, and clears
+// them.
+func (p *PendingChanges) TakeBinlogEvents() []BinlogEvent {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	events := p.events
+	p.events = nil
+	return events
+}
+
+// Take returns the changes recorded since the last call to Take or Discard, and clears them.
+func (p *PendingChanges) Take() []TableChange {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tables := p.tables
+	p.tables = nil
+	return tables
+}
+
+// Empty returns whether no changes were recorded since the last call to Take or Discard.
+func (p *PendingChanges) Empty() bool {
+	if p == nil {
+		return true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.tables) == 0 && len(p.events) == 0
+}
+
+// Discard clears the changes and the binlog events recorded, as done when the transaction is rolled back.
+func (p *PendingChanges) Discard() {
+	_ = p.Take()
+	_ = p.TakeBinlogEvents()
+}
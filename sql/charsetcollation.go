@@ -16,6 +16,7 @@ package sql
 
 import (
 	"fmt"
+	"strings"
 
 	"gopkg.in/src-d/go-errors.v1"
 )
@@ -1183,6 +1184,14 @@ var (
 
 	ErrCharacterSetNotSupported = errors.NewKind("Unknown character set: %v")
 	ErrCollationNotSupported    = errors.NewKind("Unknown collation: %v")
+
+	// ErrCollationCharsetMismatch is returned when a COLLATE clause gives a collation of another character set than
+	// the one of the value it applies to.
+	ErrCollationCharsetMismatch = errors.NewKind("COLLATION '%s' is not valid for CHARACTER SET '%s'")
+
+	// ErrIllegalMixOfCollations is returned when the two sides of a comparison have COLLATE clauses giving different
+	// collations.
+	ErrIllegalMixOfCollations = errors.NewKind("Illegal mix of collations (%s,EXPLICIT) and (%s,EXPLICIT) for comparison")
 )
 
 const (
@@ -1317,6 +1326,90 @@ func (c Collation) String() string {
 	return string(c)
 }
 
+// IsCaseInsensitive returns whether strings that differ only in case are equal under the Collation. Binary and
+// case-sensitive collations are not.
+func (c Collation) IsCaseInsensitive() bool {
+	return strings.HasSuffix(string(c), "_ci")
+}
+
+// IsAccentInsensitive returns whether strings that differ only in the accents of their letters are equal under the
+// Collation, as in the `_ai_` collations such as utf8mb4_0900_ai_ci. Other collations are accent-sensitive.
+func (c Collation) IsAccentInsensitive() bool {
+	return strings.Contains(string(c), "_ai_") || strings.HasSuffix(string(c), "_ai")
+}
+
+// ResolveCollation returns the collation a value with the collation given has when a COLLATE clause overrides it, such
+// as in `col COLLATE utf8mb4_bin = 'a'`. An empty override keeps the collation of the value. The override must belong
+// to the character set of the value, which is the one of its column for a column, or the connection character set for
+// a literal, or ErrCollationCharsetMismatch is returned.
+func ResolveCollation(columnCollation, overrideCollation Collation) (Collation, error) {
+	if overrideCollation == "" {
+		return columnCollation, nil
+	}
+	if cs := columnCollation.CharacterSet(); !overrideCollation.WorksWithCharacterSet(cs) {
+		return "", ErrCollationCharsetMismatch.New(overrideCollation, cs)
+	}
+	return overrideCollation, nil
+}
+
+// CompareCollated compares two strings under the collation given: without regard to the accents of Latin letters if
+// the collation is accent-insensitive, without regard to case if it's case-insensitive, and otherwise byte by byte.
+func CompareCollated(collation Collation, a, b string) int {
+	if collation.IsAccentInsensitive() {
+		a, b = foldAccents(a), foldAccents(b)
+	}
+	if collation.IsCaseInsensitive() {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// accentedLetters are the accented Latin letters of the Latin-1 Supplement and Latin Extended-A blocks folded by
+// foldAccents, by the letter they fold to.
+var accentedLetters = map[rune]string{
+	'A': "ÀÁÂÃÄÅĀĂĄ", 'a': "àáâãäåāăą",
+	'C': "ÇĆĈĊČ", 'c': "çćĉċč",
+	'D': "ĎĐ", 'd': "ďđ",
+	'E': "ÈÉÊËĒĔĖĘĚ", 'e': "èéêëēĕėęě",
+	'G': "ĜĞĠĢ", 'g': "ĝğġģ",
+	'H': "ĤĦ", 'h': "ĥħ",
+	'I': "ÌÍÎÏĨĪĬĮ", 'i': "ìíîïĩīĭį",
+	'J': "Ĵ", 'j': "ĵ",
+	'K': "Ķ", 'k': "ķ",
+	'L': "ĹĻĽĿŁ", 'l': "ĺļľŀł",
+	'N': "ÑŃŅŇ", 'n': "ñńņň",
+	'O': "ÒÓÔÕÖØŌŎŐ", 'o': "òóôõöøōŏő",
+	'R': "ŔŖŘ", 'r': "ŕŗř",
+	'S': "ŚŜŞŠ", 's': "śŝşš",
+	'T': "ŢŤŦ", 't': "ţťŧ",
+	'U': "ÙÚÛÜŨŪŬŮŰŲ", 'u': "ùúûüũūŭůűų",
+	'W': "Ŵ", 'w': "ŵ",
+	'Y': "ÝŶŸ", 'y': "ýÿŷ",
+	'Z': "ŹŻŽ", 'z': "źżž",
+}
+
+// accentFolds maps every accented letter of accentedLetters to the letter it folds to.
+var accentFolds = func() map[rune]rune {
+	folds := make(map[rune]rune)
+	for base, accented := range accentedLetters {
+		for _, r := range accented {
+			folds[r] = base
+		}
+	}
+	return folds
+}()
+
+// foldAccents returns the string given with its accented Latin letters replaced by the letters without accents, keeping
+// their case. Other characters are kept.
+func foldAccents(s string) string {
+	return strings.Map(func(r rune) rune {
+		if base, ok := accentFolds[r]; ok {
+			return base
+		}
+		return r
+	}, s)
+}
+
 // ID returns the id of the Collation.
 func (c Collation) ID() int64 {
 	s, ok := CollationToMySQLVals[c]
@@ -69,3 +69,51 @@ func testParseCollation(t *testing.T, charset *string, collation *string, binary
 		}
 	})
 }
+
+func TestResolveCollation(t *testing.T) {
+	tests := []struct {
+		column      Collation
+		override    Collation
+		expected    Collation
+		expectedErr bool
+	}{
+		{Collation_utf8mb4_0900_ai_ci, "", Collation_utf8mb4_0900_ai_ci, false},
+		{Collation_utf8mb4_0900_ai_ci, Collation_utf8mb4_bin, Collation_utf8mb4_bin, false},
+		{Collation_latin1_swedish_ci, Collation_latin1_bin, Collation_latin1_bin, false},
+		{Collation_utf8mb4_0900_ai_ci, Collation_latin1_swedish_ci, "", true},
+		{Collation_latin1_swedish_ci, Collation_utf8mb4_bin, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%v %v", test.column, test.override), func(t *testing.T) {
+			collation, err := ResolveCollation(test.column, test.override)
+			if test.expectedErr {
+				require.True(t, ErrCollationCharsetMismatch.Is(err), "unexpected error %v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, collation)
+			}
+		})
+	}
+}
+
+func TestCompareCollated(t *testing.T) {
+	assert.Equal(t, 0, CompareCollated(Collation_utf8mb4_0900_ai_ci, "abc", "ABC"))
+	assert.Equal(t, -1, CompareCollated(Collation_utf8mb4_0900_ai_ci, "a", "B"))
+	assert.Equal(t, 1, CompareCollated(Collation_utf8mb4_bin, "abc", "ABC"))
+	assert.Equal(t, 1, CompareCollated(Collation_utf8mb4_bin, "a", "B"))
+
+	// accents are only ignored by accent-insensitive collations
+	assert.Equal(t, 0, CompareCollated(Collation_utf8mb4_0900_ai_ci, "résumé", "RESUME"))
+	assert.Equal(t, 0, CompareCollated(Collation_utf8mb4_0900_ai_ci, "Łódź", "lodz"))
+	assert.Equal(t, -1, CompareCollated(Collation_utf8mb4_0900_ai_ci, "é", "f"))
+	assert.Equal(t, 1, CompareCollated(Collation_utf8mb4_0900_as_cs, "é", "e"))
+	assert.Equal(t, 1, CompareCollated(Collation_utf8mb4_bin, "é", "e"))
+}
+
+func TestCollationAccentInsensitive(t *testing.T) {
+	assert.True(t, Collation_utf8mb4_0900_ai_ci.IsAccentInsensitive())
+	assert.False(t, Collation_utf8mb4_0900_as_cs.IsAccentInsensitive())
+	assert.False(t, Collation_utf8mb4_bin.IsAccentInsensitive())
+	assert.False(t, Collation_utf8mb4_general_ci.IsAccentInsensitive())
+}
@@ -40,12 +40,29 @@ type Column struct {
 	Comment string
 	// Extra contains any additional information to put in the `extra` column under `information_schema.columns`.
 	Extra string
+	// Generated contains the expression computing the value of a generated column, or nil if the column isn't
+	// generated. Generated columns can't be assigned to, and are recomputed from the other columns of the row.
+	Generated *ColumnDefaultValue
+	// Virtual is true if a generated column is computed when read rather than stored. Tables should not store the
+	// values of virtual columns.
+	Virtual bool
+}
+
+// IsGenerated returns whether the column is a generated column.
+func (c *Column) IsGenerated() bool {
+	return c.Generated != nil
+}
+
+// IsVirtual returns whether the column is a virtual generated column, whose values aren't stored but computed when
+// read. Tables store virtual columns as null, whether or not they're nullable.
+func (c *Column) IsVirtual() bool {
+	return c.Generated != nil && c.Virtual
 }
 
 // Check ensures the value is correct for this column.
 func (c *Column) Check(v interface{}) bool {
 	if v == nil {
-		return c.Nullable
+		return c.Nullable || c.IsVirtual()
 	}
 
 	_, err := c.Type.Convert(v)
@@ -58,5 +75,7 @@ func (c *Column) Equals(c2 *Column) bool {
 		c.Source == c2.Source &&
 		c.Nullable == c2.Nullable &&
 		reflect.DeepEqual(c.Default, c2.Default) &&
+		reflect.DeepEqual(c.Generated, c2.Generated) &&
+		c.Virtual == c2.Virtual &&
 		reflect.DeepEqual(c.Type, c2.Type)
 }
@@ -82,6 +82,38 @@ type NonDeterministicExpression interface {
 	IsNonDeterministic() bool
 }
 
+// StatementNonDeterministicExpression allows a way for expressions to declare that, even though their result doesn't
+// change during the execution of a single statement, executing the same statement again can produce a different result.
+// Functions that depend on the current time or on the connection, like NOW() or CONNECTION_ID(), are in this category.
+type StatementNonDeterministicExpression interface {
+	Expression
+	// IsStatementNonDeterministic returns whether this expression can return a different result when the statement
+	// containing it is executed again, for example on a replica.
+	IsStatementNonDeterministic() bool
+}
+
+// IsDeterministic returns whether the expression given, including all its children, always produces the same result
+// for the same input when the statement containing it is executed again. Such expressions are safe to use in
+// statement-based replication.
+// Subqueries are taken as non-deterministic unless their results can be cached.
+func IsDeterministic(expr Expression) bool {
+	deterministic := true
+	Inspect(expr, func(e Expression) bool {
+		switch e := e.(type) {
+		case NonDeterministicExpression:
+			if e.IsNonDeterministic() {
+				deterministic = false
+			}
+		case StatementNonDeterministicExpression:
+			if e.IsStatementNonDeterministic() {
+				deterministic = false
+			}
+		}
+		return deterministic
+	})
+	return deterministic
+}
+
 // Aggregation implements an aggregation expression, where an
 // aggregation buffer is created for each grouping (NewBuffer) and rows in the
 // grouping are fed to the buffer (Update). Multiple buffers can be merged
@@ -252,6 +284,17 @@ type PartitionCounter interface {
 	PartitionCount(*Context) (int64, error)
 }
 
+// ParallelTable is a table that tells the engine whether its partitions can be read in parallel. When the analyzer
+// reads partitions in parallel, it doesn't use more goroutines for such a table than it has partitions, and reads the
+// table sequentially if its partitions can't be read concurrently. Tables that don't implement it are read in
+// parallel whenever the analyzer is configured to.
+type ParallelTable interface {
+	Table
+	PartitionCounter
+	// ConcurrentPartitions returns whether different partitions of the table can be read at the same time.
+	ConcurrentPartitions(*Context) bool
+}
+
 // FilteredTable is a table that can produce a specific RowIter
 // that's more optimized given the filters.
 type FilteredTable interface {
@@ -354,6 +397,15 @@ type ForeignKeyTable interface {
 	GetForeignKeys(ctx *Context) ([]ForeignKeyConstraint, error)
 }
 
+// ForeignKeyEnforcingTable is a table that applies the ON DELETE and ON UPDATE actions of the foreign keys referencing
+// it itself, such as one whose storage cascades changes. The engine doesn't apply them when its rows are deleted or
+// updated.
+type ForeignKeyEnforcingTable interface {
+	Table
+	// EnforcesForeignKeys returns whether the table applies the actions of the foreign keys referencing it itself.
+	EnforcesForeignKeys() bool
+}
+
 // ForeignKeyAlterableTable represents a table that supports foreign key modification operations.
 type ForeignKeyAlterableTable interface {
 	Table
@@ -456,6 +508,37 @@ type Closer interface {
 	Close(*Context) error
 }
 
+// CloserFunc is a function used as a Closer.
+type CloserFunc func(*Context) error
+
+// Close implements the Closer interface.
+func (f CloserFunc) Close(ctx *Context) error {
+	return f(ctx)
+}
+
+// CloseAll closes every closer given in order, nil ones excepted, even if some of them fail. Returns the error of the
+// only closer that failed, or ErrCloseFailed with all the errors if more than one did.
+func CloseAll(ctx *Context, closers ...Closer) error {
+	var errs []error
+	for _, c := range closers {
+		if c == nil {
+			continue
+		}
+		if err := c.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return ErrCloseFailed.New(len(errs), errs)
+	}
+}
+
 // RowReplacer is a combination of RowDeleter and RowInserter.
 // TODO: We can't embed those interfaces because go 1.13 doesn't allow for overlapping interfaces (they both declare
 //  Close). Go 1.14 fixes this problem, but we aren't ready to drop support for 1.13 yet.
@@ -480,6 +563,15 @@ type ReplaceableTable interface {
 	Replacer(ctx *Context) RowReplacer
 }
 
+// PrimaryKeyTable is a table that can look up a row directly by its primary key. The analyzer uses it instead of a
+// table scan when an UPDATE or DELETE filters on equality with every column of the primary key.
+type PrimaryKeyTable interface {
+	Table
+	// GetRowByKey returns the row with the primary key given, and whether it exists. The key has a value for each
+	// primary key column, in schema order, converted to the type of the column.
+	GetRowByKey(ctx *Context, key Row) (Row, bool, error)
+}
+
 // UpdateableTable is a table that can process updates of existing rows via update statements.
 type UpdatableTable interface {
 	Table
@@ -496,6 +588,14 @@ type RowUpdater interface {
 	Closer
 }
 
+// RollbackableRowUpdater is a RowUpdater that can undo the updates it applied, so that a statement failing partway
+// leaves the table unchanged even without a transaction.
+type RollbackableRowUpdater interface {
+	RowUpdater
+	// Rollback discards every update applied by the updater. It's called instead of Close when the statement fails.
+	Rollback(ctx *Context) error
+}
+
 // Database represents the database.
 type Database interface {
 	Nameable
@@ -630,6 +730,24 @@ type TableCreator interface {
 	CreateTable(ctx *Context, name string, schema Schema) error
 }
 
+// EngineTableCreator is a TableCreator that creates tables of different storage engines. CREATE TABLE calls
+// CreateTableWithEngine rather than CreateTable, with the name of the engine given by its ENGINE option or, without
+// one, by the default_storage_engine of the session. The name is always one of Catalog.StorageEngines.
+type EngineTableCreator interface {
+	TableCreator
+	// CreateTableWithEngine creates the table with the given name and schema, stored by the engine given. If a table
+	// with that name already exists, must return sql.ErrTableAlreadyExists.
+	CreateTableWithEngine(ctx *Context, name string, schema Schema, engine string) error
+}
+
+// TemporaryTableCreator should be implemented by databases that can create temporary tables, which belong to the
+// session creating them rather than to the database. See TemporaryTableRegistry.
+type TemporaryTableCreator interface {
+	// CreateTemporaryTable returns a new table with the given name and schema. The table must not be added to the
+	// database.
+	CreateTemporaryTable(ctx *Context, name string, schema Schema) (Table, error)
+}
+
 // ViewCreator should be implemented by databases that want to know when a view
 // has been created.
 type ViewCreator interface {
@@ -62,3 +62,31 @@ func TestEvaluateCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestCloseAll(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	var closed []int
+	closer := func(i int, err error) sql.Closer {
+		return sql.CloserFunc(func(*sql.Context) error {
+			closed = append(closed, i)
+			return err
+		})
+	}
+
+	require.NoError(sql.CloseAll(ctx, closer(1, nil), nil, closer(2, nil)))
+	require.Equal([]int{1, 2}, closed)
+
+	closed = nil
+	first := fmt.Errorf("first")
+	require.Equal(first, sql.CloseAll(ctx, closer(1, first), closer(2, nil)))
+	require.Equal([]int{1, 2}, closed)
+
+	closed = nil
+	err := sql.CloseAll(ctx, closer(1, first), closer(2, fmt.Errorf("second")))
+	require.True(sql.ErrCloseFailed.Is(err))
+	require.Contains(err.Error(), "first")
+	require.Contains(err.Error(), "second")
+	require.Equal([]int{1, 2}, closed)
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "strings"
+
+// Dialect is the flavor of MySQL a session emulates, for the behaviors that differ between MySQL and MariaDB.
+type Dialect uint8
+
+const (
+	// Dialect_MySQL emulates MySQL. It's the default dialect of a session.
+	Dialect_MySQL Dialect = iota
+	// Dialect_MariaDB emulates MariaDB.
+	Dialect_MariaDB
+)
+
+// String implements fmt.Stringer.
+func (d Dialect) String() string {
+	switch d {
+	case Dialect_MariaDB:
+		return "MariaDB"
+	default:
+		return "MySQL"
+	}
+}
+
+// Version returns the server version reported by VERSION() and the version system variable under the dialect.
+func (d Dialect) Version() string {
+	switch d {
+	case Dialect_MariaDB:
+		return "10.5.9-MariaDB"
+	default:
+		return "8.0.11"
+	}
+}
+
+// VersionComment returns the value of the version_comment system variable under the dialect.
+func (d Dialect) VersionComment() string {
+	switch d {
+	case Dialect_MariaDB:
+		return "mariadb.org binary distribution"
+	default:
+		return "MySQL Community Server - GPL"
+	}
+}
+
+// dialectFunctions are the functions that only exist under one of the dialects. Every other function exists under all
+// of them.
+var dialectFunctions = map[string]Dialect{
+	"bin_to_uuid": Dialect_MySQL,
+	"is_uuid":     Dialect_MySQL,
+	"uuid_to_bin": Dialect_MySQL,
+	"nvl":         Dialect_MariaDB,
+}
+
+// HasFunction returns whether the function with the name given exists under the dialect.
+func (d Dialect) HasFunction(name string) bool {
+	fd, ok := dialectFunctions[strings.ToLower(name)]
+	return !ok || fd == d
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialectHasFunction(t *testing.T) {
+	require := require.New(t)
+
+	require.True(Dialect_MySQL.HasFunction("concat"))
+	require.True(Dialect_MariaDB.HasFunction("concat"))
+	require.True(Dialect_MySQL.HasFunction("UUID_TO_BIN"))
+	require.False(Dialect_MariaDB.HasFunction("UUID_TO_BIN"))
+	require.False(Dialect_MySQL.HasFunction("nvl"))
+	require.True(Dialect_MariaDB.HasFunction("nvl"))
+}
+
+func TestSessionDialect(t *testing.T) {
+	require := require.New(t)
+	ctx := NewEmptyContext()
+	require.Equal(Dialect_MySQL, ctx.Dialect())
+	_, v := ctx.Get("version")
+	require.Equal("8.0.11", v)
+	_, v = ctx.Get("version_comment")
+	require.Equal("MySQL Community Server - GPL", v)
+
+	ctx.Session.SetDialect(Dialect_MariaDB)
+	require.Equal(Dialect_MariaDB, ctx.Dialect())
+	_, v = ctx.Get("version")
+	require.Equal("10.5.9-MariaDB", v)
+	_, v = ctx.Get("version_comment")
+	require.Equal("mariadb.org binary distribution", v)
+
+	ctx.Session.SetDialect(Dialect_MySQL)
+	_, v = ctx.Get("version")
+	require.Equal("8.0.11", v)
+
+	// a version set by the integrator is kept
+	require.NoError(ctx.Session.Set(ctx, "version", LongText, "8.0.11-custom"))
+	ctx.Session.SetDialect(Dialect_MariaDB)
+	_, v = ctx.Get("version")
+	require.Equal("8.0.11-custom", v)
+	_, v = ctx.Get("version_comment")
+	require.Equal("mariadb.org binary distribution", v)
+}
@@ -14,7 +14,12 @@
 
 package sql
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
 
 // Engine represents a sql engine.
 type Engine struct {
@@ -26,10 +31,28 @@ type Engine struct {
 	savepoints  string
 }
 
+// SupportedEngines are the storage engines of a new Catalog, see Catalog.StorageEngines.
 var SupportedEngines = []Engine{
 	{Name: "InnoDB", support: "DEFAULT", comment: "Supports transactions, row-level locking, and foreign keys", transaction: "YES", xa: "YES", savepoints: "YES"},
 }
 
+// ErrUnknownStorageEngine is returned when setting default_storage_engine to an engine the catalog doesn't support.
+var ErrUnknownStorageEngine = errors.NewKind("Unknown storage engine '%s'")
+
+// DefaultStorageEngine is the storage engine of the tables created without an ENGINE option, unless sessions set
+// default_storage_engine to another one of the storage engines of the catalog.
+const DefaultStorageEngine = "InnoDB"
+
+// EngineByName returns the engine of the engines given with the name given, ignoring case.
+func EngineByName(engines []Engine, name string) (Engine, bool) {
+	for _, e := range engines {
+		if strings.EqualFold(e.Name, name) {
+			return e, true
+		}
+	}
+	return Engine{}, false
+}
+
 // Support returns the server's level of support for the storage engine,
 func (e Engine) Support() string {
 	support := e.support
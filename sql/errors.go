@@ -148,6 +148,10 @@ var (
 	// ErrUnknownSystemVariable is returned when a query references a system variable that doesn't exist
 	ErrUnknownSystemVariable = errors.NewKind(`Unknown system variable '%s'`)
 
+	// ErrGlobalOnlySystemVariable is returned when setting a system variable that only has a global value without SET
+	// GLOBAL
+	ErrGlobalOnlySystemVariable = errors.NewKind(`Variable '%s' is a GLOBAL variable and should be set with SET GLOBAL`)
+
 	// ErrInvalidUseOfOldNew is returned when a trigger attempts to make use of OLD or NEW references when they don't exist
 	ErrInvalidUseOfOldNew = errors.NewKind("There is no %s row in on %s trigger")
 
@@ -208,6 +212,56 @@ var (
 
 	// ErrSignalOnlySqlState is returned when SIGNAL/RESIGNAL references a DECLARE CONDITION for a MySQL error code.
 	ErrSignalOnlySqlState = errors.NewKind("SIGNAL/RESIGNAL can only use a condition defined with SQLSTATE")
+
+	// ErrUnknownTimeZone is returned when the time zone given isn't SYSTEM, a valid offset or a known time zone name.
+	ErrUnknownTimeZone = errors.NewKind("Unknown or incorrect time zone: '%s'")
+
+	// ErrCannotSetGeneratedColumn is returned when an UPDATE assigns a value to a generated column.
+	ErrCannotSetGeneratedColumn = errors.NewKind("The value specified for generated column '%s' in table '%s' is not allowed.")
+
+	// ErrForeignKeyChildViolation is returned when a parent row can't be deleted or updated because rows of a child
+	// table reference it through a foreign key without a CASCADE or SET NULL action.
+	ErrForeignKeyChildViolation = errors.NewKind("Cannot delete or update a parent row: a foreign key constraint fails (`%s`.`%s`, CONSTRAINT `%s` FOREIGN KEY (%s) REFERENCES `%s` (%s))")
+
+	// ErrForeignKeyDepthExceeded is returned when cascading foreign key actions go through too many tables.
+	ErrForeignKeyDepthExceeded = errors.NewKind("Foreign key cascade delete/update exceeds max depth of %d.")
+
+	// ErrReadOnlyTransaction is returned when a statement writing data or changing the schema is run by a read-only
+	// session. The argument is the option making the session read-only.
+	ErrReadOnlyTransaction = errors.NewKind("The MySQL server is running with the %s option so it cannot execute this statement")
+
+	// ErrQueryInterrupted is returned when a statement is aborted because its session was killed.
+	ErrQueryInterrupted = errors.NewKind("Query execution was interrupted")
+
+	// ErrUpdateWithoutKeyInSafeMode is returned when an UPDATE or DELETE neither uses a key to find its rows nor has a
+	// LIMIT while the sql_safe_updates session variable is on.
+	ErrUpdateWithoutKeyInSafeMode = errors.NewKind("You are using safe update mode and you tried to update a table without a WHERE that uses a KEY column")
+
+	// ErrLockNowait is returned by a LockingTable when a row of a locking read with NOWAIT is locked by another
+	// transaction.
+	ErrLockNowait = errors.NewKind("Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.")
+
+	// ErrQueryTimeout is returned when a statement is aborted because it ran for longer than it was allowed to.
+	ErrQueryTimeout = errors.NewKind("Query execution was interrupted, maximum statement execution time exceeded")
+
+	// ErrConnectionClosed is returned when a statement is aborted because its client closed the connection.
+	ErrConnectionClosed = errors.NewKind("Query execution was interrupted, the connection was closed")
+
+	// ErrSchemaChangedDuringQuery is returned when the schema of a table changes while a statement is writing to it.
+	ErrSchemaChangedDuringQuery = errors.NewKind("the schema of table %s changed during the query")
+
+	// ErrExpectedSingleRow is returned when a subquery used as a scalar value returns more than one row.
+	ErrExpectedSingleRow = errors.NewKind("the subquery returned more than 1 row")
+
+	// ErrMaxPreparedStmtCountReached is returned when a session that already has max_prepared_stmt_count prepared
+	// statements prepares another.
+	ErrMaxPreparedStmtCountReached = errors.NewKind("Can't create more than max_prepared_stmt_count statements (current value: %d)")
+
+	// ErrCloseFailed is returned by CloseAll when more than one of the closers given fails.
+	ErrCloseFailed = errors.NewKind("%d errors while closing: %v")
+
+	// ErrDeferredCleanup is returned when more than one of the cleanup functions registered with Context.Defer fails.
+	ErrDeferredCleanup = errors.NewKind("%d deferred cleanup functions failed: %v")
 )
 
 func CastSQLError(err error) (*mysql.SQLError, bool) {
@@ -219,14 +273,37 @@ func CastSQLError(err error) (*mysql.SQLError, bool) {
 	}
 
 	var code int
-	var sqlState string = ""
 
 	switch {
 	case ErrTableNotFound.Is(err):
 		code = mysql.ERNoSuchTable
+	case ErrAmbiguousColumnName.Is(err):
+		code = mysql.ERNonUniq
+	case ErrUnknownTimeZone.Is(err):
+		code = mysql.ERUnknownTimeZone
+	case ErrForeignKeyChildViolation.Is(err):
+		code = mysql.ERRowIsReferenced2
+	case ErrReadOnlyTransaction.Is(err):
+		code = mysql.EROptionPreventsStatement
+	case ErrQueryInterrupted.Is(err), ErrConnectionClosed.Is(err):
+		code = mysql.ERQueryInterrupted
+	case ErrQueryTimeout.Is(err):
+		code = 3024 // ER_QUERY_TIMEOUT
+	case ErrCollationCharsetMismatch.Is(err):
+		code = mysql.ERCollationCharsetMismatch
+	case ErrIllegalMixOfCollations.Is(err):
+		code = mysql.ERCantAggregate2Collations
+	case ErrUpdateWithoutKeyInSafeMode.Is(err):
+		code = 1175 // ER_UPDATE_WITHOUT_KEY_IN_SAFE_MODE
+	case ErrLockNowait.Is(err):
+		code = 3572 // ER_LOCK_NOWAIT
+	case ErrExpectedSingleRow.Is(err):
+		code = mysql.ERSubqueryNo1Row
+	case ErrMaxPreparedStmtCountReached.Is(err):
+		code = 1461 // ER_MAX_PREPARED_STMT_COUNT_REACHED
 	default:
 		code = mysql.ERUnknownError
 	}
 
-	return mysql.NewSQLError(code, sqlState, err.Error()), false
+	return mysql.NewSQLError(code, SQLStateForCode(code), err.Error()), false
 }
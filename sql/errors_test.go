@@ -13,13 +13,18 @@ import (
 func TestSQLErrorCast(t *testing.T) {
 
 	tests := []struct {
-		err  error
-		code int
+		err      error
+		code     int
+		sqlState string
 	}{
-		{ErrTableNotFound.New("table not found err"), mysql.ERNoSuchTable},
-		{ErrInvalidType.New("unhandled mysql error"), mysql.ERUnknownError},
-		{fmt.Errorf("generic error"), mysql.ERUnknownError},
-		{nil, mysql.ERUnknownError},
+		{ErrTableNotFound.New("table not found err"), mysql.ERNoSuchTable, "42S02"},
+		{ErrAmbiguousColumnName.New("c", "a, b"), mysql.ERNonUniq, "23000"},
+		{ErrInvalidType.New("unhandled mysql error"), mysql.ERUnknownError, "HY000"},
+		{ErrQueryTimeout.New(), 3024, "HY000"},
+		{ErrUpdateWithoutKeyInSafeMode.New(), 1175, "HY000"},
+		{ErrConnectionClosed.New(), mysql.ERQueryInterrupted, "70100"},
+		{fmt.Errorf("generic error"), mysql.ERUnknownError, "HY000"},
+		{nil, mysql.ERUnknownError, ""},
 	}
 
 	for _, test := range tests {
@@ -29,6 +34,7 @@ func TestSQLErrorCast(t *testing.T) {
 			if !ok {
 				require.Error(t, err)
 				assert.Equal(t, err.Number(), test.code)
+				assert.Equal(t, test.sqlState, err.SQLState())
 			} else {
 				assert.Equal(t, err, nilErr)
 			}
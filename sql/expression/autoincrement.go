@@ -109,8 +109,14 @@ func (i *AutoIncrement) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 	if err != nil {
 		return nil, err
 	}
+	if given == nil || cmp == 0 {
+		val, err = i.step(ctx, val)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	nextVal, err := NewIncrement(i.autoIncVal).Eval(ctx, row)
+	nextVal, err := NewIncrement(NewLiteral(val, i.Type())).Eval(ctx, row)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +125,23 @@ func (i *AutoIncrement) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 	return val, nil
 }
 
+// step returns the smallest value no less than the one given that is allowed by the auto_increment_increment and
+// auto_increment_offset session variables, that is, whose remainder by the increment is that of the offset.
+func (i *AutoIncrement) step(ctx *sql.Context, val interface{}) (interface{}, error) {
+	increment, offset := ctx.AutoIncrementStep()
+	if increment == 1 {
+		return val, nil
+	}
+
+	n, err := sql.Uint64.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	next := n.(uint64)
+	next += (offset%increment + increment - next%increment) % increment
+	return i.Type().Convert(next)
+}
+
 func (i *AutoIncrement) String() string {
 	return fmt.Sprintf("AutoIncrement(%s)", i.Child.String())
 }
@@ -0,0 +1,129 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Collate is an `expr COLLATE collation` clause, which overrides the collation its child is compared and sorted with.
+type Collate struct {
+	UnaryExpression
+	Collation sql.Collation
+}
+
+// NewCollate creates a new Collate expression.
+func NewCollate(expr sql.Expression, collation sql.Collation) *Collate {
+	return &Collate{UnaryExpression{expr}, collation}
+}
+
+// ResolvedCollation returns the collation of the expression, or ErrCollationCharsetMismatch if the collation isn't
+// valid for the character set of the child. A child that isn't a string, such as a number literal, has the default
+// character set.
+func (c *Collate) ResolvedCollation() (sql.Collation, error) {
+	collation := sql.Collation_Default
+	if st, ok := c.Child.Type().(sql.StringType); ok {
+		collation = st.Collation()
+	}
+	return sql.ResolveCollation(collation, c.Collation)
+}
+
+// Type implements the Expression interface. The COLLATE clause only changes the type of a string: the type of any
+// other child is kept.
+func (c *Collate) Type() sql.Type {
+	st, ok := c.Child.Type().(sql.StringType)
+	if !ok {
+		return c.Child.Type()
+	}
+	if t, err := sql.CreateString(st.Type(), st.MaxCharacterLength(), c.Collation); err == nil {
+		return t
+	}
+	return sql.CreateLongText(c.Collation)
+}
+
+// Eval implements the Expression interface.
+func (c *Collate) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	return c.Child.Eval(ctx, row)
+}
+
+func (c *Collate) String() string {
+	return fmt.Sprintf("%s COLLATE %s", c.Child, c.Collation)
+}
+
+func (c *Collate) DebugString() string {
+	return fmt.Sprintf("%s COLLATE %s", sql.DebugString(c.Child), c.Collation)
+}
+
+// WithChildren implements the Expression interface.
+func (c *Collate) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(c, len(children), 1)
+	}
+	return NewCollate(children[0], c.Collation), nil
+}
+
+// stringCollate returns the expression given if it's a COLLATE clause on a string, which is the only kind of value a
+// collation applies to.
+func stringCollate(e sql.Expression) (*Collate, bool) {
+	c, ok := e.(*Collate)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := c.Child.Type().(sql.StringType); !ok {
+		return nil, false
+	}
+	return c, true
+}
+
+// ComparisonCollation returns the collation given by a COLLATE clause on a string on either side of a comparison, and
+// whether there is one. Returns ErrIllegalMixOfCollations if both sides have one with different collations.
+func ComparisonCollation(left, right sql.Expression) (sql.Collation, bool, error) {
+	lc, lok := stringCollate(left)
+	rc, rok := stringCollate(right)
+	switch {
+	case lok && rok:
+		if lc.Collation != rc.Collation {
+			return "", true, sql.ErrIllegalMixOfCollations.New(lc.Collation, rc.Collation)
+		}
+		return lc.Collation, true, nil
+	case lok:
+		return lc.Collation, true, nil
+	case rok:
+		return rc.Collation, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// compareCollated compares two non-NULL values as strings under the collation of a COLLATE clause on a string on
+// either side of a comparison or sort. Returns false if neither side has one.
+func compareCollated(left, right sql.Expression, l, r interface{}) (int, bool, error) {
+	collation, ok, err := ComparisonCollation(left, right)
+	if !ok || err != nil {
+		return 0, ok, err
+	}
+
+	ls, err := sql.LongText.Convert(l)
+	if err != nil {
+		return 0, true, err
+	}
+	rs, err := sql.LongText.Convert(r)
+	if err != nil {
+		return 0, true, err
+	}
+	return sql.CompareCollated(collation, ls.(string), rs.(string)), true, nil
+}
@@ -58,6 +58,10 @@ func (c *comparison) Compare(ctx *sql.Context, row sql.Row) (int, error) {
 		return 0, ErrNilOperand.New()
 	}
 
+	if cmp, ok, err := compareCollated(c.Left(), c.Right(), left, right); ok {
+		return cmp, err
+	}
+
 	if sql.TypesEqual(c.Left().Type(), c.Right().Type()) {
 		return c.Left().Type().Compare(left, right)
 	}
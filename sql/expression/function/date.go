@@ -304,6 +304,7 @@ type UnixTimestamp struct {
 }
 
 var _ sql.FunctionExpression = (*UnixTimestamp)(nil)
+var _ sql.StatementNonDeterministicExpression = (*UnixTimestamp)(nil)
 
 func NewUnixTimestamp(args ...sql.Expression) (sql.Expression, error) {
 	if len(args) > 1 {
@@ -338,6 +339,11 @@ func (ut *UnixTimestamp) IsNullable() bool {
 	return true
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (ut *UnixTimestamp) IsStatementNonDeterministic() bool {
+	return ut.Date == nil
+}
+
 func (ut *UnixTimestamp) Type() sql.Type {
 	return sql.Float64
 }
@@ -385,6 +391,7 @@ type CurrDate struct {
 }
 
 var _ sql.FunctionExpression = CurrDate{}
+var _ sql.StatementNonDeterministicExpression = CurrDate{}
 
 func NewCurrDate() sql.Expression {
 	return CurrDate{
@@ -399,7 +406,10 @@ func NewCurrentDate() sql.Expression {
 }
 
 func currDateLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
-	t := ctx.QueryTime()
+	t, err := sessionQueryTime(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return fmt.Sprintf("%d-%02d-%02d", t.Year(), t.Month(), t.Day()), nil
 }
 
@@ -408,6 +418,11 @@ func (c CurrDate) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	return currDateLogic(ctx, row)
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (c CurrDate) IsStatementNonDeterministic() bool {
+	return true
+}
+
 // WithChildren implements sql.Expression
 func (c CurrDate) WithChildren(expressions ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, expressions)
@@ -105,6 +105,9 @@ func (r *Rand) Children() []sql.Expression {
 // Eval implements sql.Expression.
 func (r *Rand) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	if r.Child == nil {
+		if ctx != nil {
+			return ctx.RandFloat64(), nil
+		}
 		return rand.Float64(), nil
 	}
 
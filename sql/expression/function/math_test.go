@@ -15,6 +15,7 @@
 package function
 
 import (
+	"context"
 	"math"
 	"testing"
 	"time"
@@ -85,6 +86,27 @@ func TestRandWithSeed(t *testing.T) {
 	assert.Equal(t, f64, f642)
 }
 
+func TestRandWithContextSeed(t *testing.T) {
+	r, _ := NewRand()
+
+	evalN := func(ctx *sql.Context) []float64 {
+		var res []float64
+		for i := 0; i < 5; i++ {
+			f, err := r.Eval(ctx, nil)
+			require.NoError(t, err)
+			res = append(res, f.(float64))
+		}
+		return res
+	}
+
+	ctx1 := sql.NewContext(context.Background(), sql.WithRandSeed(42))
+	ctx2 := sql.NewContext(context.Background(), sql.WithRandSeed(42))
+	assert.Equal(t, evalN(ctx1), evalN(ctx2))
+
+	ctx3 := sql.NewContext(context.Background(), sql.WithRandSeed(43))
+	assert.NotEqual(t, evalN(sql.NewContext(context.Background(), sql.WithRandSeed(42))), evalN(ctx3))
+}
+
 func TestRadians(t *testing.T) {
 	f := sql.Function1{Name: "radians", Fn: NewRadians}
 	tf := NewTestFactory(f.Fn)
@@ -0,0 +1,69 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package function
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+func TestIsDeterministic(t *testing.T) {
+	mustExpr := func(e sql.Expression, err error) sql.Expression {
+		require.NoError(t, err)
+		return e
+	}
+
+	now := mustExpr(NewNow())
+	seededRand := mustExpr(NewRand(expression.NewLiteral(int64(1), sql.Int64)))
+	unseededRand := mustExpr(NewRand())
+	col := expression.NewGetField(0, sql.LongText, "a", true)
+
+	testCases := []struct {
+		name          string
+		expr          sql.Expression
+		deterministic bool
+	}{
+		{"literal", expression.NewLiteral(int64(1), sql.Int64), true},
+		{"column", col, true},
+		{"deterministic function", NewUpper(col), true},
+		{"seeded rand", seededRand, true},
+		{"unseeded rand", unseededRand, false},
+		{"uuid", NewUUIDFunc(), false},
+		{"now", now, false},
+		{"connection_id", NewConnectionID(), false},
+		{"current_user", NewCurrentUser(), false},
+		{"unix_timestamp of column", mustExpr(NewUnixTimestamp(col)), true},
+		{"unix_timestamp of now", mustExpr(NewUnixTimestamp()), false},
+		{"deterministic function of non-deterministic argument", NewUpper(NewUUIDFunc()), false},
+		{"mixed tree", expression.NewAnd(
+			expression.NewEquals(col, expression.NewLiteral("a", sql.LongText)),
+			expression.NewGreaterThan(mustExpr(NewYearWeek(now)), expression.NewLiteral(int64(1), sql.Int64)),
+		), false},
+		{"deterministic tree", expression.NewOr(
+			expression.NewEquals(NewLower(col), expression.NewLiteral("a", sql.LongText)),
+			expression.NewIsNull(col),
+		), true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.deterministic, sql.IsDeterministic(tt.expr))
+		})
+	}
+}
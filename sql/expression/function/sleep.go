@@ -15,7 +15,6 @@
 package function
 
 import (
-	"context"
 	"fmt"
 	"time"
 
@@ -64,7 +63,7 @@ func (s *Sleep) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 
 	select {
 	case <-ctx.Done():
-		return 0, context.Canceled
+		return 0, ctx.CancellationError()
 	case <-t.C:
 		return 0, nil
 	}
@@ -25,6 +25,7 @@ func connIDFuncLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
 }
 
 var _ sql.FunctionExpression = ConnectionID{}
+var _ sql.StatementNonDeterministicExpression = ConnectionID{}
 
 func NewConnectionID() sql.Expression {
 	return ConnectionID{
@@ -37,6 +38,11 @@ func (c ConnectionID) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	return connIDFuncLogic(ctx, row)
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (c ConnectionID) IsStatementNonDeterministic() bool {
+	return true
+}
+
 // WithChildren implements sql.Expression
 func (c ConnectionID) WithChildren(expressions ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, expressions)
@@ -50,7 +56,14 @@ func userFuncLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
 	return ctx.Client().User, nil
 }
 
+// currentUserFuncLogic returns the effective user, which differs from the user of the session in the body of a SQL
+// SECURITY DEFINER procedure.
+func currentUserFuncLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return ctx.EffectiveUser(), nil
+}
+
 var _ sql.FunctionExpression = User{}
+var _ sql.StatementNonDeterministicExpression = User{}
 
 func NewUser() sql.Expression {
 	return User{
@@ -66,9 +79,17 @@ func NewCurrentUser() sql.Expression {
 
 // Eval implements sql.Expression
 func (c User) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	if c.Name == "current_user" {
+		return currentUserFuncLogic(ctx, row)
+	}
 	return userFuncLogic(ctx, row)
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (c User) IsStatementNonDeterministic() bool {
+	return true
+}
+
 // WithChildren implements sql.Expression
 func (c User) WithChildren(expressions ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, expressions)
@@ -703,6 +703,7 @@ type Now struct {
 }
 
 var _ sql.FunctionExpression = (*Now)(nil)
+var _ sql.StatementNonDeterministicExpression = (*Now)(nil)
 
 // NewNow returns a new Now node.
 func NewNow(args ...sql.Expression) (sql.Expression, error) {
@@ -783,6 +784,9 @@ func (n *Now) String() string {
 // IsNullable implements the sql.Expression interface.
 func (n *Now) IsNullable() bool { return false }
 
+// IsStatementNonDeterministic implements the sql.StatementNonDeterministicExpression interface.
+func (n *Now) IsStatementNonDeterministic() bool { return true }
+
 // Resolved implements the sql.Expression interface.
 func (n *Now) Resolved() bool { return true }
 
@@ -791,7 +795,10 @@ func (n *Now) Children() []sql.Expression { return nil }
 
 // Eval implements the sql.Expression interface.
 func (n *Now) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
-	t := ctx.QueryTime()
+	t, err := sessionQueryTime(ctx)
+	if err != nil {
+		return nil, err
+	}
 	// TODO: Now should return a string formatted depending on context.  This code handles string formatting
 	// and should be enabled at the time we fix the return type
 	/*s, err := formatDate("%Y-%m-%d %H:%i:%s", t)
@@ -807,17 +814,114 @@ func (n *Now) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
 	return t, nil
 }
 
+// sessionQueryTime returns the time at which the current query started, as a wall clock time in the time zone of the
+// session. Like every other DATETIME value, the result has no time zone of its own, and is given in UTC.
+func sessionQueryTime(ctx *sql.Context) (time.Time, error) {
+	return sessionWallClock(ctx, ctx.QueryTime())
+}
+
+// sessionWallClock returns the wall clock time in the time zone of the session at the instant given, in UTC.
+func sessionWallClock(ctx *sql.Context, instant time.Time) (time.Time, error) {
+	loc, err := ctx.TimeZone()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t := instant.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC), nil
+}
+
 // WithChildren implements the Expression interface.
 func (n *Now) WithChildren(children ...sql.Expression) (sql.Expression, error) {
 	return NewNow(children...)
 }
 
+// SysDate is a function that returns the time at which it is evaluated. Unlike NOW(), which returns the time at which
+// the query started, its result changes as the query runs.
+type SysDate struct {
+	precision *int
+}
+
+var _ sql.FunctionExpression = (*SysDate)(nil)
+var _ sql.NonDeterministicExpression = (*SysDate)(nil)
+
+// NewSysDate returns a new SysDate node.
+func NewSysDate(args ...sql.Expression) (sql.Expression, error) {
+	var precision *int
+	if len(args) > 1 {
+		return nil, sql.ErrInvalidArgumentNumber.New("SYSDATE", 1, len(args))
+	} else if len(args) == 1 {
+		argType := args[0].Type().Promote()
+		if argType != sql.Int64 && argType != sql.Uint64 {
+			return nil, sql.ErrInvalidType.New(args[0].Type().String())
+		}
+		val, err := args[0].Eval(sql.NewEmptyContext(), nil)
+		if err != nil {
+			return nil, err
+		}
+		precisionArg, err := sql.Int32.Convert(val)
+
+		if err != nil {
+			return nil, err
+		}
+
+		n := int(precisionArg.(int32))
+		if n < 0 || n > 6 {
+			return nil, sql.ErrOutOfRange.New("precision", "sysdate")
+		}
+		precision = &n
+	}
+
+	return &SysDate{precision}, nil
+}
+
+// FunctionName implements sql.FunctionExpression
+func (n *SysDate) FunctionName() string {
+	return "sysdate"
+}
+
+// Type implements the sql.Expression interface.
+func (n *SysDate) Type() sql.Type {
+	return sql.Datetime
+}
+
+func (n *SysDate) String() string {
+	if n.precision == nil {
+		return "SYSDATE()"
+	}
+
+	return fmt.Sprintf("SYSDATE(%d)", *n.precision)
+}
+
+// IsNullable implements the sql.Expression interface.
+func (n *SysDate) IsNullable() bool { return false }
+
+// IsNonDeterministic implements the sql.NonDeterministicExpression interface.
+func (n *SysDate) IsNonDeterministic() bool { return true }
+
+// Resolved implements the sql.Expression interface.
+func (n *SysDate) Resolved() bool { return true }
+
+// Children implements the sql.Expression interface.
+func (n *SysDate) Children() []sql.Expression { return nil }
+
+// Eval implements the sql.Expression interface.
+func (n *SysDate) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
+	return sessionWallClock(ctx, ctx.Now())
+}
+
+// WithChildren implements the Expression interface.
+func (n *SysDate) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NewSysDate(children...)
+}
+
 // UTCTimestamp is a function that returns the current time.
 type UTCTimestamp struct {
 	precision *int
 }
 
 var _ sql.FunctionExpression = (*UTCTimestamp)(nil)
+var _ sql.StatementNonDeterministicExpression = (*UTCTimestamp)(nil)
 
 // NewUTCTimestamp returns a new UTCTimestamp node.
 func NewUTCTimestamp(args ...sql.Expression) (sql.Expression, error) {
@@ -869,6 +973,9 @@ func (ut *UTCTimestamp) String() string {
 // IsNullable implements the sql.Expression interface.
 func (ut *UTCTimestamp) IsNullable() bool { return false }
 
+// IsStatementNonDeterministic implements the sql.StatementNonDeterministicExpression interface.
+func (ut *UTCTimestamp) IsStatementNonDeterministic() bool { return true }
+
 // Resolved implements the sql.Expression interface.
 func (ut *UTCTimestamp) Resolved() bool { return true }
 
@@ -1194,6 +1301,7 @@ type CurrTime struct {
 }
 
 var _ sql.FunctionExpression = CurrTime{}
+var _ sql.StatementNonDeterministicExpression = CurrTime{}
 
 func NewCurrTime() sql.Expression {
 	return CurrTime{
@@ -1208,7 +1316,10 @@ func NewCurrentTime() sql.Expression {
 }
 
 func currTimeLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
-	t := ctx.QueryTime()
+	t, err := sessionQueryTime(ctx)
+	if err != nil {
+		return nil, err
+	}
 	return fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second()), nil
 }
 
@@ -1217,6 +1328,11 @@ func (c CurrTime) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	return currTimeLogic(ctx, row)
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (c CurrTime) IsStatementNonDeterministic() bool {
+	return true
+}
+
 // WithChildren implements sql.Expression
 func (c CurrTime) WithChildren(expressions ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, expressions)
@@ -1227,6 +1343,7 @@ type CurrTimestamp struct {
 }
 
 var _ sql.FunctionExpression = CurrTimestamp{}
+var _ sql.StatementNonDeterministicExpression = CurrTimestamp{}
 
 func NewCurrTimestamp() sql.Expression {
 	return CurrTimestamp{
@@ -1235,7 +1352,7 @@ func NewCurrTimestamp() sql.Expression {
 }
 
 func currDatetimeLogic(ctx *sql.Context, _ sql.Row) (interface{}, error) {
-	return ctx.QueryTime(), nil
+	return sessionQueryTime(ctx)
 }
 
 // Eval implements sql.Expression
@@ -1243,6 +1360,11 @@ func (c CurrTimestamp) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 	return currDatetimeLogic(ctx, row)
 }
 
+// IsStatementNonDeterministic implements sql.StatementNonDeterministicExpression
+func (c CurrTimestamp) IsStatementNonDeterministic() bool {
+	return true
+}
+
 // WithChildren implements sql.Expression
 func (c CurrTimestamp) WithChildren(expressions ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, expressions)
@@ -15,6 +15,7 @@
 package function
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -362,6 +363,9 @@ func TestNow(t *testing.T) {
 	})
 	require.NoError(t, err)
 
+	// NOW() gives the wall clock time in the session time zone, which is SYSTEM by default
+	wallClock := time.Date(2018, time.December, 2, 16, 25, 0, 0, time.UTC)
+
 	tests := []struct {
 		args      []sql.Expression
 		result    time.Time
@@ -369,22 +373,22 @@ func TestNow(t *testing.T) {
 	}{
 		{
 			args:      nil,
-			result:    date,
+			result:    wallClock,
 			expectErr: false,
 		},
 		{
 			args:      []sql.Expression{expression.NewLiteral(0, sql.Int8)},
-			result:    date,
+			result:    wallClock,
 			expectErr: false,
 		},
 		{
 			args:      []sql.Expression{expression.NewLiteral(0, sql.Int64)},
-			result:    date,
+			result:    wallClock,
 			expectErr: false,
 		},
 		{
 			args:      []sql.Expression{expression.NewLiteral(6, sql.Uint8)},
-			result:    date,
+			result:    wallClock,
 			expectErr: false,
 		},
 		{
@@ -419,6 +423,84 @@ func TestNow(t *testing.T) {
 	}
 }
 
+func TestNowTimeZone(t *testing.T) {
+	date := time.Date(2018, time.December, 2, 16, 25, 0, 0, time.UTC)
+	testNowFunc := func() time.Time {
+		return date
+	}
+
+	var ctx *sql.Context
+	err := sql.RunWithNowFunc(testNowFunc, func() error {
+		ctx = sql.NewEmptyContext()
+		return nil
+	})
+	require.NoError(t, err)
+
+	now, err := NewNow()
+	require.NoError(t, err)
+
+	tests := []struct {
+		timeZone string
+		result   time.Time
+	}{
+		{"+00:00", time.Date(2018, time.December, 2, 16, 25, 0, 0, time.UTC)},
+		{"+05:30", time.Date(2018, time.December, 2, 21, 55, 0, 0, time.UTC)},
+		{"-08:00", time.Date(2018, time.December, 2, 8, 25, 0, 0, time.UTC)},
+		{"Asia/Tokyo", time.Date(2018, time.December, 3, 1, 25, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.timeZone, func(t *testing.T) {
+			require.NoError(t, ctx.Set(ctx, sql.TimeZoneSessionVar, sql.LongText, test.timeZone))
+			val, err := now.Eval(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.result, val)
+
+			sqlVal, err := sql.Datetime.SQL(val)
+			require.NoError(t, err)
+			assert.Equal(t, test.result.Format("2006-01-02 15:04:05"), sqlVal.ToString())
+		})
+	}
+}
+
+func TestSysDate(t *testing.T) {
+	date := time.Date(2018, time.December, 2, 16, 25, 0, 0, time.UTC)
+	calls := 0
+	testNowFunc := func() time.Time {
+		calls++
+		return date.Add(time.Duration(calls) * time.Second)
+	}
+
+	ctx := sql.NewContext(context.Background(), sql.WithNowFunc(testNowFunc))
+	require.NoError(t, ctx.Set(ctx, sql.TimeZoneSessionVar, sql.LongText, "+00:00"))
+
+	now, err := NewNow()
+	require.NoError(t, err)
+	sysDate, err := NewSysDate()
+	require.NoError(t, err)
+
+	// NOW() stays at the query time for the whole statement
+	queryTime := time.Date(2018, time.December, 2, 16, 25, 1, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		val, err := now.Eval(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, queryTime, val)
+	}
+
+	// SYSDATE() advances with every evaluation
+	first, err := sysDate.Eval(ctx, nil)
+	require.NoError(t, err)
+	second, err := sysDate.Eval(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2018, time.December, 2, 16, 25, 2, 0, time.UTC), first)
+	assert.Equal(t, time.Date(2018, time.December, 2, 16, 25, 3, 0, time.UTC), second)
+
+	assert.False(t, sql.IsDeterministic(sysDate))
+
+	_, err = NewSysDate(expression.NewLiteral(7, sql.Int8))
+	assert.Error(t, err)
+}
+
 func TestUTCTimestamp(t *testing.T) {
 	date := time.Date(2018, time.December, 2, 16, 25, 0, 0, time.Local)
 	testNowFunc := func() time.Time {
@@ -48,3 +48,29 @@ func TestUser(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "", user)
 }
+
+func TestCurrentUser(t *testing.T) {
+	currentUserFunc := sql.NewFunction0("current_user", NewCurrentUser)
+	fn := currentUserFunc.Fn
+
+	session := sql.NewSession("server", "client", "root", 0)
+	ctx := sql.NewContext(context.TODO(), sql.WithSession(session))
+
+	user, err := fn().Eval(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "root", user)
+
+	// the effective user differs from the session user, as in a SQL SECURITY DEFINER procedure
+	definerCtx := ctx.WithEffectiveUser("definer")
+	user, err = fn().Eval(definerCtx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "definer", user)
+
+	user, err = NewUser().Eval(definerCtx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "root", user)
+
+	user, err = fn().Eval(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "root", user)
+}
@@ -54,6 +54,7 @@ import (
 type UUIDFunc struct{}
 
 var _ sql.FunctionExpression = &UUIDFunc{}
+var _ sql.NonDeterministicExpression = &UUIDFunc{}
 
 func NewUUIDFunc() sql.Expression {
 	return UUIDFunc{}
@@ -88,6 +89,11 @@ func (u UUIDFunc) FunctionName() string {
 	return "uuid"
 }
 
+// IsNonDeterministic implements sql.NonDeterministicExpression
+func (u UUIDFunc) IsNonDeterministic() bool {
+	return true
+}
+
 func (u UUIDFunc) Resolved() bool {
 	return true
 }
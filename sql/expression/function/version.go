@@ -20,8 +20,6 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 )
 
-const mysqlVersion = "8.0.11"
-
 // Version is a function that returns server version.
 type Version string
 
@@ -69,9 +67,10 @@ func (f Version) Children() []sql.Expression { return nil }
 
 // Eval implements the Expression interface.
 func (f Version) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	version := ctx.Dialect().Version()
 	if f == "" {
-		return mysqlVersion, nil
+		return version, nil
 	}
 
-	return fmt.Sprintf("%s-%s", mysqlVersion, string(f)), nil
+	return fmt.Sprintf("%s-%s", version, string(f)), nil
 }
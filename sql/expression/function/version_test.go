@@ -39,4 +39,9 @@ func TestNewVersion(t *testing.T) {
 	val, err = f.Eval(ctx, nil)
 	require.NoError(err)
 	require.Equal("8.0.11", val)
+
+	ctx.Session.SetDialect(sql.Dialect_MariaDB)
+	val, err = f.Eval(ctx, nil)
+	require.NoError(err)
+	require.Equal("10.5.9-MariaDB", val)
 }
@@ -124,7 +124,13 @@ func (l *Like) evalRight(ctx *sql.Context, row sql.Row) (*string, error) {
 	if err != nil {
 		return nil, err
 	}
-	s := patternToGoRegex(v.(string))
+	pattern := v.(string)
+	if ctx.HasSQLMode(sql.SQLModeNoBackslashEscapes) {
+		// A backslash isn't an escape character in this mode, so every backslash of the pattern is escaped to match
+		// itself.
+		pattern = strings.Replace(pattern, `\`, `\\`, -1)
+	}
+	s := patternToGoRegex(pattern)
 	return &s, nil
 }
 
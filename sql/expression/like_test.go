@@ -78,3 +78,37 @@ func TestLike(t *testing.T) {
 		})
 	}
 }
+
+func TestLikeNoBackslashEscapes(t *testing.T) {
+	f := NewLike(
+		NewGetField(0, sql.Text, "", false),
+		NewGetField(1, sql.Text, "", false),
+	)
+
+	testCases := []struct {
+		pattern, value  string
+		ok, okNoEscapes bool
+	}{
+		{`a\_b`, `a_b`, true, false},
+		{`a\_b`, `a\xb`, false, true},
+		{`a\%`, `a%`, true, false},
+		{`a\%`, `a\bc`, false, true},
+		{`a\\b`, `a\b`, true, false},
+		{`a\\b`, `a\\b`, false, true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(fmt.Sprintf("%q LIKE %q", tt.value, tt.pattern), func(t *testing.T) {
+			require := require.New(t)
+			ctx := sql.NewEmptyContext()
+			value, err := f.Eval(ctx, sql.NewRow(tt.value, tt.pattern))
+			require.NoError(err)
+			require.Equal(tt.ok, value)
+
+			require.NoError(ctx.Set(ctx, "sql_mode", sql.LongText, "STRICT_TRANS_TABLES,no_backslash_escapes"))
+			value, err = f.Eval(ctx, sql.NewRow(tt.value, tt.pattern))
+			require.NoError(err)
+			require.Equal(tt.okNoEscapes, value)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// Memoized is an expression whose result is kept for each row in the RowExpressionCache of the context, if it has
+// one, so that it's evaluated only once per row however many times it's referenced. The references sharing the result
+// are those with the same MemoizationID, which WithChildren keeps. Only projections reset the cache for each of their
+// rows, so Memoized expressions belong in the projections of a Project.
+type Memoized struct {
+	UnaryExpression
+	id sql.MemoizationID
+}
+
+var _ sql.Expression = (*Memoized)(nil)
+var _ sql.NonDeterministicExpression = (*Memoized)(nil)
+
+// NewMemoized returns the expression given memoized. Only deterministic expressions, as reported by
+// sql.IsDeterministic, are memoized, so any other is returned as is.
+func NewMemoized(e sql.Expression) sql.Expression {
+	return newMemoized(e, sql.NewMemoizationID())
+}
+
+func newMemoized(e sql.Expression, id sql.MemoizationID) sql.Expression {
+	if !sql.IsDeterministic(e) {
+		return e
+	}
+	return &Memoized{UnaryExpression{Child: e}, id}
+}
+
+// Type implements the sql.Expression interface.
+func (m *Memoized) Type() sql.Type {
+	return m.Child.Type()
+}
+
+// IsNonDeterministic implements the sql.NonDeterministicExpression interface. A Memoized expression is as
+// deterministic as its child.
+func (m *Memoized) IsNonDeterministic() bool {
+	return !sql.IsDeterministic(m.Child)
+}
+
+// Eval implements the sql.Expression interface.
+func (m *Memoized) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	cache := ctx.RowExpressionCache()
+	if cache == nil {
+		return m.Child.Eval(ctx, row)
+	}
+	if val, ok := cache.Get(m.id); ok {
+		return val, nil
+	}
+
+	val, err := m.Child.Eval(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	cache.Put(m.id, val)
+	return val, nil
+}
+
+func (m *Memoized) String() string {
+	return m.Child.String()
+}
+
+func (m *Memoized) DebugString() string {
+	return fmt.Sprintf("Memoized(%s)", sql.DebugString(m.Child))
+}
+
+// WithChildren implements the sql.Expression interface. The expression returned shares the results of this one.
+func (m *Memoized) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(m, len(children), 1)
+	}
+	return newMemoized(children[0], m.id), nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// countingExpression returns its first column, counting how many times it's evaluated.
+type countingExpression struct {
+	evals            int
+	nonDeterministic bool
+}
+
+var _ sql.NonDeterministicExpression = (*countingExpression)(nil)
+
+func (e *countingExpression) Resolved() bool                                         { return true }
+func (e *countingExpression) String() string                                         { return "counting" }
+func (e *countingExpression) Type() sql.Type                                         { return sql.Int64 }
+func (e *countingExpression) IsNullable() bool                                       { return false }
+func (e *countingExpression) Children() []sql.Expression                             { return nil }
+func (e *countingExpression) IsNonDeterministic() bool                               { return e.nonDeterministic }
+func (e *countingExpression) WithChildren(...sql.Expression) (sql.Expression, error) { return e, nil }
+
+func (e *countingExpression) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	e.evals++
+	return row[0], nil
+}
+
+func TestMemoized(t *testing.T) {
+	require := require.New(t)
+
+	counting := &countingExpression{}
+	memoized := NewMemoized(counting)
+	sum := NewArithmetic(memoized, memoized, "+")
+
+	// without a cache, every reference evaluates the expression
+	ctx := sql.NewEmptyContext()
+	val, err := sum.Eval(ctx, sql.NewRow(int64(1)))
+	require.NoError(err)
+	require.Equal(int64(2), val)
+	require.Equal(2, counting.evals)
+
+	// with one, every row evaluates it once, and rows don't see each other's results once the cache is reset
+	counting.evals = 0
+	cache := sql.NewRowExpressionCache()
+	ctx = sql.NewContext(context.Background(), sql.WithRowExpressionCache(cache))
+	for i := int64(1); i <= 3; i++ {
+		cache.Reset()
+		val, err := sum.Eval(ctx, sql.NewRow(i))
+		require.NoError(err)
+		require.Equal(2*i, val)
+	}
+	require.Equal(3, counting.evals)
+
+	// rebuilding the expression keeps its identity, so the copies share their results
+	rebuilt, err := memoized.WithChildren(counting)
+	require.NoError(err)
+	cache.Reset()
+	_, err = memoized.Eval(ctx, sql.NewRow(int64(4)))
+	require.NoError(err)
+	val, err = rebuilt.Eval(ctx, sql.NewRow(int64(4)))
+	require.NoError(err)
+	require.Equal(int64(4), val)
+	require.Equal(4, counting.evals)
+
+	// but distinct Memoized expressions of the same child don't
+	other := NewMemoized(counting)
+	_, err = other.Eval(ctx, sql.NewRow(int64(4)))
+	require.NoError(err)
+	require.Equal(5, counting.evals)
+
+	// non-deterministic expressions aren't memoized, even when they become the child of a Memoized
+	nonDeterministic := &countingExpression{nonDeterministic: true}
+	require.Equal(nonDeterministic, NewMemoized(nonDeterministic))
+	rebuilt, err = memoized.WithChildren(nonDeterministic)
+	require.NoError(err)
+	require.Equal(nonDeterministic, rebuilt)
+	require.True(sql.IsDeterministic(memoized))
+}
+
+func BenchmarkMemoized(b *testing.B) {
+	// an expression referenced three times, as in SELECT x, x * 2, x + 1 FROM t
+	var expr sql.Expression = NewGetField(0, sql.Int64, "i", false)
+	for i := 0; i < 5; i++ {
+		expr = NewArithmetic(expr, NewLiteral(int64(i), sql.Int64), "+")
+	}
+
+	bench := func(b *testing.B, expr sql.Expression, ctx *sql.Context) {
+		refs := []sql.Expression{expr, expr, expr}
+		cache := ctx.RowExpressionCache()
+		for i := 0; i < b.N; i++ {
+			if cache != nil {
+				cache.Reset()
+			}
+			row := sql.NewRow(int64(i))
+			for _, ref := range refs {
+				if _, err := ref.Eval(ctx, row); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+
+	b.Run("plain", func(b *testing.B) {
+		bench(b, expr, sql.NewEmptyContext())
+	})
+	b.Run("memoized", func(b *testing.B) {
+		ctx := sql.NewContext(context.Background(), sql.WithRowExpressionCache(sql.NewRowExpressionCache()))
+		bench(b, NewMemoized(expr), ctx)
+	})
+}
@@ -54,19 +54,22 @@ func (s *Sorter) Less(i, j int) bool {
 			return false
 		}
 
-		if sf.Order == sql.Descending {
-			av, bv = bv, av
-		}
-
 		if av == nil && bv == nil {
 			continue
 		} else if av == nil {
-			return sf.NullOrdering == sql.NullsFirst
+			return sf.NullsFirst()
 		} else if bv == nil {
-			return sf.NullOrdering != sql.NullsFirst
+			return !sf.NullsFirst()
 		}
 
-		cmp, err := typ.Compare(av, bv)
+		if sf.Order == sql.Descending {
+			av, bv = bv, av
+		}
+
+		cmp, collated, err := compareCollated(sf.Column, sf.Column, av, bv)
+		if !collated {
+			cmp, err = typ.Compare(av, bv)
+		}
 		if err != nil {
 			s.LastError = err
 			return false
@@ -25,11 +25,13 @@ import (
 type SystemVar struct {
 	Name string
 	typ  sql.Type
+	// Scope is the scope the variable is qualified with, SystemVariableScope_Session without a qualifier.
+	Scope sql.SystemVariableScope
 }
 
 // NewSystemVar creates a new SystemVar expression.
 func NewSystemVar(name string, typ sql.Type) *SystemVar {
-	return &SystemVar{name, typ}
+	return &SystemVar{Name: name, typ: typ}
 }
 
 // Children implements the sql.Expression interface.
@@ -37,8 +39,12 @@ func (v *SystemVar) Children() []sql.Expression { return nil }
 
 // Eval implements the sql.Expression interface.
 func (v *SystemVar) Eval(ctx *sql.Context, _ sql.Row) (interface{}, error) {
-	_, val := ctx.Get(v.Name)
-	return val, nil
+	_, val, _, err := ctx.ResolveSystemVariable(v.Name)
+	if sql.ErrUnknownSystemVariable.Is(err) {
+		// Unknown variables have always evaluated to NULL here, and callers depend on it
+		return nil, nil
+	}
+	return val, err
 }
 
 // Type implements the sql.Expression interface.
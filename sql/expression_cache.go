@@ -0,0 +1,69 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RowExpressionCache memoizes the results of deterministic expressions for a single row, so that an expression
+// referenced several times in a statement is evaluated once per row. Results are keyed by the MemoizationID of the
+// expression, which is kept when the expression is rebuilt with new children. The cache holds the results of the
+// current row only: the iterator producing the rows a node evaluates expressions on calls Reset before each of them, so
+// results never leak between rows. It's safe for concurrent use.
+type RowExpressionCache struct {
+	mu     sync.Mutex
+	values map[MemoizationID]interface{}
+}
+
+// MemoizationID identifies a memoized expression in a RowExpressionCache.
+type MemoizationID uint64
+
+var memoizationIDs uint64
+
+// NewMemoizationID returns a MemoizationID different from all the others returned.
+func NewMemoizationID() MemoizationID {
+	return MemoizationID(atomic.AddUint64(&memoizationIDs, 1))
+}
+
+// NewRowExpressionCache returns an empty RowExpressionCache.
+func NewRowExpressionCache() *RowExpressionCache {
+	return &RowExpressionCache{values: make(map[MemoizationID]interface{})}
+}
+
+// Get returns the result cached for the expression given in the current row, and whether there was one.
+func (c *RowExpressionCache) Get(id MemoizationID) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	val, ok := c.values[id]
+	return val, ok
+}
+
+// Put caches the result of the expression given for the current row.
+func (c *RowExpressionCache) Put(id MemoizationID, val interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[id] = val
+}
+
+// Reset discards the results of the current row, before moving on to the next one.
+func (c *RowExpressionCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.values) > 0 {
+		c.values = make(map[MemoizationID]interface{})
+	}
+}
@@ -15,6 +15,9 @@
 package sql
 
 import (
+	"strings"
+	"sync"
+
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/go-mysql-server/internal/similartext"
@@ -258,3 +261,77 @@ func (r FunctionRegistry) Function(name string) (Function, error) {
 	similar := similartext.FindFromMap(r, name)
 	return nil, ErrFunctionNotFound.New(name + similar)
 }
+
+// UserFunctionRegistry is a thread-safe registry of user-defined functions, which can be registered and unregistered
+// while the engine is running, such as by loadable plugins. Its functions are consulted before the builtin functions
+// of its FunctionRegistry. Function names are case-insensitive.
+//
+// Functions are instantiated during analysis, so unregistering a function doesn't affect the queries already using
+// it.
+type UserFunctionRegistry struct {
+	mu sync.RWMutex
+	// see SetOverride
+	override bool
+	builtins FunctionRegistry
+	funcs    map[string]Function
+}
+
+// NewUserFunctionRegistry returns a new empty UserFunctionRegistry for the builtin functions given.
+func NewUserFunctionRegistry(builtins FunctionRegistry) *UserFunctionRegistry {
+	return &UserFunctionRegistry{
+		builtins: builtins,
+		funcs:    make(map[string]Function),
+	}
+}
+
+// SetOverride sets whether functions may be registered with the name of a builtin function, which they then replace.
+// It's off by default, and turning it off doesn't unregister the functions already replacing builtin ones.
+func (r *UserFunctionRegistry) SetOverride(override bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.override = override
+}
+
+// Override returns whether functions may be registered with the name of a builtin function. See SetOverride.
+func (r *UserFunctionRegistry) Override() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.override
+}
+
+// Register registers the function given with the name given. Returns ErrFunctionAlreadyRegistered if a user-defined
+// function with that name is already registered, or if a builtin function has that name and overriding isn't allowed
+// by SetOverride.
+func (r *UserFunctionRegistry) Register(name string, fn Function) error {
+	name = strings.ToLower(name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.funcs[name]; ok {
+		return ErrFunctionAlreadyRegistered.New(name)
+	}
+	if _, ok := r.builtins[name]; ok && !r.override {
+		return ErrFunctionAlreadyRegistered.New(name)
+	}
+	r.funcs[name] = fn
+	return nil
+}
+
+// Unregister removes the user-defined function with the name given, if any. A builtin function it replaced is
+// available again.
+func (r *UserFunctionRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.funcs, strings.ToLower(name))
+}
+
+// Function returns the user-defined function with the name given, or the builtin one if there is none.
+func (r *UserFunctionRegistry) Function(name string) (Function, error) {
+	r.mu.RLock()
+	fn, ok := r.funcs[strings.ToLower(name)]
+	r.mu.RUnlock()
+	if ok {
+		return fn, nil
+	}
+	return r.builtins.Function(name)
+}
@@ -58,3 +58,48 @@ func TestFunctionRegistryMissingFunction(t *testing.T) {
 	require.Error(err)
 	require.Nil(f)
 }
+
+func TestUserFunctionRegistry(t *testing.T) {
+	require := require.New(t)
+
+	c := sql.NewCatalog()
+	builtin := expression.NewLiteral("builtin", sql.LongText)
+	c.MustRegister(sql.Function0{
+		Name: "func",
+		Fn:   func() sql.Expression { return builtin },
+	})
+
+	user := expression.NewLiteral("user", sql.LongText)
+	userFn := sql.Function0{
+		Name: "func",
+		Fn:   func() sql.Expression { return user },
+	}
+	instance := func(name string) sql.Expression {
+		f, err := c.Function(name)
+		require.NoError(err)
+		e, err := f.NewInstance(nil)
+		require.NoError(err)
+		return e
+	}
+
+	// register
+	require.NoError(c.UserFunctions.Register("udf", userFn))
+	require.Equal(user, instance("UDF"))
+	require.True(sql.ErrFunctionAlreadyRegistered.Is(c.UserFunctions.Register("Udf", userFn)))
+
+	// override
+	require.True(sql.ErrFunctionAlreadyRegistered.Is(c.UserFunctions.Register("func", userFn)))
+	require.Equal(builtin, instance("func"))
+	require.False(c.UserFunctions.Override())
+	c.UserFunctions.SetOverride(true)
+	require.True(c.UserFunctions.Override())
+	require.NoError(c.UserFunctions.Register("func", userFn))
+	require.Equal(user, instance("func"))
+
+	// unregister
+	c.UserFunctions.Unregister("func")
+	require.Equal(builtin, instance("func"))
+	c.UserFunctions.Unregister("udf")
+	_, err := c.Function("udf")
+	require.True(sql.ErrFunctionNotFound.Is(err))
+}
@@ -573,14 +573,14 @@ func charsetRowIter(ctx *Context, c *Catalog) (RowIter, error) {
 
 func engineRowIter(ctx *Context, c *Catalog) (RowIter, error) {
 	var rows []Row
-	for _, c := range SupportedEngines {
+	for _, e := range c.StorageEngines {
 		rows = append(rows, Row{
-			c.String(),
-			c.Support(),
-			c.Comment(),
-			c.Transactions(),
-			c.XA(),
-			c.Savepoints(),
+			e.String(),
+			e.Support(),
+			e.Comment(),
+			e.Transactions(),
+			e.XA(),
+			e.Savepoints(),
 		})
 	}
 	return RowsToRowIter(rows...), nil
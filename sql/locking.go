@@ -0,0 +1,73 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// LockType is the kind of lock a locking read takes on the rows it reads.
+type LockType uint8
+
+const (
+	// LockType_Exclusive is the lock taken by SELECT ... FOR UPDATE, which prevents other transactions from locking
+	// the rows at all.
+	LockType_Exclusive LockType = iota
+	// LockType_Shared is the lock taken by SELECT ... LOCK IN SHARE MODE or FOR SHARE, which can be held by several
+	// transactions at once, but prevents them from taking an exclusive lock.
+	LockType_Shared
+)
+
+// String implements fmt.Stringer.
+func (t LockType) String() string {
+	switch t {
+	case LockType_Shared:
+		return "FOR SHARE"
+	default:
+		return "FOR UPDATE"
+	}
+}
+
+// LockMode is what a locking read, such as SELECT ... FOR UPDATE, does with the rows locked by other transactions.
+type LockMode uint8
+
+const (
+	// LockMode_Wait waits for the other transactions to release their locks. It's the default.
+	LockMode_Wait LockMode = iota
+	// LockMode_NoWait fails the statement with ErrLockNowait, as given by NOWAIT.
+	LockMode_NoWait
+	// LockMode_SkipLocked leaves the locked rows out of the result, as given by SKIP LOCKED.
+	LockMode_SkipLocked
+)
+
+// String implements fmt.Stringer.
+func (m LockMode) String() string {
+	switch m {
+	case LockMode_NoWait:
+		return "NOWAIT"
+	case LockMode_SkipLocked:
+		return "SKIP LOCKED"
+	default:
+		return "WAIT"
+	}
+}
+
+// LockingTable is a table that locks the rows read by a locking read, such as SELECT ... FOR UPDATE, for the
+// transaction of the session. Rows of tables that don't implement it aren't locked.
+type LockingTable interface {
+	Table
+	// LockRow takes a lock of the type given on the row given, which was read from the table. A transaction that
+	// already holds a shared lock on the row and asks for an exclusive one upgrades its lock. The lock mode says what
+	// to do when another transaction holds a conflicting lock: with LockMode_Wait it waits for the lock, with
+	// LockMode_NoWait it returns ErrLockNowait, and with LockMode_SkipLocked it returns false so that the row is left
+	// out of the result.
+	LockRow(ctx *Context, row Row, typ LockType, mode LockMode) (bool, error)
+}
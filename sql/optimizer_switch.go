@@ -0,0 +1,151 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"strings"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// OptimizerSwitchSessionVar is the session variable holding the optimizer flags, as a comma-separated list of
+// `flag=on` or `flag=off` entries.
+const OptimizerSwitchSessionVar = "optimizer_switch"
+
+// ErrUnknownOptimizerFlag is returned when setting an optimizer flag that doesn't exist.
+var ErrUnknownOptimizerFlag = errors.NewKind("unknown optimizer_switch flag: %s")
+
+// ErrInvalidOptimizerSwitch is returned when setting optimizer_switch to a value with an entry that isn't `default`,
+// `flag=on`, `flag=off` or `flag=default`.
+var ErrInvalidOptimizerSwitch = errors.NewKind("invalid optimizer_switch entry: %s")
+
+// optimizerFlag is a flag of optimizer_switch and its default value.
+type optimizerFlag struct {
+	name string
+	on   bool
+}
+
+// optimizerFlags are the flags of optimizer_switch, in the order MySQL lists them. Most of them exist for
+// compatibility only. See https://dev.mysql.com/doc/refman/8.0/en/switchable-optimizations.html
+var optimizerFlags = []optimizerFlag{
+	{"index_merge", true},
+	{"index_merge_union", true},
+	{"index_merge_sort_union", true},
+	{"index_merge_intersection", true},
+	{"engine_condition_pushdown", true},
+	{"index_condition_pushdown", true},
+	{"mrr", true},
+	{"mrr_cost_based", true},
+	{"block_nested_loop", true},
+	{"batched_key_access", false},
+	{"materialization", true},
+	{"semijoin", true},
+	{"loosescan", true},
+	{"firstmatch", true},
+	{"duplicateweedout", true},
+	{"subquery_materialization_cost_based", true},
+	{"use_index_extensions", true},
+	{"condition_fanout_filter", true},
+	{"derived_merge", true},
+	{"use_invisible_indexes", false},
+	{"skip_scan", true},
+	{"hash_join", true},
+	{"subquery_to_derived", false},
+	{"prefer_ordering_index", true},
+	{"hypergraph_optimizer", false},
+	{"derived_condition_pushdown", true},
+}
+
+// DefaultOptimizerSwitch returns the default value of optimizer_switch, with every flag set to its default.
+func DefaultOptimizerSwitch() string {
+	return formatOptimizerSwitch(defaultOptimizerFlags())
+}
+
+func defaultOptimizerFlags() map[string]bool {
+	flags := make(map[string]bool, len(optimizerFlags))
+	for _, f := range optimizerFlags {
+		flags[f.name] = f.on
+	}
+	return flags
+}
+
+// parseOptimizerSwitch returns the value of every optimizer flag for the optimizer_switch value given. Flags not
+// listed keep their default. Unknown flags and malformed entries are ignored, see applyOptimizerSwitchEntry.
+func parseOptimizerSwitch(value string) map[string]bool {
+	flags := defaultOptimizerFlags()
+	for _, entry := range strings.Split(value, ",") {
+		_ = applyOptimizerSwitchEntry(flags, entry)
+	}
+	return flags
+}
+
+// MergeOptimizerSwitch returns the value of optimizer_switch after setting it to the value given, when its current
+// value is the one given. As in MySQL, flags not listed keep their current value, `flag=default` resets a flag to its
+// default, and a `default` entry resets every flag. Fails with ErrUnknownOptimizerFlag or ErrInvalidOptimizerSwitch
+// for the first entry that isn't valid.
+func MergeOptimizerSwitch(current, value string) (string, error) {
+	flags := parseOptimizerSwitch(current)
+	for _, entry := range strings.Split(value, ",") {
+		if err := applyOptimizerSwitchEntry(flags, entry); err != nil {
+			return "", err
+		}
+	}
+	return formatOptimizerSwitch(flags), nil
+}
+
+// applyOptimizerSwitchEntry sets the flags given as told by an entry of an optimizer_switch value.
+func applyOptimizerSwitchEntry(flags map[string]bool, entry string) error {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	if entry == "default" {
+		for name, on := range defaultOptimizerFlags() {
+			flags[name] = on
+		}
+		return nil
+	}
+
+	parts := strings.SplitN(entry, "=", 2)
+	if len(parts) != 2 {
+		return ErrInvalidOptimizerSwitch.New(entry)
+	}
+	name, val := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if _, ok := flags[name]; !ok {
+		return ErrUnknownOptimizerFlag.New(name)
+	}
+	switch val {
+	case "on":
+		flags[name] = true
+	case "off":
+		flags[name] = false
+	case "default":
+		flags[name] = defaultOptimizerFlags()[name]
+	default:
+		return ErrInvalidOptimizerSwitch.New(entry)
+	}
+	return nil
+}
+
+// formatOptimizerSwitch returns the optimizer_switch value for the flags given, listing every flag in the order of
+// optimizerFlags.
+func formatOptimizerSwitch(flags map[string]bool) string {
+	entries := make([]string, len(optimizerFlags))
+	for i, f := range optimizerFlags {
+		val := "off"
+		if flags[f.name] {
+			val = "on"
+		}
+		entries[i] = f.name + "=" + val
+	}
+	return strings.Join(entries, ",")
+}
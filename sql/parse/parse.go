@@ -67,6 +67,11 @@ var (
 	unlockTablesRegex    = regexp.MustCompile(`^unlock\s+tables$`)
 	lockTablesRegex      = regexp.MustCompile(`^lock\s+tables\s`)
 	setRegex             = regexp.MustCompile(`^set\s+`)
+	temporaryTableRegex  = regexp.MustCompile(`^(create|drop)\s+(temporary)\s+table\s`)
+	flushStatusRegex     = regexp.MustCompile(`^flush\s+((local|no_write_to_binlog)\s+)?status$`)
+	flushPrivilegesRegex = regexp.MustCompile(`^flush\s+((local|no_write_to_binlog)\s+)?privileges$`)
+	lockModeRegex        = regexp.MustCompile(`(?is)^(select\s.*\s)for\s+(?:(share)(?:\s+(nowait|skip\s+locked))?|update\s+(nowait|skip\s+locked))$`)
+	maxExecutionTimeHint = regexp.MustCompile(`(?i)\bmax_execution_time\s*\(\s*(\d+)\s*\)`)
 )
 
 var describeSupportedFormats = []string{"tree"}
@@ -133,6 +138,18 @@ func Parse(ctx *sql.Context, query string) (sql.Node, error) {
 		return parseLockTables(ctx, s)
 	case setRegex.MatchString(lowerQuery):
 		s = fixSetQuery(s)
+	case temporaryTableRegex.MatchString(lowerQuery):
+		return parseTemporaryTable(ctx, s, lowerQuery)
+	case flushStatusRegex.MatchString(lowerQuery):
+		return plan.NewFlushStatus(), nil
+	case flushPrivilegesRegex.MatchString(lowerQuery):
+		return plan.NewFlushPrivileges(), nil
+	case lockModeRegex.MatchString(s):
+		return parseLockMode(ctx, s)
+	}
+
+	if ctx.HasSQLMode(sql.SQLModeNoBackslashEscapes) {
+		s = escapeBackslashes(s)
 	}
 
 	stmt, err := sqlparser.Parse(s)
@@ -147,9 +164,134 @@ func Parse(ctx *sql.Context, query string) (sql.Node, error) {
 	return convert(ctx, stmt, s)
 }
 
+// SplitStatements splits a string of semicolon-separated statements, as sent by a client with
+// CLIENT_MULTI_STATEMENTS, into its statements. Semicolons in quoted strings, quoted identifiers and comments don't end
+// a statement, but those in the body of a compound statement such as CREATE PROCEDURE ... BEGIN ... END do, so such
+// statements must be sent on their own. Empty statements are dropped.
+func SplitStatements(query string) ([]string, error) {
+	pieces, err := sqlparser.SplitStatementToPieces(query)
+	if err != nil {
+		return nil, sql.ErrSyntaxError.New(err.Error())
+	}
+
+	statements := make([]string, 0, len(pieces))
+	for _, piece := range pieces {
+		if piece = strings.TrimSpace(piece); piece != "" {
+			statements = append(statements, piece)
+		}
+	}
+	return statements, nil
+}
+
+// MaxExecutionTime returns the timeout given by the MAX_EXECUTION_TIME(ms) optimizer hint of the query given, and
+// whether it has one. As in MySQL, only the hint of the top-level SELECT counts, which is the first one in a UNION.
+// Queries without an optimizer hint aren't parsed.
+func MaxExecutionTime(ctx *sql.Context, query string) (time.Duration, bool) {
+	if !strings.Contains(query, "/*+") {
+		return 0, false
+	}
+
+	s := strings.TrimSpace(query)
+	if strings.HasSuffix(s, ";") {
+		s = s[:len(s)-1]
+	}
+	// The parser doesn't know the locking clauses parseLockMode handles, which don't hold hints
+	if m := lockModeRegex.FindStringSubmatch(s); m != nil {
+		s = m[1]
+	}
+	if ctx.HasSQLMode(sql.SQLModeNoBackslashEscapes) {
+		s = escapeBackslashes(s)
+	}
+
+	stmt, err := sqlparser.Parse(s)
+	if err != nil {
+		return 0, false
+	}
+	return selectMaxExecutionTime(stmt)
+}
+
+// selectMaxExecutionTime returns the timeout given by the MAX_EXECUTION_TIME(ms) optimizer hint of the SELECT
+// statement given, and whether it has one.
+func selectMaxExecutionTime(stmt sqlparser.Statement) (time.Duration, bool) {
+	switch stmt := stmt.(type) {
+	case *sqlparser.Select:
+		for _, comment := range stmt.Comments {
+			if !strings.HasPrefix(string(comment), "/*+") {
+				continue
+			}
+			if m := maxExecutionTimeHint.FindSubmatch(comment); m != nil {
+				if ms, err := strconv.ParseInt(string(m[1]), 10, 64); err == nil {
+					return time.Duration(ms) * time.Millisecond, true
+				}
+			}
+		}
+	case *sqlparser.Union:
+		return selectMaxExecutionTime(stmt.Left)
+	case *sqlparser.ParenSelect:
+		return selectMaxExecutionTime(stmt.Select)
+	}
+	return 0, false
+}
+
+// parseTemporaryTable parses a CREATE TEMPORARY TABLE or DROP TEMPORARY TABLE statement. The parser doesn't know the
+// TEMPORARY keyword, so the statement is parsed without it and the node is marked as temporary.
+func parseTemporaryTable(ctx *sql.Context, query, lowerQuery string) (sql.Node, error) {
+	idx := temporaryTableRegex.FindStringSubmatchIndex(lowerQuery)
+	node, err := Parse(ctx, query[:idx[4]]+query[idx[5]:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch node := node.(type) {
+	case *plan.CreateTable:
+		return node.AsTemporary(), nil
+	case *plan.DropTable:
+		return node.AsTemporary(), nil
+	default:
+		return nil, ErrUnsupportedSyntax.New(query)
+	}
+}
+
+// parseLockMode parses a SELECT ... FOR SHARE statement, optionally with NOWAIT or SKIP LOCKED, or a SELECT ... FOR
+// UPDATE NOWAIT or SKIP LOCKED statement. The parser knows neither FOR SHARE nor the lock modes, so the statement is
+// parsed without its locking clause and wrapped in a locking read. A plain FOR UPDATE is left to the parser.
+func parseLockMode(ctx *sql.Context, query string) (sql.Node, error) {
+	m := lockModeRegex.FindStringSubmatch(query)
+	node, err := Parse(ctx, m[1])
+	if err != nil {
+		return nil, err
+	}
+
+	typ, modeName := sql.LockType_Exclusive, m[4]
+	if m[2] != "" {
+		typ, modeName = sql.LockType_Shared, m[3]
+	}
+	mode := sql.LockMode_Wait
+	switch {
+	case strings.EqualFold(modeName, "nowait"):
+		mode = sql.LockMode_NoWait
+	case modeName != "":
+		mode = sql.LockMode_SkipLocked
+	}
+	return plan.NewLockingRead(typ, mode, node), nil
+}
+
 func convert(ctx *sql.Context, stmt sqlparser.Statement, query string) (sql.Node, error) {
 	if ss, ok := stmt.(sqlparser.SelectStatement); ok {
-		return convertSelectStatement(ctx, ss)
+		node, err := convertSelectStatement(ctx, ss)
+		if err != nil {
+			return nil, err
+		}
+		node = applySessionSelectLimit(ctx, ss, node)
+		if sel, ok := ss.(*sqlparser.Select); ok {
+			switch sel.Lock {
+			case sqlparser.ForUpdateStr:
+				node = plan.NewLockingRead(sql.LockType_Exclusive, sql.LockMode_Wait, node)
+			case sqlparser.ShareModeStr:
+				node = plan.NewLockingRead(sql.LockType_Shared, sql.LockMode_Wait, node)
+			}
+		}
+		return node, nil
 	}
 	switch n := stmt.(type) {
 	default:
@@ -252,6 +394,42 @@ func convertIfConditional(ctx *sql.Context, n sqlparser.IfStatementCondition) (*
 	return plan.NewIfConditional(condition, block), nil
 }
 
+// applySessionSelectLimit limits the rows returned by the top-level SELECT statement given, converted to the node
+// given, to the value of the sql_select_limit session variable, unless the statement has its own LIMIT clause. As in
+// MySQL, the limit doesn't apply to subqueries, views, or the SELECT statements of DML statements.
+func applySessionSelectLimit(ctx *sql.Context, ss sqlparser.SelectStatement, node sql.Node) sql.Node {
+	for {
+		paren, ok := ss.(*sqlparser.ParenSelect)
+		if !ok {
+			break
+		}
+		ss = paren.Select
+	}
+	switch s := ss.(type) {
+	case *sqlparser.Select:
+		if s.Limit != nil {
+			return node
+		}
+	case *sqlparser.Union:
+		if s.Limit != nil {
+			return node
+		}
+	}
+
+	ok, val := sql.HasDefaultValue(ctx.Session, "sql_select_limit")
+	if ok {
+		return node
+	}
+	limit := plan.NewLimit(mustCastNumToInt64(val), node)
+
+	// common table expressions are resolved from the top-level With node, so the limit goes under it
+	if with, isWith := node.(*plan.With); isWith {
+		limit.Child = with.Child
+		return plan.NewWith(limit, with.CTEs)
+	}
+	return limit
+}
+
 func convertSelectStatement(ctx *sql.Context, ss sqlparser.SelectStatement) (sql.Node, error) {
 	switch n := ss.(type) {
 	case *sqlparser.Select:
@@ -352,6 +530,12 @@ func convertShow(ctx *sql.Context, s *sqlparser.Show, query string) (sql.Node, e
 			sql.UnresolvedDatabase(s.Table.Qualifier.String()),
 			s.Table.Name.String(),
 		), nil
+	case "create procedure":
+		dbName, procedureName, err := showCreateProcedureName(query)
+		if err != nil {
+			return nil, err
+		}
+		return plan.NewShowCreateProcedure(sql.UnresolvedDatabase(dbName), procedureName), nil
 	case "grants":
 		return plan.NewShowGrants(), nil
 	case "triggers":
@@ -616,9 +800,6 @@ func convertSelect(ctx *sql.Context, s *sqlparser.Select) (sql.Node, error) {
 		if s.CalcFoundRows {
 			node.(*plan.Limit).CalcFoundRows = true
 		}
-	} else if ok, val := sql.HasDefaultValue(ctx.Session, "sql_select_limit"); !ok {
-		limit := mustCastNumToInt64(val)
-		node = plan.NewLimit(limit, node)
 	}
 
 	// Finally, if common table expressions were provided, wrap the top-level node in a With node to capture them
@@ -734,7 +915,7 @@ func convertCreateTrigger(ctx *sql.Context, query string, c *sqlparser.DDL) (sql
 		return nil, err
 	}
 
-	return plan.NewCreateTrigger(c.TriggerSpec.Name, c.TriggerSpec.Time, c.TriggerSpec.Event, triggerOrder, tableNameToUnresolvedTable(c.Table), body, query, bodyStr), nil
+	return plan.NewCreateTrigger(c.TriggerSpec.Name, c.TriggerSpec.Time, c.TriggerSpec.Event, triggerOrder, tableNameToUnresolvedTable(c.Table), body, definitionText(ctx, query), definitionText(ctx, bodyStr)), nil
 }
 
 func convertCreateProcedure(ctx *sql.Context, query string, c *sqlparser.DDL) (sql.Node, error) {
@@ -792,6 +973,7 @@ func convertCreateProcedure(ctx *sql.Context, query string, c *sqlparser.DDL) (s
 		}
 	}
 
+	c.SubStatementPositionStart = procedureBodyStart(query, c.SubStatementPositionStart)
 	bodyStr := strings.TrimSpace(query[c.SubStatementPositionStart:c.SubStatementPositionEnd])
 	body, err := convert(ctx, c.ProcedureSpec.Body, bodyStr)
 	if err != nil {
@@ -808,11 +990,33 @@ func convertCreateProcedure(ctx *sql.Context, query string, c *sqlparser.DDL) (s
 		characteristics,
 		body,
 		comment,
-		query,
-		bodyStr,
+		definitionText(ctx, query),
+		definitionText(ctx, bodyStr),
 	), nil
 }
 
+// procedureBodyStart returns the offset in the query given of the body of the CREATE PROCEDURE statement it holds. The
+// parser records the position after the first token of the body, as it has already read that token when it reduces
+// the statement, so the query is tokenized again to find the end of the token before it. Returns the position given
+// if no token ends there.
+func procedureBodyStart(query string, pos int) int {
+	tokenizer := sqlparser.NewStringTokenizer(query)
+	for {
+		// the tokenizer reads a character ahead, so its position is one past the end of the last token
+		prevEnd := tokenizer.Position - 1
+		typ, _ := tokenizer.Scan()
+		if typ == 0 || typ == sqlparser.LEX_ERROR || tokenizer.Position > pos {
+			return pos
+		}
+		if tokenizer.Position == pos {
+			if prevEnd < 0 {
+				prevEnd = 0
+			}
+			return prevEnd + len(query[prevEnd:]) - len(strings.TrimLeftFunc(query[prevEnd:], unicode.IsSpace))
+		}
+	}
+}
+
 func convertCall(ctx *sql.Context, c *sqlparser.Call) (sql.Node, error) {
 	params := make([]sql.Expression, len(c.Params))
 	for i, param := range c.Params {
@@ -1264,12 +1468,37 @@ func convertCreateTable(ctx *sql.Context, c *sqlparser.DDL) (sql.Node, error) {
 		IdxDefs: idxDefs,
 		FkDefs:  fkDefs,
 		ChDefs:  chDefs,
+		Engine:  tableEngine(c.TableSpec.Options),
 	}
 
 	return plan.NewCreateTable(
 		sql.UnresolvedDatabase(qualifier), c.Table.Name.String(), c.IfNotExists, tableSpec), nil
 }
 
+// tableEngine returns the storage engine named by the ENGINE option of the table options given, which vitess keeps as
+// text, or "" if there is none. The options are tokenized so that the text of a string option, such as a COMMENT,
+// isn't mistaken for an ENGINE option.
+func tableEngine(options string) string {
+	tokenizer := sqlparser.NewStringTokenizer(options)
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 || typ == sqlparser.LEX_ERROR {
+			return ""
+		}
+		if typ != sqlparser.ID || !strings.EqualFold(string(val), "engine") {
+			continue
+		}
+
+		if typ, val = tokenizer.Scan(); typ == '=' {
+			typ, val = tokenizer.Scan()
+		}
+		if typ == sqlparser.ID || typ == sqlparser.STRING {
+			return string(val)
+		}
+		return ""
+	}
+}
+
 type namedConstraint struct {
 	name string
 }
@@ -1342,7 +1571,7 @@ func convertCreateView(ctx *sql.Context, query string, c *sqlparser.DDL) (sql.No
 	}
 
 	selectStr := query[c.SubStatementPositionStart:c.SubStatementPositionEnd]
-	queryAlias := plan.NewSubqueryAlias(c.View.Name.String(), selectStr, queryNode)
+	queryAlias := plan.NewSubqueryAlias(c.View.Name.String(), definitionText(ctx, selectStr), queryNode)
 
 	return plan.NewCreateView(
 		sql.UnresolvedDatabase(""), c.View.Name.String(), []string{}, queryAlias, c.OrReplace), nil
@@ -1716,7 +1945,7 @@ func tableExprToTable(
 
 			return node, nil
 		case *sqlparser.Subquery:
-			node, err := convert(ctx, e.Select, sqlparser.String(e.Select))
+			node, err := convertSelectStatement(ctx, e.Select)
 			if err != nil {
 				return nil, err
 			}
@@ -2243,7 +2472,7 @@ func ExprToExpression(ctx *sql.Context, e sqlparser.Expr) (sql.Expression, error
 	case *sqlparser.UnaryExpr:
 		return unaryExprToExpression(ctx, v)
 	case *sqlparser.Subquery:
-		node, err := convert(ctx, v.Select, "")
+		node, err := convertSelectStatement(ctx, v.Select)
 		if err != nil {
 			return nil, err
 		}
@@ -2256,8 +2485,15 @@ func ExprToExpression(ctx *sql.Context, e sqlparser.Expr) (sql.Expression, error
 	case *sqlparser.IntervalExpr:
 		return intervalExprToExpression(ctx, v)
 	case *sqlparser.CollateExpr:
-		// TODO: handle collation
-		return ExprToExpression(ctx, v.Expr)
+		expr, err := ExprToExpression(ctx, v.Expr)
+		if err != nil {
+			return nil, err
+		}
+		collation, err := sql.ParseCollation(nil, &v.Charset, false)
+		if err != nil {
+			return nil, err
+		}
+		return expression.NewCollate(expr, collation), nil
 	case *sqlparser.ValuesFuncExpr:
 		col, err := ExprToExpression(ctx, v.Name)
 		if err != nil {
@@ -2651,6 +2887,38 @@ func setExprsToExpressions(ctx *sql.Context, e sqlparser.SetExprs) ([]sql.Expres
 	return res, nil
 }
 
+// showCreateProcedureName returns the optional database qualifier and the name of the procedure of a SHOW CREATE
+// PROCEDURE statement. The parser skips the rest of the statement after the PROCEDURE keyword, so the name is read
+// from the query.
+func showCreateProcedureName(query string) (string, string, error) {
+	tokenizer := sqlparser.NewStringTokenizer(query)
+	for _, keyword := range []int{sqlparser.SHOW, sqlparser.CREATE, sqlparser.PROCEDURE} {
+		if typ, _ := tokenizer.Scan(); typ != keyword {
+			return "", "", ErrUnsupportedSyntax.New(query)
+		}
+	}
+
+	typ, val := tokenizer.Scan()
+	if typ != sqlparser.ID {
+		return "", "", ErrUnsupportedSyntax.New(query)
+	}
+	dbName, name := "", string(val)
+	if typ, _ = tokenizer.Scan(); typ == '.' {
+		if typ, val = tokenizer.Scan(); typ != sqlparser.ID {
+			return "", "", ErrUnsupportedSyntax.New(query)
+		}
+		dbName, name = name, string(val)
+		typ, _ = tokenizer.Scan()
+	}
+	if typ == ';' {
+		typ, _ = tokenizer.Scan()
+	}
+	if typ != 0 {
+		return "", "", ErrUnsupportedSyntax.New(query)
+	}
+	return dbName, name, nil
+}
+
 func convertShowTableStatus(ctx *sql.Context, s *sqlparser.Show) (sql.Node, error) {
 	var filter sql.Expression
 	if s.Filter != nil {
@@ -2690,3 +2958,101 @@ func fixSetQuery(s string) string {
 	s = fixGlobalRegex.ReplaceAllString(s, `$1@@global.$4 =`)
 	return s
 }
+
+// escapeBackslashes doubles the backslashes in the string literals of a query, for the NO_BACKSLASH_ESCAPES sql_mode.
+// The parser always treats a backslash as an escape character, so once doubled, every backslash is read as a literal
+// one. A string literal ends at its first delimiter that isn't doubled, as a backslash can't escape it in that mode.
+// Quoted identifiers and comments are left untouched.
+func escapeBackslashes(query string) string {
+	if !strings.Contains(query, `\`) {
+		return query
+	}
+	return rewriteStringLiterals(query, func(buf *strings.Builder, literal string, i int) int {
+		if literal[i] == '\\' {
+			buf.WriteByte('\\')
+		}
+		return i
+	})
+}
+
+// unescapeBackslashes undoes escapeBackslashes on a query, or on a part of one that doesn't start or end in a string
+// literal, so that the text it gives to definition-bearing nodes, such as the body of a procedure, is the text the
+// client sent rather than the one given to the parser.
+func unescapeBackslashes(query string) string {
+	if !strings.Contains(query, `\\`) {
+		return query
+	}
+	return rewriteStringLiterals(query, func(buf *strings.Builder, literal string, i int) int {
+		if literal[i] == '\\' && i+1 < len(literal) && literal[i+1] == '\\' {
+			return i + 1
+		}
+		return i
+	})
+}
+
+// definitionText returns the text of a statement, or of a part of one, as the client sent it, for the nodes keeping
+// the text of their definition. Under NO_BACKSLASH_ESCAPES, convert is given the query with its backslashes escaped
+// for the parser, which positions in the statement refer to.
+func definitionText(ctx *sql.Context, text string) string {
+	if ctx.HasSQLMode(sql.SQLModeNoBackslashEscapes) {
+		return unescapeBackslashes(text)
+	}
+	return text
+}
+
+// rewriteStringLiterals returns the query given with the bytes of its string literals rewritten by the function
+// given, which is called with the index of every byte of a literal but its delimiters, may write bytes of its own before
+// it, and returns the index of the byte to write in its place, skipping the ones before. A string literal ends at its
+// first delimiter that isn't doubled. Quoted identifiers and comments are left untouched.
+func rewriteStringLiterals(query string, rewrite func(buf *strings.Builder, literal string, i int) int) string {
+	var buf strings.Builder
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		buf.WriteByte(ch)
+		switch {
+		case ch == '\'' || ch == '"':
+			for i++; i < len(query); i++ {
+				if query[i] == ch {
+					if i+1 < len(query) && query[i+1] == ch {
+						buf.WriteByte(ch)
+						i++
+					} else {
+						break
+					}
+				} else {
+					i = rewrite(&buf, query, i)
+				}
+				buf.WriteByte(query[i])
+			}
+			if i < len(query) {
+				buf.WriteByte(ch)
+			}
+		case ch == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				end = len(query) - i - 1
+			} else {
+				end++
+			}
+			buf.WriteString(query[i+1 : i+1+end])
+			i += end
+		case ch == '#' || (ch == '-' && strings.HasPrefix(query[i:], "-- ")):
+			end := strings.IndexByte(query[i+1:], '\n')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			buf.WriteString(query[i+1 : i+1+end])
+			i += end
+		case ch == '/' && strings.HasPrefix(query[i:], "/*") && !strings.HasPrefix(query[i:], "/*!"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i - 1
+			} else {
+				end += 3
+			}
+			buf.WriteString(query[i+1 : i+1+end])
+			i += end
+		}
+	}
+	return buf.String()
+}
@@ -19,6 +19,7 @@ import (
 	"math"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/dolthub/vitess/go/sqltypes"
 	"github.com/dolthub/vitess/go/vt/sqlparser"
@@ -104,6 +105,74 @@ var fixtures = map[string]sql.Node{
 			}},
 		},
 	),
+	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY) ENGINE=MyISAM`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:       "a",
+				Type:       sql.Int32,
+				Nullable:   false,
+				PrimaryKey: true,
+			}},
+			Engine: "MyISAM",
+		},
+	),
+	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY) DEFAULT CHARSET=utf8mb4 ENGINE InnoDB`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:       "a",
+				Type:       sql.Int32,
+				Nullable:   false,
+				PrimaryKey: true,
+			}},
+			Engine: "InnoDB",
+		},
+	),
+	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY) COMMENT 'engine=MyISAM' ENGINE='InnoDB'`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:       "a",
+				Type:       sql.Int32,
+				Nullable:   false,
+				PrimaryKey: true,
+			}},
+			Engine: "InnoDB",
+		},
+	),
+	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY) COMMENT='engine=MyISAM'`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:       "a",
+				Type:       sql.Int32,
+				Nullable:   false,
+				PrimaryKey: true,
+			}},
+		},
+	),
+	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY) SECONDARY_ENGINE=rapid`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:       "a",
+				Type:       sql.Int32,
+				Nullable:   false,
+				PrimaryKey: true,
+			}},
+		},
+	),
 	`CREATE TABLE t1(a INTEGER NOT NULL PRIMARY KEY COMMENT "hello", b TEXT COMMENT "goodbye")`: plan.NewCreateTable(
 		sql.UnresolvedDatabase(""),
 		"t1",
@@ -666,6 +735,21 @@ var fixtures = map[string]sql.Node{
 	`DROP TABLE IF EXISTS foo, bar, baz;`: plan.NewDropTable(
 		sql.UnresolvedDatabase(""), true, "foo", "bar", "baz",
 	),
+	`DROP TEMPORARY TABLE IF EXISTS foo;`: plan.NewDropTable(
+		sql.UnresolvedDatabase(""), true, "foo",
+	).AsTemporary(),
+	`CREATE TEMPORARY TABLE t1(a INTEGER)`: plan.NewCreateTable(
+		sql.UnresolvedDatabase(""),
+		"t1",
+		false,
+		&plan.TableSpec{
+			Schema: sql.Schema{{
+				Name:     "a",
+				Type:     sql.Int32,
+				Nullable: true,
+			}},
+		},
+	).AsTemporary(),
 	`RENAME TABLE foo TO bar`: plan.NewRenameTable(
 		sql.UnresolvedDatabase(""), []string{"foo"}, []string{"bar"},
 	),
@@ -1154,7 +1238,7 @@ var fixtures = map[string]sql.Node{
 		),
 	),
 	`SELECT foo, bar FROM foo ORDER BY baz DESC;`: plan.NewSort(
-		[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsFirst}},
+		[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsDefault}},
 		plan.NewProject(
 			[]sql.Expression{
 				expression.NewUnresolvedColumn("foo"),
@@ -1180,7 +1264,7 @@ var fixtures = map[string]sql.Node{
 	),
 	`SELECT foo, bar FROM foo ORDER BY baz DESC LIMIT 1;`: plan.NewLimit(1,
 		plan.NewSort(
-			[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsFirst}},
+			[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsDefault}},
 			plan.NewProject(
 				[]sql.Expression{
 					expression.NewUnresolvedColumn("foo"),
@@ -1192,7 +1276,7 @@ var fixtures = map[string]sql.Node{
 	),
 	`SELECT foo, bar FROM foo WHERE qux = 1 ORDER BY baz DESC LIMIT 1;`: plan.NewLimit(1,
 		plan.NewSort(
-			[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsFirst}},
+			[]sql.SortField{{Column: expression.NewUnresolvedColumn("baz"), Order: sql.Descending, NullOrdering: sql.NullsDefault}},
 			plan.NewProject(
 				[]sql.Expression{
 					expression.NewUnresolvedColumn("foo"),
@@ -1438,6 +1522,67 @@ var fixtures = map[string]sql.Node{
 		},
 		plan.NewUnresolvedTable("foo", ""),
 	),
+	`SELECT * FROM foo FOR UPDATE`: plan.NewLockingRead(sql.LockType_Exclusive, sql.LockMode_Wait, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	)),
+	`SELECT * FROM foo LIMIT 1 FOR UPDATE NOWAIT`: plan.NewLockingRead(sql.LockType_Exclusive, sql.LockMode_NoWait, plan.NewLimit(1, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	))),
+	`select * from foo
+	for update skip  locked;`: plan.NewLockingRead(sql.LockType_Exclusive, sql.LockMode_SkipLocked, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	)),
+	`SELECT * FROM foo LOCK IN SHARE MODE`: plan.NewLockingRead(sql.LockType_Shared, sql.LockMode_Wait, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	)),
+	`SELECT * FROM foo FOR SHARE`: plan.NewLockingRead(sql.LockType_Shared, sql.LockMode_Wait, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	)),
+	`SELECT * FROM foo FOR SHARE SKIP LOCKED`: plan.NewLockingRead(sql.LockType_Shared, sql.LockMode_SkipLocked, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewUnresolvedTable("foo", ""),
+	)),
+	`SELECT * FROM foo WHERE c = 'İİİİİİ' FOR SHARE`: plan.NewLockingRead(sql.LockType_Shared, sql.LockMode_Wait, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("c"),
+				expression.NewLiteral("İİİİİİ", sql.LongText),
+			),
+			plan.NewUnresolvedTable("foo", ""),
+		),
+	)),
+	`SELECT * FROM foo WHERE c = 'İİİİİİ' FOR UPDATE NOWAIT`: plan.NewLockingRead(sql.LockType_Exclusive, sql.LockMode_NoWait, plan.NewProject(
+		[]sql.Expression{
+			expression.NewStar(),
+		},
+		plan.NewFilter(
+			expression.NewEquals(
+				expression.NewUnresolvedColumn("c"),
+				expression.NewLiteral("İİİİİİ", sql.LongText),
+			),
+			plan.NewUnresolvedTable("foo", ""),
+		),
+	)),
 	`SELECT foo, bar FROM foo LIMIT 2 OFFSET 5;`: plan.NewLimit(2,
 		plan.NewOffset(5, plan.NewProject(
 			[]sql.Expression{
@@ -1758,12 +1903,12 @@ var fixtures = map[string]sql.Node{
 			{
 				Column:       expression.NewLiteral(int8(2), sql.Int8),
 				Order:        sql.Ascending,
-				NullOrdering: sql.NullsFirst,
+				NullOrdering: sql.NullsDefault,
 			},
 			{
 				Column:       expression.NewLiteral(int8(1), sql.Int8),
 				Order:        sql.Ascending,
-				NullOrdering: sql.NullsFirst,
+				NullOrdering: sql.NullsDefault,
 			},
 		},
 		plan.NewProject(
@@ -2130,6 +2275,10 @@ var fixtures = map[string]sql.Node{
 	`SHOW VARIABLES LIKE 'gtid_mode'`:          plan.NewShowVariables("gtid_mode"),
 	`SHOW SESSION VARIABLES LIKE 'autocommit'`: plan.NewShowVariables("autocommit"),
 	`UNLOCK TABLES`:                            plan.NewUnlockTables(),
+	`FLUSH STATUS`:                             plan.NewFlushStatus(),
+	`flush local status`:                       plan.NewFlushStatus(),
+	`FLUSH PRIVILEGES`:                         plan.NewFlushPrivileges(),
+	`flush no_write_to_binlog privileges`:      plan.NewFlushPrivileges(),
 	`LOCK TABLES foo READ`: plan.NewLockTables([]*plan.TableLock{
 		{Table: plan.NewUnresolvedTable("foo", "")},
 	}),
@@ -2489,7 +2638,7 @@ var fixtures = map[string]sql.Node{
 						{
 							Column:       expression.NewUnresolvedColumn("x"),
 							Order:        sql.Ascending,
-							NullOrdering: sql.NullsFirst,
+							NullOrdering: sql.NullsDefault,
 						},
 					},
 				)),
@@ -2519,7 +2668,7 @@ var fixtures = map[string]sql.Node{
 						{
 							Column:       expression.NewUnresolvedColumn("x"),
 							Order:        sql.Ascending,
-							NullOrdering: sql.NullsFirst,
+							NullOrdering: sql.NullsDefault,
 						},
 					},
 				)),
@@ -2545,7 +2694,7 @@ var fixtures = map[string]sql.Node{
 						{
 							Column:       expression.NewUnresolvedColumn("x"),
 							Order:        sql.Ascending,
-							NullOrdering: sql.NullsFirst,
+							NullOrdering: sql.NullsDefault,
 						},
 					},
 				)),
@@ -2946,6 +3095,8 @@ var fixtures = map[string]sql.Node{
 	`CREATE DATABASE IF NOT EXISTS test`: plan.NewCreateDatabase("test", true),
 	`DROP DATABASE test`:                 plan.NewDropDatabase("test", false),
 	`DROP DATABASE IF EXISTS test`:       plan.NewDropDatabase("test", true),
+	`SHOW CREATE PROCEDURE p1`:           plan.NewShowCreateProcedure(sql.UnresolvedDatabase(""), "p1"),
+	"show create procedure `mydb`.`P1`;": plan.NewShowCreateProcedure(sql.UnresolvedDatabase("mydb"), "P1"),
 }
 
 func TestParse(t *testing.T) {
@@ -3011,6 +3162,8 @@ func assertNodesEqualWithDiff(t *testing.T, expected, actual sql.Node) bool {
 }
 
 var fixturesErrors = map[string]*errors.Kind{
+	`SHOW CREATE PROCEDURE`:                                   ErrUnsupportedSyntax,
+	`SHOW CREATE PROCEDURE p1 p2`:                             ErrUnsupportedSyntax,
 	`SHOW METHEMONEY`:                                         ErrUnsupportedFeature,
 	`LOCK TABLES foo AS READ`:                                 errUnexpectedSyntax,
 	`LOCK TABLES foo LOW_PRIORITY READ`:                       errUnexpectedSyntax,
@@ -3060,6 +3213,60 @@ func TestFixSetQuery(t *testing.T) {
 	}
 }
 
+func TestSplitStatements(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out []string
+	}{
+		{"select 1", []string{"select 1"}},
+		{"select 1; select 2;", []string{"select 1", "select 2"}},
+		{" select 1 ;; ; select 2 ", []string{"select 1", "select 2"}},
+		{"select 'a;b'; select \"c;d\"", []string{"select 'a;b'", "select \"c;d\""}},
+		{"select 'it\\';s'; select 2", []string{"select 'it\\';s'", "select 2"}},
+		{"select `a;b` from t; select 2", []string{"select `a;b` from t", "select 2"}},
+		{"select /* ; */ 1; select 2", []string{"select /* ; */ 1", "select 2"}},
+		{"select 1 -- comment;\n; select 2", []string{"select 1 -- comment;", "select 2"}},
+		{"select 1 # comment;\n; select 2", []string{"select 1 # comment;", "select 2"}},
+		{"", []string{}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.in, func(t *testing.T) {
+			statements, err := SplitStatements(tt.in)
+			require.NoError(t, err)
+			require.Equal(t, tt.out, statements)
+		})
+	}
+}
+
+func TestMaxExecutionTime(t *testing.T) {
+	testCases := []struct {
+		in      string
+		timeout time.Duration
+		ok      bool
+	}{
+		{"SELECT /*+ MAX_EXECUTION_TIME(10) */ 1", 10 * time.Millisecond, true},
+		{"select /*+ bka(t) max_execution_time( 20 ) */ 1;", 20 * time.Millisecond, true},
+		{"/* comment */ SELECT /*+ MAX_EXECUTION_TIME(10) */ 1", 10 * time.Millisecond, true},
+		{"WITH c AS (SELECT 1) SELECT /*+ MAX_EXECUTION_TIME(10) */ * FROM c", 10 * time.Millisecond, true},
+		{"(SELECT /*+ MAX_EXECUTION_TIME(10) */ 1) UNION (SELECT 2)", 10 * time.Millisecond, true},
+		{"SELECT /*+ MAX_EXECUTION_TIME(10) */ * FROM t FOR SHARE SKIP LOCKED", 10 * time.Millisecond, true},
+		{"SELECT 1", 0, false},
+		{"SELECT /* MAX_EXECUTION_TIME(10) */ 1", 0, false},
+		{"SELECT 1 FROM t WHERE s = '/*+ MAX_EXECUTION_TIME(10) */'", 0, false},
+		{"SELECT (SELECT /*+ MAX_EXECUTION_TIME(10) */ 1)", 0, false},
+		{"SELECT 1 UNION SELECT /*+ MAX_EXECUTION_TIME(10) */ 2", 0, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.in, func(t *testing.T) {
+			timeout, ok := MaxExecutionTime(sql.NewEmptyContext(), tt.in)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.timeout, timeout)
+		})
+	}
+}
+
 func TestPrintTree(t *testing.T) {
 	require := require.New(t)
 	node, err := Parse(sql.NewEmptyContext(), `
@@ -3081,3 +3288,109 @@ func TestPrintTree(t *testing.T) {
                  └─ UnresolvedTable(bar)
 `, node.String())
 }
+
+func TestProcedureCreateStatement(t *testing.T) {
+	require := require.New(t)
+	query := "CREATE DEFINER=`user` PROCEDURE p1(IN a BIGINT, OUT b VARCHAR(20), INOUT c DECIMAL(10,2)) " +
+		"DETERMINISTIC READS SQL DATA SQL SECURITY INVOKER COMMENT 'it''s a test' BEGIN SET b = CONCAT(a, ''); SET c = c * 2; END"
+	node, err := Parse(sql.NewEmptyContext(), query)
+	require.NoError(err)
+	procedure := node.(*plan.CreateProcedure).Procedure
+	require.Equal(query, procedure.CreateStatement())
+
+	// Without the original statement, the reconstructed one must define the same procedure
+	reconstructed := *procedure
+	reconstructed.CreateProcedureString = ""
+	createStatement := reconstructed.CreateStatement()
+	require.Equal("CREATE DEFINER = `user` PROCEDURE `p1`(IN a BIGINT, OUT b VARCHAR(20), INOUT c DECIMAL(10,2)) "+
+		"DETERMINISTIC READS SQL DATA SQL SECURITY INVOKER COMMENT 'it''s a test' BEGIN SET b = CONCAT(a, ''); SET c = c * 2; END",
+		createStatement)
+
+	node, err = Parse(sql.NewEmptyContext(), createStatement)
+	require.NoError(err)
+	reparsed := node.(*plan.CreateProcedure).Procedure
+	require.Equal(procedure.Name, reparsed.Name)
+	require.Equal(procedure.Definer, reparsed.Definer)
+	require.Equal(procedure.Params, reparsed.Params)
+	require.Equal(procedure.Characteristics, reparsed.Characteristics)
+	require.Equal(procedure.SecurityContext, reparsed.SecurityContext)
+	require.Equal(procedure.Comment, reparsed.Comment)
+	require.Equal(procedure.BodyString, reparsed.BodyString)
+}
+
+func TestEscapeBackslashes(t *testing.T) {
+	testCases := []struct {
+		query, expected string
+	}{
+		{`SELECT 'abc'`, `SELECT 'abc'`},
+		{`SELECT 'a\b'`, `SELECT 'a\\b'`},
+		{`SELECT "a\", 'b\'`, `SELECT "a\\", 'b\\'`},
+		{`SELECT 'it''s \n'`, `SELECT 'it''s \\n'`},
+		{"SELECT `a\\b` FROM t", "SELECT `a\\b` FROM t"},
+		{"SELECT 1 -- a\\b\nFROM t WHERE x = '\\'", "SELECT 1 -- a\\b\nFROM t WHERE x = '\\\\'"},
+		{`SELECT /* 'a\b */ 'c\'`, `SELECT /* 'a\b */ 'c\\'`},
+		{`SELECT /*!'a\b' */ 1`, `SELECT /*!'a\\b' */ 1`},
+		{`SELECT 'unterminated\`, `SELECT 'unterminated\\`},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.query, func(t *testing.T) {
+			require.Equal(t, tt.expected, escapeBackslashes(tt.query))
+			require.Equal(t, tt.query, unescapeBackslashes(tt.expected))
+		})
+	}
+}
+
+func TestParseNoBackslashEscapes(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+	parseLiteral := func(query string) interface{} {
+		node, err := Parse(ctx, query)
+		require.NoError(err)
+		val, err := node.(*plan.Project).Projections[0].Eval(ctx, nil)
+		require.NoError(err)
+		return val
+	}
+
+	_, err := Parse(ctx, `SELECT 'a\nb\'`)
+	require.Error(err)
+	require.Equal("a\nb", parseLiteral(`SELECT 'a\nb'`))
+
+	require.NoError(ctx.Set(ctx, "sql_mode", sql.LongText, "NO_BACKSLASH_ESCAPES"))
+	require.Equal(`a\nb\`, parseLiteral(`SELECT 'a\nb\'`))
+	require.Equal(`it's \`, parseLiteral(`SELECT 'it''s \'`))
+
+	// definitions keep the text the client sent
+	node, err := Parse(ctx, `CREATE PROCEDURE p() SELECT 'a\b'`)
+	require.NoError(err)
+	procedure := node.(*plan.CreateProcedure).Procedure
+	require.Equal(`CREATE PROCEDURE p() SELECT 'a\b'`, procedure.CreateProcedureString)
+	require.Equal(`SELECT 'a\b'`, procedure.BodyString)
+
+	node, err = Parse(ctx, `CREATE TRIGGER trig BEFORE INSERT ON t FOR EACH ROW SET new.s = '\'`)
+	require.NoError(err)
+	trigger := node.(*plan.CreateTrigger)
+	require.Equal(`CREATE TRIGGER trig BEFORE INSERT ON t FOR EACH ROW SET new.s = '\'`, trigger.CreateTriggerString)
+	require.Equal(`SET new.s = '\'`, trigger.BodyString)
+
+	node, err = Parse(ctx, `CREATE VIEW v AS SELECT 'a\b'`)
+	require.NoError(err)
+	require.Equal(`SELECT 'a\b'`, node.(*plan.CreateView).Definition.TextDefinition)
+}
+
+func TestParseProcedureBody(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	// the body is found by its position, not by the whitespace before it
+	for query, body := range map[string]string{
+		`CREATE PROCEDURE p2()SELECT 7`:                    `SELECT 7`,
+		`CREATE PROCEDURE p3(x INT)  BEGIN SELECT x; END`:  `BEGIN SELECT x; END`,
+		`CREATE PROCEDURE p4() COMMENT 'a b' SELECT 'c d'`: `SELECT 'c d'`,
+		"CREATE PROCEDURE p5()\n\tSELECT 1":                `SELECT 1`,
+	} {
+		node, err := Parse(ctx, query)
+		require.NoError(err)
+		require.Equal(body, node.(*plan.CreateProcedure).Procedure.BodyString, query)
+	}
+}
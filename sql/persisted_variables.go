@@ -0,0 +1,73 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "strings"
+
+// PersistedVariableStore is supplied by integrators to durably record system variables set with SET PERSIST or
+// SET PERSIST_ONLY, so that they can be restored when the server restarts.
+type PersistedVariableStore interface {
+	// Persist durably records the value of the system variable with the name given, replacing any previous value.
+	Persist(ctx *Context, name string, value TypedValue) error
+	// Remove removes the persisted value of the system variable with the name given. Removing a variable that isn't
+	// persisted is not an error.
+	Remove(ctx *Context, name string) error
+	// RemoveAll removes every persisted system variable.
+	RemoveAll(ctx *Context) error
+	// Persisted returns all the persisted system variables, keyed by name.
+	Persisted(ctx *Context) (map[string]TypedValue, error)
+}
+
+// SetPersist sets the system variable given in the session, like SET, and records it in the store so it survives a
+// restart, like SET PERSIST.
+func SetPersist(ctx *Context, store PersistedVariableStore, name string, typ Type, value interface{}) error {
+	name = strings.ToLower(name)
+	if err := ctx.Set(ctx, name, typ, value); err != nil {
+		return err
+	}
+	return store.Persist(ctx, name, TypedValue{typ, value})
+}
+
+// SetPersistOnly records the system variable given in the store without changing its value in the running session,
+// like SET PERSIST_ONLY.
+func SetPersistOnly(ctx *Context, store PersistedVariableStore, name string, typ Type, value interface{}) error {
+	return store.Persist(ctx, strings.ToLower(name), TypedValue{typ, value})
+}
+
+// ResetPersist removes the system variable given from the store, or every persisted variable if the name is empty,
+// like RESET PERSIST. The values in the running session are left untouched.
+func ResetPersist(ctx *Context, store PersistedVariableStore, name string) error {
+	if name == "" {
+		return store.RemoveAll(ctx)
+	}
+	return store.Remove(ctx, strings.ToLower(name))
+}
+
+// ApplyPersistedVariables sets every variable in the store on the session of the context given, overriding the
+// built-in defaults. Integrators should call it when building new sessions.
+func ApplyPersistedVariables(ctx *Context, store PersistedVariableStore) error {
+	persisted, err := store.Persisted(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, v := range persisted {
+		if err := ctx.Set(ctx, name, v.Typ, v.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memPersistedVariableStore map[string]TypedValue
+
+var _ PersistedVariableStore = memPersistedVariableStore{}
+
+func (m memPersistedVariableStore) Persist(_ *Context, name string, value TypedValue) error {
+	m[name] = value
+	return nil
+}
+
+func (m memPersistedVariableStore) Remove(_ *Context, name string) error {
+	delete(m, name)
+	return nil
+}
+
+func (m memPersistedVariableStore) RemoveAll(_ *Context) error {
+	for name := range m {
+		delete(m, name)
+	}
+	return nil
+}
+
+func (m memPersistedVariableStore) Persisted(_ *Context) (map[string]TypedValue, error) {
+	persisted := make(map[string]TypedValue, len(m))
+	for name, v := range m {
+		persisted[name] = v
+	}
+	return persisted, nil
+}
+
+func TestPersistedVariables(t *testing.T) {
+	require := require.New(t)
+
+	store := memPersistedVariableStore{}
+	ctx := NewContext(context.Background(), WithSession(NewSession("foo", "baz", "bar", 1)))
+
+	require.NoError(SetPersist(ctx, store, "SQL_SELECT_LIMIT", Int32, int32(10)))
+	_, val := ctx.Get("sql_select_limit")
+	require.Equal(int32(10), val)
+	require.Equal(TypedValue{Int32, int32(10)}, store["sql_select_limit"])
+
+	// SET PERSIST_ONLY doesn't change the running value
+	require.NoError(SetPersistOnly(ctx, store, "sql_mode", LongText, "ANSI_QUOTES"))
+	_, val = ctx.Get("sql_mode")
+	require.Equal("", val)
+	require.Equal(TypedValue{LongText, "ANSI_QUOTES"}, store["sql_mode"])
+
+	// a new session starts with the persisted values instead of the defaults
+	newCtx := NewContext(context.Background(), WithSession(NewSession("foo", "baz", "bar", 2)))
+	require.NoError(ApplyPersistedVariables(newCtx, store))
+	_, val = newCtx.Get("sql_mode")
+	require.Equal("ANSI_QUOTES", val)
+	_, val = newCtx.Get("sql_select_limit")
+	require.Equal(int32(10), val)
+
+	require.NoError(ResetPersist(ctx, store, "Sql_Mode"))
+	require.Len(store, 1)
+	_, val = ctx.Get("sql_select_limit")
+	require.Equal(int32(10), val)
+
+	require.NoError(ResetPersist(ctx, store, ""))
+	require.Len(store, 0)
+}
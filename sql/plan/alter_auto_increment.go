@@ -49,6 +49,10 @@ func (p *AlterAutoIncrement) Execute(ctx *sql.Context) error {
 
 // RowIter implements the Node interface.
 func (p *AlterAutoIncrement) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
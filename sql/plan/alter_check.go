@@ -157,6 +157,10 @@ func (p *CreateCheck) WithChildren(children ...sql.Node) (sql.Node, error) {
 func (p *CreateCheck) Schema() sql.Schema { return nil }
 
 func (p *CreateCheck) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
@@ -166,7 +170,7 @@ func (p *CreateCheck) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error
 
 func (p CreateCheck) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("AddCheck(%s)", p.Check.Name)
+	_ = pr.WriteNode("AddCheck(%s)", sql.QuoteIdentifier(p.Check.Name))
 	_ = pr.WriteChildren(
 		fmt.Sprintf("Table(%s)", p.UnaryNode.Child.String()),
 		fmt.Sprintf("Expr(%s)", p.Check.Expr.String()),
@@ -185,6 +189,10 @@ func (p *DropCheck) Execute(ctx *sql.Context) error {
 
 // RowIter implements the Node interface.
 func (p *DropCheck) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
@@ -203,7 +211,7 @@ func (p *DropCheck) Schema() sql.Schema { return nil }
 
 func (p DropCheck) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("DropCheck(%s)", p.Check.Name)
+	_ = pr.WriteNode("DropCheck(%s)", sql.QuoteIdentifier(p.Check.Name))
 	_ = pr.WriteChildren(fmt.Sprintf("Table(%s)", p.UnaryNode.Child.String()))
 	return pr.String()
 }
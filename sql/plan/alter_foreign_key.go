@@ -130,6 +130,10 @@ func (p *DropForeignKey) Execute(ctx *sql.Context) error {
 
 // RowIter implements the Node interface.
 func (p *DropForeignKey) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
@@ -158,6 +162,10 @@ func (p *CreateForeignKey) Schema() sql.Schema { return nil }
 func (p *DropForeignKey) Schema() sql.Schema   { return nil }
 
 func (p *CreateForeignKey) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
@@ -168,19 +176,19 @@ func (p *CreateForeignKey) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter,
 
 func (p DropForeignKey) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("DropForeignKey(%s)", p.FkDef.Name)
+	_ = pr.WriteNode("DropForeignKey(%s)", sql.QuoteIdentifier(p.FkDef.Name))
 	_ = pr.WriteChildren(fmt.Sprintf("Table(%s)", p.UnaryNode.Child.String()))
 	return pr.String()
 }
 
 func (p CreateForeignKey) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("AddForeignKey(%s)", p.FkDef.Name)
+	_ = pr.WriteNode("AddForeignKey(%s)", sql.QuoteIdentifier(p.FkDef.Name))
 	_ = pr.WriteChildren(
 		fmt.Sprintf("Table(%s)", p.BinaryNode.left.String()),
-		fmt.Sprintf("Columns(%s)", strings.Join(p.FkDef.Columns, ", ")),
+		fmt.Sprintf("Columns(%s)", strings.Join(sql.QuoteIdentifiers(p.FkDef.Columns), ", ")),
 		fmt.Sprintf("ReferencedTable(%s)", p.BinaryNode.right.String()),
-		fmt.Sprintf("ReferencedColumns(%s)", strings.Join(p.FkDef.ReferencedColumns, ", ")),
+		fmt.Sprintf("ReferencedColumns(%s)", strings.Join(sql.QuoteIdentifiers(p.FkDef.ReferencedColumns), ", ")),
 		fmt.Sprintf("OnUpdate(%s)", p.FkDef.OnUpdate),
 		fmt.Sprintf("OnDelete(%s)", p.FkDef.OnDelete))
 	return pr.String()
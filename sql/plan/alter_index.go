@@ -161,6 +161,10 @@ func (p *AlterIndex) Execute(ctx *sql.Context) error {
 
 // RowIter implements the Node interface.
 func (p *AlterIndex) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	err := p.Execute(ctx)
 	if err != nil {
 		return nil, err
@@ -190,7 +194,7 @@ func (p AlterIndex) String() string {
 	pr := sql.NewTreePrinter()
 	switch p.Action {
 	case IndexAction_Create:
-		_ = pr.WriteNode("CreateIndex(%s)", p.IndexName)
+		_ = pr.WriteNode("CreateIndex(%s)", sql.QuoteIdentifier(p.IndexName))
 		children := []string{fmt.Sprintf("Table(%s)", p.Table.String())}
 		switch p.Constraint {
 		case sql.IndexConstraint_Unique:
@@ -209,23 +213,23 @@ func (p AlterIndex) String() string {
 		cols := make([]string, len(p.Columns))
 		for i, col := range p.Columns {
 			if col.Length == 0 {
-				cols[i] = col.Name
+				cols[i] = sql.QuoteIdentifier(col.Name)
 			} else {
-				cols[i] = fmt.Sprintf("%s(%v)", col.Name, col.Length)
+				cols[i] = fmt.Sprintf("%s(%v)", sql.QuoteIdentifier(col.Name), col.Length)
 			}
 		}
 		children = append(children, fmt.Sprintf("Columns(%s)", strings.Join(cols, ", ")))
 		children = append(children, fmt.Sprintf("Comment(%s)", p.Comment))
 		_ = pr.WriteChildren(children...)
 	case IndexAction_Drop:
-		_ = pr.WriteNode("DropIndex(%s)", p.IndexName)
+		_ = pr.WriteNode("DropIndex(%s)", sql.QuoteIdentifier(p.IndexName))
 		_ = pr.WriteChildren(fmt.Sprintf("Table(%s)", p.Table.String()))
 	case IndexAction_Rename:
 		_ = pr.WriteNode("RenameIndex")
 		_ = pr.WriteChildren(
 			fmt.Sprintf("Table(%s)", p.Table.String()),
-			fmt.Sprintf("FromIndex(%s)", p.PreviousIndexName),
-			fmt.Sprintf("ToIndex(%s)", p.IndexName),
+			fmt.Sprintf("FromIndex(%s)", sql.QuoteIdentifier(p.PreviousIndexName)),
+			fmt.Sprintf("ToIndex(%s)", sql.QuoteIdentifier(p.IndexName)),
 		)
 	default:
 		_ = pr.WriteNode("Unknown_Index_Action(%v)", p.Action)
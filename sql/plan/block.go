@@ -88,9 +88,13 @@ func (b *Block) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	var returnRows []sql.Row
 	var returnNode sql.Node
 	var returnSch sql.Schema
+	var resultSets []ResultSet
 
 	selectSeen := false
 	for _, s := range b.statements {
+		if err := ctx.CheckInterrupted(); err != nil {
+			return nil, err
+		}
 		err := func() error {
 			rowCache, disposeFunc := ctx.Memory.NewRowsCache()
 			defer disposeFunc()
@@ -125,6 +129,16 @@ func (b *Block) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 					if isSelect || !selectSeen {
 						returnRows = rowCache.Get()
 					}
+					// a block, or a CALL of a procedure with one, gives the result sets of its SELECTs
+					var subResultSets []ResultSet
+					if rsIter, ok := subIter.(ResultSetsRowIter); ok {
+						subResultSets = rsIter.ResultSets()
+					}
+					if subResultSets != nil {
+						resultSets = append(resultSets, subResultSets...)
+					} else if isSelect {
+						resultSets = append(resultSets, ResultSet{Schema: subIterSch, Rows: returnRows})
+					}
 					break
 				} else if err != nil {
 					return err
@@ -147,6 +161,7 @@ func (b *Block) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 		internalIter: sql.RowsToRowIter(returnRows...),
 		repNode:      returnNode,
 		sch:          returnSch,
+		resultSets:   resultSets,
 	}, nil
 }
 
@@ -155,9 +170,11 @@ type blockIter struct {
 	internalIter sql.RowIter
 	repNode      sql.Node
 	sch          sql.Schema
+	resultSets   []ResultSet
 }
 
 var _ BlockRowIter = (*blockIter)(nil)
+var _ ResultSetsRowIter = (*blockIter)(nil)
 
 // Next implements the sql.RowIter interface.
 func (i *blockIter) Next() (sql.Row, error) {
@@ -178,3 +195,8 @@ func (i *blockIter) RepresentingNode() sql.Node {
 func (i *blockIter) Schema() sql.Schema {
 	return i.sch
 }
+
+// ResultSets implements the ResultSetsRowIter interface.
+func (i *blockIter) ResultSets() []ResultSet {
+	return i.resultSets
+}
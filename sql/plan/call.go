@@ -144,11 +144,39 @@ type callIter struct {
 	innerIter sql.RowIter
 }
 
+var _ ResultSetsRowIter = (*callIter)(nil)
+var _ BlockRowIter = (*callIter)(nil)
+
 // Next implements the sql.RowIter interface.
 func (iter *callIter) Next() (sql.Row, error) {
 	return iter.innerIter.Next()
 }
 
+// RepresentingNode implements the BlockRowIter interface, so that a block calling the procedure sees whether its rows
+// are those of a SELECT.
+func (iter *callIter) RepresentingNode() sql.Node {
+	if blockIter, ok := iter.innerIter.(BlockRowIter); ok {
+		return blockIter.RepresentingNode()
+	}
+	return iter.call.proc.Body
+}
+
+// Schema implements the BlockRowIter interface.
+func (iter *callIter) Schema() sql.Schema {
+	if blockIter, ok := iter.innerIter.(BlockRowIter); ok {
+		return blockIter.Schema()
+	}
+	return iter.call.proc.Schema()
+}
+
+// ResultSets implements the ResultSetsRowIter interface.
+func (iter *callIter) ResultSets() []ResultSet {
+	if rsIter, ok := iter.innerIter.(ResultSetsRowIter); ok {
+		return rsIter.ResultSets()
+	}
+	return nil
+}
+
 // Close implements the sql.RowIter interface.
 func (iter *callIter) Close(ctx *sql.Context) error {
 	err := iter.innerIter.Close(ctx)
@@ -91,6 +91,22 @@ type BlockRowIter interface {
 	Schema() sql.Schema
 }
 
+// ResultSet is the schema and rows returned by a statement.
+type ResultSet struct {
+	Schema sql.Schema
+	Rows   []sql.Row
+}
+
+// ResultSetsRowIter is a sql.RowIter that may return more than one result set, such as the iterator of a CALL to a
+// stored procedure running several SELECT statements. Iterating over it returns the rows of the last result set only.
+type ResultSetsRowIter interface {
+	sql.RowIter
+	// ResultSets returns all the result sets produced by the SELECT statements executed, in order. Returns nil if none
+	// were executed, or if the iterator is not over a block of statements (such as a procedure whose body is a single
+	// SELECT), in which case its rows are the only result set.
+	ResultSets() []ResultSet
+}
+
 // nodeRepresentsSelect attempts to walk a sql.Node to determine if it represents a SELECT statement.
 func nodeRepresentsSelect(s sql.Node) bool {
 	if s == nil {
@@ -112,3 +128,53 @@ func nodeRepresentsSelect(s sql.Node) bool {
 	})
 	return isSelect
 }
+
+// checkReadOnly returns ErrReadOnlyTransaction if the session of the context given is read-only, which every node
+// writing data or changing the schema checks before executing. A sql.ReadOnlyBypassSession that bypasses read_only may
+// write unless super_read_only is enabled too.
+func checkReadOnly(ctx *sql.Context) error {
+	if ctx.Session == nil || !ctx.IsReadOnly() {
+		return nil
+	}
+
+	if _, val := ctx.Get(sql.SuperReadOnlySessionVar); val != nil {
+		if enabled, err := sql.ConvertToBool(val); err == nil && enabled {
+			return sql.ErrReadOnlyTransaction.New("--super-read-only")
+		}
+	}
+	if bs, ok := ctx.Session.(sql.ReadOnlyBypassSession); ok && bs.BypassesReadOnly() {
+		return nil
+	}
+	return sql.ErrReadOnlyTransaction.New("--read-only")
+}
+
+// checkTableReadOnly is checkReadOnly for the nodes writing the rows of the table in the node given. As in MySQL, the
+// temporary tables of the session may be written even if it's read-only.
+func checkTableReadOnly(ctx *sql.Context, target sql.Node) error {
+	if ctx.Session != nil {
+		if rt := getResolvedTable(target); rt != nil && rt.Database != nil {
+			if _, ok := ctx.TemporaryTables().Table(rt.Database.Name(), rt.Name()); ok {
+				return nil
+			}
+		}
+	}
+	return checkReadOnly(ctx)
+}
+
+// getResolvedTable returns the first table in the node given, or nil if there is none.
+func getResolvedTable(node sql.Node) *ResolvedTable {
+	switch n := node.(type) {
+	case *ResolvedTable:
+		return n
+	case *IndexedTableAccess:
+		return n.ResolvedTable
+	case *PrimaryKeyLookup:
+		return n.ResolvedTable
+	}
+	for _, child := range node.Children() {
+		if rt := getResolvedTable(child); rt != nil {
+			return rt
+		}
+	}
+	return nil
+}
@@ -110,6 +110,10 @@ func getChecksumable(t sql.Table) sql.Checksumable {
 
 // RowIter implements the Node interface.
 func (c *CreateIndex) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	table, ok := c.Table.(*ResolvedTable)
 	if !ok {
 		return nil, ErrNotIndexable.New()
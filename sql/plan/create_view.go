@@ -77,6 +77,10 @@ func (cv *CreateView) Resolved() bool {
 // set to false and the view already exists. The RowIter returned is always
 // empty.
 func (cv *CreateView) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	view := cv.View()
 	registry := ctx.ViewRegistry
 
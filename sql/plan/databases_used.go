@@ -0,0 +1,84 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// DatabasesUsed returns the names of the databases of every table the plan given reads or writes, deduplicated
+// case-insensitively, in the order they're first found. It looks into subqueries, common table expressions and the
+// source of INSERT statements, so it works for both resolved and unresolved plans. Unresolved tables that aren't
+// qualified with a database are in the current database, which isn't known here, so they aren't included.
+func DatabasesUsed(node sql.Node) []string {
+	var dbs []string
+	seen := make(map[string]bool)
+	add := func(db string) {
+		if db == "" || seen[strings.ToLower(db)] {
+			return
+		}
+		seen[strings.ToLower(db)] = true
+		dbs = append(dbs, db)
+	}
+
+	var inspect func(sql.Node)
+	inspect = func(node sql.Node) {
+		Inspect(node, func(node sql.Node) bool {
+			if n, ok := node.(sql.Expressioner); ok {
+				for _, e := range n.Expressions() {
+					sql.Inspect(e, func(e sql.Expression) bool {
+						if sq, ok := e.(*Subquery); ok {
+							inspect(sq.Query)
+						}
+						return true
+					})
+				}
+			}
+
+			switch n := node.(type) {
+			case *ResolvedTable:
+				addResolvedTableDatabase(n, add)
+			case *IndexedTableAccess:
+				addResolvedTableDatabase(n.ResolvedTable, add)
+			case *PrimaryKeyLookup:
+				addResolvedTableDatabase(n.ResolvedTable, add)
+			case *UnresolvedTable:
+				add(n.Database)
+			case *With:
+				for _, cte := range n.CTEs {
+					inspect(cte.Subquery)
+				}
+			case *InsertInto:
+				// the source isn't a child of the node, and is inspected after its destination
+				inspect(n.Destination)
+				inspect(n.Source)
+				return false
+			}
+
+			return true
+		})
+	}
+	inspect(node)
+
+	return dbs
+}
+
+func addResolvedTableDatabase(t *ResolvedTable, add func(string)) {
+	if t.Database != nil {
+		add(t.Database.Name())
+	}
+}
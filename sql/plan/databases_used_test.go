@@ -0,0 +1,72 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+func TestDatabasesUsed(t *testing.T) {
+	require := require.New(t)
+
+	schema := sql.Schema{{Name: "i", Type: sql.Int64}}
+	db1 := memory.NewDatabase("db1")
+	db2 := memory.NewDatabase("db2")
+	t1 := NewResolvedTable(memory.NewTable("t1", schema), db1, nil)
+	t2 := NewResolvedTable(memory.NewTable("t2", schema), db2, nil)
+
+	// SELECT * FROM db1.t1 JOIN db2.t2 AS b ON t1.i = b.i
+	join := NewInnerJoin(t1, NewTableAlias("b", t2), expression.NewEquals(
+		expression.NewGetFieldWithTable(0, sql.Int64, "t1", "i", false),
+		expression.NewGetFieldWithTable(1, sql.Int64, "b", "i", false),
+	))
+	require.Equal([]string{"db1", "db2"}, DatabasesUsed(join))
+
+	// WITH cte AS (SELECT * FROM db4.t4) SELECT * FROM (the join)
+	// WHERE i IN (SELECT i FROM db3.t3) AND i IN (SELECT i FROM DB2.t2) AND i IN (SELECT i FROM t5)
+	filter := NewFilter(
+		expression.NewAnd(
+			expression.NewAnd(
+				expression.NewInTuple(
+					expression.NewUnresolvedColumn("i"),
+					NewSubquery(NewUnresolvedTable("t3", "db3"), "SELECT i FROM db3.t3"),
+				),
+				expression.NewInTuple(
+					expression.NewUnresolvedColumn("i"),
+					NewSubquery(NewUnresolvedTable("t2", "DB2"), "SELECT i FROM DB2.t2"),
+				),
+			),
+			expression.NewInTuple(
+				expression.NewUnresolvedColumn("i"),
+				NewSubquery(NewUnresolvedTable("t5", ""), "SELECT i FROM t5"),
+			),
+		),
+		join,
+	)
+	with := NewWith(filter, []*CommonTableExpression{
+		NewCommonTableExpression(NewSubqueryAlias("cte", "SELECT * FROM db4.t4", NewUnresolvedTable("t4", "db4")), nil),
+	})
+	require.Equal([]string{"db4", "db3", "DB2", "db1"}, DatabasesUsed(with))
+
+	// INSERT INTO db1.t1 SELECT * FROM db2.t2
+	insert := NewInsertInto(db1, t1, NewProject([]sql.Expression{expression.NewStar()}, t2), false, nil, nil, nil)
+	require.Equal([]string{"db1", "db2"}, DatabasesUsed(insert))
+}
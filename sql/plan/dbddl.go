@@ -52,13 +52,18 @@ func (c CreateDB) Children() []sql.Node {
 }
 
 func (c CreateDB) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	exists := c.Catalog.HasDB(c.dbName)
 	if exists {
 		if c.IfNotExists {
 			ctx.Session.Warn(&sql.Warning{
-				Level:   "Note",
-				Code:    mysql.ERDbCreateExists,
-				Message: fmt.Sprintf("Can't create database %s; database exists ", c.dbName),
+				Level:    "Note",
+				Code:     mysql.ERDbCreateExists,
+				SQLState: sql.SQLStateForCode(mysql.ERDbCreateExists),
+				Message:  fmt.Sprintf("Can't create database %s; database exists ", c.dbName),
 			})
 
 			return sql.RowsToRowIter(), nil
@@ -114,13 +119,18 @@ func (d DropDB) Children() []sql.Node {
 }
 
 func (d DropDB) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	exists := d.Catalog.HasDB(d.dbName)
 	if !exists {
 		if d.IfExists {
 			ctx.Session.Warn(&sql.Warning{
-				Level:   "Note",
-				Code:    mysql.ERDbDropExists,
-				Message: fmt.Sprintf("Can't drop database %s; database doesn't exist ", d.dbName),
+				Level:    "Note",
+				Code:     mysql.ERDbDropExists,
+				SQLState: sql.SQLStateForCode(mysql.ERDbDropExists),
+				Message:  fmt.Sprintf("Can't drop database %s; database doesn't exist ", d.dbName),
 			})
 
 			return sql.RowsToRowIter(), nil
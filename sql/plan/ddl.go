@@ -30,6 +30,9 @@ var ErrCreateTableNotSupported = errors.NewKind("tables cannot be created on dat
 // ErrDropTableNotSupported is thrown when the database doesn't support dropping tables
 var ErrDropTableNotSupported = errors.NewKind("tables cannot be dropped on database %s")
 
+// ErrTemporaryTablesNotSupported is thrown when the database or the session doesn't support temporary tables
+var ErrTemporaryTablesNotSupported = errors.NewKind("temporary tables cannot be created on database %s")
+
 // ErrRenameTableNotSupported is thrown when the database doesn't support renaming tables
 var ErrRenameTableNotSupported = errors.NewKind("tables cannot be renamed on database %s")
 
@@ -81,6 +84,8 @@ type TableSpec struct {
 	FkDefs  []*sql.ForeignKeyConstraint
 	ChDefs  []*sql.CheckConstraint
 	IdxDefs []*IndexDefinition
+	// Engine is the storage engine named by the ENGINE option, if any.
+	Engine string
 }
 
 func (c *TableSpec) WithSchema(schema sql.Schema) (*TableSpec, error) {
@@ -117,6 +122,10 @@ type CreateTable struct {
 	chDefs      []*sql.CheckConstraint
 	idxDefs     []*IndexDefinition
 	like        sql.Node
+	temporary   bool
+	engine      string
+	// Catalog holds the storage engines the table may be created with.
+	Catalog *sql.Catalog
 }
 
 var _ sql.Databaser = (*CreateTable)(nil)
@@ -137,6 +146,7 @@ func NewCreateTable(db sql.Database, name string, ifNotExists bool, tableSpec *T
 		chDefs:      tableSpec.ChDefs,
 		idxDefs:     tableSpec.IdxDefs,
 		ifNotExists: ifNotExists,
+		engine:      tableSpec.Engine,
 	}
 }
 
@@ -150,6 +160,54 @@ func NewCreateTableLike(db sql.Database, name string, likeTable sql.Node, ifNotE
 	}
 }
 
+// AsTemporary returns a copy of this node creating a temporary table, which belongs to the session and shadows any
+// table of the database with the same name.
+func (c *CreateTable) AsTemporary() *CreateTable {
+	nc := *c
+	nc.temporary = true
+	return &nc
+}
+
+// Temporary returns whether the table created is a temporary table.
+func (c *CreateTable) Temporary() bool {
+	return c.temporary
+}
+
+// Engine returns the storage engine named by the ENGINE option of the statement, or "" if it has none.
+func (c *CreateTable) Engine() string {
+	return c.engine
+}
+
+// storageEngine returns the storage engine of the table created: the one of the ENGINE option, or else the default
+// of the session. An unknown engine is replaced by the default of the session, or by sql.DefaultStorageEngine if the
+// default is unknown too, with a warning.
+func (c *CreateTable) storageEngine(ctx *sql.Context) string {
+	name := c.engine
+	if name == "" {
+		name = ctx.Session.DefaultStorageEngine()
+	}
+	engines := storageEngines(c.Catalog)
+	if e, ok := sql.EngineByName(engines, name); ok {
+		return e.Name
+	}
+
+	ctx.Warn(1286, "Unknown storage engine '%s'", name)
+	engine := sql.DefaultStorageEngine
+	if e, ok := sql.EngineByName(engines, ctx.Session.DefaultStorageEngine()); ok {
+		engine = e.Name
+	}
+	ctx.Warn(1266, "Using storage engine %s for table '%s'", engine, c.name)
+	return engine
+}
+
+// storageEngines returns the storage engines of the catalog given, or sql.SupportedEngines without one.
+func storageEngines(catalog *sql.Catalog) []sql.Engine {
+	if catalog == nil {
+		return sql.SupportedEngines
+	}
+	return catalog.StorageEngines
+}
+
 // WithDatabase implements the sql.Databaser interface.
 func (c *CreateTable) WithDatabase(db sql.Database) (sql.Node, error) {
 	nc := *c
@@ -173,13 +231,27 @@ func (c *CreateTable) Resolved() bool {
 
 // RowIter implements the Node interface.
 func (c *CreateTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	// As in MySQL, temporary tables can be created by read-only sessions
+	if c.temporary {
+		return c.createTemporaryTable(ctx)
+	}
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	creatable, ok := c.db.(sql.TableCreator)
 	if ok {
 		if err := c.validateDefaultPosition(); err != nil {
 			return sql.RowsToRowIter(), err
 		}
 
-		err := creatable.CreateTable(ctx, c.name, c.schema)
+		engine := c.storageEngine(ctx)
+		var err error
+		if ec, ok := creatable.(sql.EngineTableCreator); ok {
+			err = ec.CreateTableWithEngine(ctx, c.name, c.schema, engine)
+		} else {
+			err = creatable.CreateTable(ctx, c.name, c.schema)
+		}
 		if err != nil && !(sql.ErrTableAlreadyExists.Is(err) && c.ifNotExists) {
 			return sql.RowsToRowIter(), err
 		}
@@ -193,45 +265,8 @@ func (c *CreateTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error
 			if !ok {
 				return sql.RowsToRowIter(), ErrTableCreatedNotFound.New()
 			}
-			if len(c.idxDefs) > 0 {
-				idxAlterable, ok := tableNode.(sql.IndexAlterableTable)
-				if !ok {
-					return sql.RowsToRowIter(), ErrNotIndexable.New()
-				}
-				for _, idxDef := range c.idxDefs {
-					err = idxAlterable.CreateIndex(ctx, idxDef.IndexName, idxDef.Using, idxDef.Constraint, idxDef.Columns, idxDef.Comment)
-					if err != nil {
-						return sql.RowsToRowIter(), err
-					}
-				}
-			}
-			if len(c.fkDefs) > 0 {
-				fkAlterable, ok := tableNode.(sql.ForeignKeyAlterableTable)
-				if !ok {
-					return sql.RowsToRowIter(), ErrNoForeignKeySupport.New(c.name)
-				}
-				for _, fkDef := range c.fkDefs {
-					err = fkAlterable.CreateForeignKey(ctx, fkDef.Name, fkDef.Columns, fkDef.ReferencedTable, fkDef.ReferencedColumns, fkDef.OnUpdate, fkDef.OnDelete)
-					if err != nil {
-						return sql.RowsToRowIter(), err
-					}
-				}
-			}
-			if len(c.chDefs) > 0 {
-				chAlterable, ok := tableNode.(sql.CheckAlterableTable)
-				if !ok {
-					return sql.RowsToRowIter(), ErrNoCheckConstraintSupport.New(c.name)
-				}
-				for _, ch := range c.chDefs {
-					check, err := NewCheckDefinition(ch)
-					if err != nil {
-						return nil, err
-					}
-					err = chAlterable.CreateCheck(ctx, check)
-					if err != nil {
-						return sql.RowsToRowIter(), err
-					}
-				}
+			if err := c.createIndexesAndConstraints(ctx, tableNode); err != nil {
+				return sql.RowsToRowIter(), err
 			}
 		}
 		return sql.RowsToRowIter(), nil
@@ -240,6 +275,78 @@ func (c *CreateTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error
 	return nil, ErrCreateTableNotSupported.New(c.db.Name())
 }
 
+// createTemporaryTable creates the table as a temporary table of the session.
+func (c *CreateTable) createTemporaryTable(ctx *sql.Context) (sql.RowIter, error) {
+	creatable, ok := c.db.(sql.TemporaryTableCreator)
+	temps := ctx.TemporaryTables()
+	if !ok || temps == nil {
+		return nil, ErrTemporaryTablesNotSupported.New(c.db.Name())
+	}
+	if err := c.validateDefaultPosition(); err != nil {
+		return sql.RowsToRowIter(), err
+	}
+
+	if _, ok := temps.Table(c.db.Name(), c.name); ok {
+		if c.ifNotExists {
+			return sql.RowsToRowIter(), nil
+		}
+		return sql.RowsToRowIter(), sql.ErrTableAlreadyExists.New(c.name)
+	}
+
+	table, err := creatable.CreateTemporaryTable(ctx, c.name, c.schema)
+	if err != nil {
+		return sql.RowsToRowIter(), err
+	}
+	if err := c.createIndexesAndConstraints(ctx, table); err != nil {
+		return sql.RowsToRowIter(), err
+	}
+	return sql.RowsToRowIter(), temps.Register(c.db.Name(), table)
+}
+
+func (c *CreateTable) createIndexesAndConstraints(ctx *sql.Context, tableNode sql.Table) error {
+	if len(c.idxDefs) > 0 {
+		idxAlterable, ok := tableNode.(sql.IndexAlterableTable)
+		if !ok {
+			return ErrNotIndexable.New()
+		}
+		for _, idxDef := range c.idxDefs {
+			err := idxAlterable.CreateIndex(ctx, idxDef.IndexName, idxDef.Using, idxDef.Constraint, idxDef.Columns, idxDef.Comment)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.fkDefs) > 0 {
+		fkAlterable, ok := tableNode.(sql.ForeignKeyAlterableTable)
+		if !ok {
+			return ErrNoForeignKeySupport.New(c.name)
+		}
+		for _, fkDef := range c.fkDefs {
+			err := fkAlterable.CreateForeignKey(ctx, fkDef.Name, fkDef.Columns, fkDef.ReferencedTable, fkDef.ReferencedColumns, fkDef.OnUpdate, fkDef.OnDelete)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.chDefs) > 0 {
+		chAlterable, ok := tableNode.(sql.CheckAlterableTable)
+		if !ok {
+			return ErrNoCheckConstraintSupport.New(c.name)
+		}
+		for _, ch := range c.chDefs {
+			check, err := NewCheckDefinition(ch)
+			if err != nil {
+				return err
+			}
+			err = chAlterable.CreateCheck(ctx, check)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Children implements the Node interface.
 func (c *CreateTable) Children() []sql.Node {
 	if c.like != nil {
@@ -266,7 +373,10 @@ func (c *CreateTable) String() string {
 	if c.ifNotExists {
 		ifNotExists = "if not exists "
 	}
-	return fmt.Sprintf("Create table %s%s", ifNotExists, c.name)
+	if c.temporary {
+		return fmt.Sprintf("Create temporary table %s%s", ifNotExists, sql.QuoteIdentifier(c.name))
+	}
+	return fmt.Sprintf("Create table %s%s", ifNotExists, sql.QuoteIdentifier(c.name))
 }
 
 func (c *CreateTable) Expressions() []sql.Expression {
@@ -324,6 +434,7 @@ type DropTable struct {
 	names        []string
 	ifExists     bool
 	triggerNames []string
+	temporary    bool
 }
 
 var _ sql.Node = (*DropTable)(nil)
@@ -345,6 +456,13 @@ func (d *DropTable) WithDatabase(db sql.Database) (sql.Node, error) {
 	return &nc, nil
 }
 
+// AsTemporary returns a copy of this node dropping only temporary tables, as DROP TEMPORARY TABLE does.
+func (d *DropTable) AsTemporary() *DropTable {
+	nd := *d
+	nd.temporary = true
+	return &nd
+}
+
 // WithTriggers returns this node but with the given triggers.
 func (d *DropTable) WithTriggers(triggers []string) sql.Node {
 	nd := *d
@@ -359,13 +477,44 @@ func (d *DropTable) TableNames() []string {
 
 // RowIter implements the Node interface.
 func (d *DropTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	// Temporary tables shadow the tables of the database, so they're dropped first. Every name is checked before any
+	// table is dropped, so that a failing statement drops nothing.
+	temps := ctx.TemporaryTables()
+	var tempNames, permanentNames []string
+	for _, tableName := range d.names {
+		if _, ok := temps.Table(d.db.Name(), tableName); ok {
+			tempNames = append(tempNames, tableName)
+			continue
+		}
+		if d.temporary {
+			if d.ifExists {
+				continue
+			}
+			return nil, sql.ErrTableNotFound.New(tableName)
+		}
+		permanentNames = append(permanentNames, tableName)
+	}
+	// As in MySQL, temporary tables can be dropped by read-only sessions
+	if len(permanentNames) > 0 {
+		if err := checkReadOnly(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, tableName := range tempNames {
+		temps.Delete(d.db.Name(), tableName)
+	}
+	if len(permanentNames) == 0 {
+		return sql.RowsToRowIter(), nil
+	}
+
 	droppable, ok := d.db.(sql.TableDropper)
 	if !ok {
 		return nil, ErrDropTableNotSupported.New(d.db.Name())
 	}
 
 	var err error
-	for _, tableName := range d.names {
+	for _, tableName := range permanentNames {
 		tbl, ok, err := d.db.GetTableInsensitive(ctx, tableName)
 
 		if err != nil {
@@ -409,10 +558,13 @@ func (d *DropTable) WithChildren(children ...sql.Node) (sql.Node, error) {
 
 func (d *DropTable) String() string {
 	ifExists := ""
-	names := strings.Join(d.names, ", ")
+	names := strings.Join(sql.QuoteIdentifiers(d.names), ", ")
 	if d.ifExists {
 		ifExists = "if exists "
 	}
+	if d.temporary {
+		return fmt.Sprintf("Drop temporary table %s%s", ifExists, names)
+	}
 	return fmt.Sprintf("Drop table %s%s", ifExists, names)
 }
 
@@ -441,10 +593,14 @@ func (r *RenameTable) WithDatabase(db sql.Database) (sql.Node, error) {
 }
 
 func (r *RenameTable) String() string {
-	return fmt.Sprintf("Rename table %s to %s", r.oldNames, r.newNames)
+	return fmt.Sprintf("Rename table %s to %s", strings.Join(sql.QuoteIdentifiers(r.oldNames), ", "), strings.Join(sql.QuoteIdentifiers(r.newNames), ", "))
 }
 
 func (r *RenameTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	renamer, ok := r.db.(sql.TableRenamer)
 	if !ok {
 		return nil, ErrRenameTableNotSupported.New(r.db.Name())
@@ -520,10 +676,14 @@ func (a *AddColumn) Schema() sql.Schema {
 }
 
 func (a *AddColumn) String() string {
-	return fmt.Sprintf("add column %s", a.column.Name)
+	return fmt.Sprintf("add column %s", sql.QuoteIdentifier(a.column.Name))
 }
 
 func (a *AddColumn) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	alterable, err := getAlterableTable(a.db, ctx, a.tableName)
 	if err != nil {
 		return nil, err
@@ -629,10 +789,14 @@ func (d *DropColumn) WithDatabase(db sql.Database) (sql.Node, error) {
 }
 
 func (d *DropColumn) String() string {
-	return fmt.Sprintf("drop column %s", d.column)
+	return fmt.Sprintf("drop column %s", sql.QuoteIdentifier(d.column))
 }
 
 func (d *DropColumn) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	alterable, err := getAlterableTable(d.db, ctx, d.tableName)
 	if err != nil {
 		return nil, err
@@ -704,10 +868,14 @@ func (r *RenameColumn) WithDatabase(db sql.Database) (sql.Node, error) {
 }
 
 func (r *RenameColumn) String() string {
-	return fmt.Sprintf("rename column %s to %s", r.columnName, r.newColumnName)
+	return fmt.Sprintf("rename column %s to %s", sql.QuoteIdentifier(r.columnName), sql.QuoteIdentifier(r.newColumnName))
 }
 
 func (r *RenameColumn) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	alterable, err := getAlterableTable(r.db, ctx, r.tableName)
 	if err != nil {
 		return nil, err
@@ -784,10 +952,14 @@ func (m *ModifyColumn) Schema() sql.Schema {
 }
 
 func (m *ModifyColumn) String() string {
-	return fmt.Sprintf("modify column %s", m.column.Name)
+	return fmt.Sprintf("modify column %s", sql.QuoteIdentifier(m.column.Name))
 }
 
 func (m *ModifyColumn) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	alterable, err := getAlterableTable(m.db, ctx, m.tableName)
 	if err != nil {
 		return nil, err
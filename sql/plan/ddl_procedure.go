@@ -25,8 +25,7 @@ import (
 
 type CreateProcedure struct {
 	*Procedure
-	BodyString string
-	Db         sql.Database
+	Db sql.Database
 }
 
 var _ sql.Node = (*CreateProcedure)(nil)
@@ -55,9 +54,9 @@ func NewCreateProcedure(
 		body,
 		createdAt,
 		modifiedAt)
+	procedure.BodyString = bodyString
 	return &CreateProcedure{
-		Procedure:  procedure,
-		BodyString: bodyString,
+		Procedure: procedure,
 	}
 }
 
@@ -155,6 +154,10 @@ func (c *CreateProcedure) DebugString() string {
 
 // RowIter implements the sql.Node interface.
 func (c *CreateProcedure) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	return &createProcedureIter{
 		spd: sql.StoredProcedureDetails{
 			Name:            c.Name,
@@ -112,3 +112,19 @@ func createTable(t *testing.T, db sql.Database, name string, schema sql.Schema,
 	require.Equal(t, io.EOF, err)
 	return nil
 }
+
+func TestDDLStringQuotesIdentifiers(t *testing.T) {
+	require := require.New(t)
+
+	db := memory.NewDatabase("test")
+	table := memory.NewTable("my table", sql.Schema{
+		{Name: "select", Type: sql.Int64, Source: "my table"},
+	})
+
+	require.Equal("Create table `my table`", NewCreateTable(db, "my table", false, &TableSpec{}).String())
+	require.Equal("Drop table if exists `my table`, t2", NewDropTable(db, true, "my table", "t2").String())
+	require.Equal("Rename table `my table`, t2 to `your table`, t3",
+		NewRenameTable(db, []string{"my table", "t2"}, []string{"your table", "t3"}).String())
+	require.Equal("Table(`my table`)", NewResolvedTable(table, db, nil).String())
+	require.Equal("Delete\n └─ Table(`my table`)\n", NewDeleteFrom(NewResolvedTable(table, db, nil)).String())
+}
@@ -136,6 +136,10 @@ func (c *createTriggerIter) Close(*sql.Context) error {
 }
 
 func (c *CreateTrigger) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	return &createTriggerIter{
 		definition: sql.TriggerDefinition{
 			Name:            c.TriggerName,
@@ -38,6 +38,8 @@ func getDeletable(node sql.Node) (sql.DeletableTable, error) {
 		return node, nil
 	case *IndexedTableAccess:
 		return getDeletable(node.ResolvedTable)
+	case *PrimaryKeyLookup:
+		return getDeletable(node.ResolvedTable)
 	case *ResolvedTable:
 		return getDeletableTable(node.Table)
 	case sql.TableWrapper:
@@ -69,6 +71,8 @@ func deleteDatabaseHelper(node sql.Node) string {
 		return ""
 	case *IndexedTableAccess:
 		return deleteDatabaseHelper(node.ResolvedTable)
+	case *PrimaryKeyLookup:
+		return deleteDatabaseHelper(node.ResolvedTable)
 	case *ResolvedTable:
 		return node.Database.Name()
 	case *UnresolvedTable:
@@ -88,6 +92,10 @@ func (p *DeleteFrom) Database() string {
 
 // RowIter implements the Node interface.
 func (p *DeleteFrom) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkTableReadOnly(ctx, p.Child); err != nil {
+		return nil, err
+	}
+
 	deletable, err := getDeletable(p.Child)
 	if err != nil {
 		return nil, err
@@ -100,15 +108,22 @@ func (p *DeleteFrom) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 
 	deleter := deletable.Deleter(ctx)
 
-	return newDeleteIter(iter, deleter, deletable.Schema(), ctx), nil
+	deleteIter := newDeleteIter(iter, deleter, deletable.Schema(), ctx)
+	deleteIter.foreignKeys, err = newForeignKeyCascader(ctx, getResolvedTableDatabase(p.Child), deletable)
+	if err != nil {
+		_ = deleteIter.Close(ctx)
+		return nil, err
+	}
+	return deleteIter, nil
 }
 
 type deleteIter struct {
-	deleter   sql.RowDeleter
-	schema    sql.Schema
-	childIter sql.RowIter
-	ctx       *sql.Context
-	closed    bool
+	deleter     sql.RowDeleter
+	schema      sql.Schema
+	childIter   sql.RowIter
+	foreignKeys *foreignKeyCascader
+	ctx         *sql.Context
+	closed      bool
 }
 
 func (d *deleteIter) Next() (sql.Row, error) {
@@ -124,16 +139,20 @@ func (d *deleteIter) Next() (sql.Row, error) {
 		row = row[len(row)-len(d.schema):]
 	}
 
-	return row, d.deleter.Delete(d.ctx, row)
+	if d.foreignKeys != nil {
+		if err = d.foreignKeys.OnDelete(d.ctx, row); err != nil {
+			return nil, err
+		}
+	}
+
+	// The values of virtual columns are computed when read, the table is given the row it stores
+	return row, d.deleter.Delete(d.ctx, d.schema.StoredRow(row))
 }
 
 func (d *deleteIter) Close(ctx *sql.Context) error {
 	if !d.closed {
 		d.closed = true
-		if err := d.deleter.Close(ctx); err != nil {
-			return err
-		}
-		return d.childIter.Close(ctx)
+		return sql.CloseAll(ctx, d.deleter, d.childIter)
 	}
 	return nil
 }
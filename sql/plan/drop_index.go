@@ -57,6 +57,10 @@ func (d *DropIndex) Children() []sql.Node { return []sql.Node{d.Table} }
 
 // RowIter implements the Node interface.
 func (d *DropIndex) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	db, err := d.Catalog.Database(d.CurrentDatabase)
 	if err != nil {
 		return nil, err
@@ -66,6 +66,10 @@ func (d *DropProcedure) Children() []sql.Node {
 
 // RowIter implements the sql.Node interface.
 func (d *DropProcedure) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	procDb, ok := d.db.(sql.StoredProcedureDatabase)
 	if !ok {
 		if d.IfExists {
@@ -66,6 +66,10 @@ func (d *DropTrigger) Children() []sql.Node {
 
 // RowIter implements the sql.Node interface.
 func (d *DropTrigger) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	triggerDb, ok := d.db.(sql.TriggerDatabase)
 	if !ok {
 		if d.IfExists {
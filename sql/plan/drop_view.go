@@ -123,6 +123,10 @@ func (dvs *DropView) Resolved() bool {
 // all the views defined by the node's children. It errors if the flag ifExists
 // is set to false and there is some view that does not exist.
 func (dvs *DropView) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkReadOnly(ctx); err != nil {
+		return nil, err
+	}
+
 	viewList := make([]sql.ViewKey, len(dvs.children))
 	for i, child := range dvs.children {
 		drop, ok := child.(*SingleDropView)
@@ -15,7 +15,6 @@
 package plan
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"sync"
@@ -172,7 +171,7 @@ func (it *exchangeRowIter) start() {
 	for {
 		select {
 		case <-it.ctx.Done():
-			it.err <- context.Canceled
+			it.err <- it.ctx.CancellationError()
 			it.closeTokens()
 			return
 		case <-it.quit():
@@ -210,7 +209,7 @@ func (it *exchangeRowIter) iterPartitions(ch chan<- sql.Partition) {
 	for {
 		select {
 		case <-it.ctx.Done():
-			it.err <- context.Canceled
+			it.err <- it.ctx.CancellationError()
 			return
 		case <-it.quit():
 			return
@@ -264,7 +263,7 @@ func (it *exchangeRowIter) iterPartition(p sql.Partition) {
 	for {
 		select {
 		case <-it.ctx.Done():
-			it.err <- context.Canceled
+			it.err <- it.ctx.CancellationError()
 			return
 		case <-it.quit():
 			return
@@ -15,6 +15,8 @@
 package plan
 
 import (
+	"time"
+
 	"github.com/dolthub/go-mysql-server/sql"
 )
 
@@ -93,8 +95,16 @@ type FilterIter struct {
 	cond      sql.Expression
 	childIter sql.RowIter
 	ctx       *sql.Context
+
+	// stats are only collected once EnableConditionStats is called
+	stats    bool
+	passed   int
+	filtered int
+	evalTime time.Duration
 }
 
+var _ sql.ConditionStatsIter = (*FilterIter)(nil)
+
 // NewFilterIter creates a new FilterIter.
 func NewFilterIter(
 	ctx *sql.Context,
@@ -112,17 +122,41 @@ func (i *FilterIter) Next() (sql.Row, error) {
 			return nil, err
 		}
 
+		var start time.Time
+		if i.stats {
+			start = time.Now()
+		}
+
 		ok, err := sql.EvaluateCondition(i.ctx, i.cond, row)
 		if err != nil {
 			return nil, err
 		}
 
+		if i.stats {
+			i.evalTime += time.Since(start)
+			if ok {
+				i.passed++
+			} else {
+				i.filtered++
+			}
+		}
+
 		if ok {
 			return row, nil
 		}
 	}
 }
 
+// EnableConditionStats implements the sql.ConditionStatsIter interface.
+func (i *FilterIter) EnableConditionStats() {
+	i.stats = true
+}
+
+// ConditionStats implements the sql.ConditionStatsIter interface.
+func (i *FilterIter) ConditionStats() (passed, filtered int, evalTime time.Duration) {
+	return i.passed, i.filtered, i.evalTime
+}
+
 // Close implements the RowIter interface.
 func (i *FilterIter) Close(ctx *sql.Context) error {
 	return i.childIter.Close(ctx)
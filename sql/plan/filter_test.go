@@ -15,8 +15,10 @@
 package plan
 
 import (
+	"context"
 	"testing"
 
+	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/go-mysql-server/memory"
@@ -101,3 +103,56 @@ func TestFilter(t *testing.T) {
 	require.Equal(int32(3333), row[2])
 	require.Equal(int64(4444), row[3])
 }
+
+func TestFilterConditionStats(t *testing.T) {
+	require := require.New(t)
+
+	childSchema := sql.Schema{
+		{Name: "col1", Type: sql.Int32, Nullable: true},
+	}
+	child := memory.NewTable("test", childSchema)
+	for i := int32(1); i <= 5; i++ {
+		require.NoError(child.Insert(sql.NewEmptyContext(), sql.NewRow(i)))
+	}
+
+	f := NewFilter(
+		expression.NewGreaterThan(
+			expression.NewGetField(0, sql.Int32, "col1", true),
+			expression.NewLiteral(int32(3), sql.Int32)),
+		NewResolvedTable(child, nil, nil))
+
+	tracer := mocktracer.New()
+	ctx := sql.NewContext(context.Background(), sql.WithTracer(tracer))
+
+	iter, err := f.RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Len(rows, 2)
+
+	var filterSpan *mocktracer.MockSpan
+	for _, span := range tracer.FinishedSpans() {
+		if span.OperationName == "plan.Filter" {
+			filterSpan = span
+		}
+	}
+	require.NotNil(filterSpan)
+
+	fields := make(map[string]string)
+	for _, record := range filterSpan.Logs() {
+		for _, field := range record.Fields {
+			fields[field.Key] = field.ValueString
+		}
+	}
+	require.Equal("2", fields["rows_passed"])
+	require.Equal("3", fields["rows_filtered"])
+	require.Contains(fields, "eval_time")
+
+	// without tracing, no stats are collected
+	filterIter := NewFilterIter(sql.NewEmptyContext(), f.Expression, sql.RowsToRowIter(sql.NewRow(int32(4))))
+	_, err = sql.RowIterToRows(sql.NewEmptyContext(), filterIter)
+	require.NoError(err)
+	passed, filtered, _ := filterIter.ConditionStats()
+	require.Equal(0, passed)
+	require.Equal(0, filtered)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import "github.com/dolthub/go-mysql-server/sql"
+
+// FlushStatus is a FLUSH STATUS statement, which sets the status variables of the current session to zero. The
+// global status variables are left as they are, and are reset with Catalog.GlobalStatus.Reset.
+type FlushStatus struct{}
+
+var _ sql.Node = (*FlushStatus)(nil)
+
+// NewFlushStatus returns a new FlushStatus node.
+func NewFlushStatus() *FlushStatus {
+	return &FlushStatus{}
+}
+
+// Children implements the sql.Node interface.
+func (*FlushStatus) Children() []sql.Node { return nil }
+
+// Resolved implements the sql.Node interface.
+func (*FlushStatus) Resolved() bool { return true }
+
+// Schema implements the sql.Node interface.
+func (*FlushStatus) Schema() sql.Schema { return nil }
+
+// RowIter implements the sql.Node interface.
+func (*FlushStatus) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	ctx.ResetStats()
+	return sql.RowsToRowIter(), nil
+}
+
+func (*FlushStatus) String() string {
+	return "FLUSH STATUS"
+}
+
+// WithChildren implements the sql.Node interface.
+func (f *FlushStatus) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), 0)
+	}
+	return f, nil
+}
+
+// FlushPrivileges is a FLUSH PRIVILEGES statement, which reloads the grants of the PrivilegeReloader of the catalog.
+// It does nothing if the catalog has none.
+type FlushPrivileges struct {
+	Catalog *sql.Catalog
+}
+
+var _ sql.Node = (*FlushPrivileges)(nil)
+
+// NewFlushPrivileges returns a new FlushPrivileges node.
+func NewFlushPrivileges() *FlushPrivileges {
+	return &FlushPrivileges{}
+}
+
+// Children implements the sql.Node interface.
+func (*FlushPrivileges) Children() []sql.Node { return nil }
+
+// Resolved implements the sql.Node interface.
+func (*FlushPrivileges) Resolved() bool { return true }
+
+// Schema implements the sql.Node interface.
+func (*FlushPrivileges) Schema() sql.Schema { return nil }
+
+// RowIter implements the sql.Node interface.
+func (f *FlushPrivileges) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if f.Catalog != nil && f.Catalog.PrivilegeReloader != nil {
+		if err := f.Catalog.PrivilegeReloader.Reload(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return sql.RowsToRowIter(), nil
+}
+
+func (*FlushPrivileges) String() string {
+	return "FLUSH PRIVILEGES"
+}
+
+// WithChildren implements the sql.Node interface.
+func (f *FlushPrivileges) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), 0)
+	}
+	return f, nil
+}
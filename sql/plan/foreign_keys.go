@@ -0,0 +1,350 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// maxForeignKeyCascadeDepth is the maximum number of tables a cascading action may go through, as in MySQL. It also
+// bounds the cascades of cyclic foreign keys.
+const maxForeignKeyCascadeDepth = 15
+
+// foreignKeyCascader applies the ON DELETE and ON UPDATE actions of the foreign keys referencing a parent table, as
+// declared by the sql.ForeignKeyTable tables of its database, when rows of the parent table are deleted or updated.
+// The foreign keys of the database are read once, when the cascader is created for a statement.
+//
+// The actions are carried out with the deleters and updaters of the child tables as every parent row is written, so
+// on tables that aren't transactional a cascade isn't atomic with the write of its parent row: if it fails part way,
+// the child rows already changed stay changed, and so does the parent row if it was written before the failure.
+type foreignKeyCascader struct {
+	db     sql.Database
+	parent sql.Table
+	// the foreign keys of the database, by the lowercased name of the table they reference
+	refs map[string][]foreignKeyReference
+}
+
+// newForeignKeyCascader returns a foreignKeyCascader for the table given, or nil if no foreign key actions apply to
+// it: its database is unknown, foreign key checks are disabled for the session, the table enforces its own foreign
+// keys, or no foreign key references it.
+func newForeignKeyCascader(ctx *sql.Context, db sql.Database, parent sql.Table) (*foreignKeyCascader, error) {
+	if db == nil || parent == nil || enforcesForeignKeys(parent) {
+		return nil, nil
+	}
+	if _, val := ctx.Get("foreign_key_checks"); val != nil {
+		if enabled, err := sql.ConvertToBool(val); err == nil && !enabled {
+			return nil, nil
+		}
+	}
+
+	refs, err := databaseForeignKeyReferences(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs[strings.ToLower(parent.Name())]) == 0 {
+		return nil, nil
+	}
+	return &foreignKeyCascader{db: db, parent: parent, refs: refs}, nil
+}
+
+// enforcesForeignKeys returns whether the table given applies the actions of the foreign keys referencing it itself.
+func enforcesForeignKeys(t sql.Table) bool {
+	et, ok := t.(sql.ForeignKeyEnforcingTable)
+	return ok && et.EnforcesForeignKeys()
+}
+
+// foreignKeyReference is a foreign key of a child table referencing a parent table.
+type foreignKeyReference struct {
+	fk         sql.ForeignKeyConstraint
+	child      sql.Table
+	childCols  []int
+	parentCols []int
+}
+
+// databaseForeignKeyReferences returns the foreign keys of the tables of the database given, by the lowercased name of
+// the table they reference. Foreign keys referencing tables that don't exist are left out.
+func databaseForeignKeyReferences(ctx *sql.Context, db sql.Database) (map[string][]foreignKeyReference, error) {
+	names, err := db.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]sql.Table, len(names))
+	for _, name := range names {
+		t, ok, err := db.GetTableInsensitive(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			tables[strings.ToLower(t.Name())] = t
+		}
+	}
+
+	refs := make(map[string][]foreignKeyReference)
+	for _, t := range tables {
+		fkTable, ok := t.(sql.ForeignKeyTable)
+		if !ok {
+			continue
+		}
+
+		fks, err := fkTable.GetForeignKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, fk := range fks {
+			parentName := strings.ToLower(fk.ReferencedTable)
+			parent, ok := tables[parentName]
+			if !ok || len(fk.Columns) != len(fk.ReferencedColumns) {
+				continue
+			}
+			ref := foreignKeyReference{fk: fk, child: t}
+			for i := range fk.Columns {
+				childIdx := columnIndex(t.Schema(), fk.Columns[i])
+				parentIdx := columnIndex(parent.Schema(), fk.ReferencedColumns[i])
+				if childIdx < 0 || parentIdx < 0 {
+					return nil, sql.ErrTableColumnNotFound.New(t.Name(), fk.Columns[i])
+				}
+				ref.childCols = append(ref.childCols, childIdx)
+				ref.parentCols = append(ref.parentCols, parentIdx)
+			}
+			refs[parentName] = append(refs[parentName], ref)
+		}
+	}
+
+	return refs, nil
+}
+
+// OnDelete applies the ON DELETE action of every foreign key referencing the parent row given, which is about to be
+// deleted.
+func (c *foreignKeyCascader) OnDelete(ctx *sql.Context, row sql.Row) error {
+	return c.onDelete(ctx, c.parent, row, 0)
+}
+
+// OnUpdate applies the ON UPDATE action of every foreign key referencing the parent row given, which is about to be
+// updated. Foreign keys whose referenced columns don't change are ignored.
+func (c *foreignKeyCascader) OnUpdate(ctx *sql.Context, oldRow, newRow sql.Row) error {
+	return c.onUpdate(ctx, c.parent, oldRow, newRow, 0)
+}
+
+func (c *foreignKeyCascader) onDelete(ctx *sql.Context, parent sql.Table, row sql.Row, depth int) error {
+	if depth >= maxForeignKeyCascadeDepth {
+		return sql.ErrForeignKeyDepthExceeded.New(maxForeignKeyCascadeDepth)
+	}
+
+	for _, ref := range c.refs[strings.ToLower(parent.Name())] {
+		children, err := ref.matchingChildRows(ctx, parent.Schema(), row)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		switch ref.fk.OnDelete {
+		case sql.ForeignKeyReferenceOption_Cascade:
+			for _, childRow := range children {
+				if !enforcesForeignKeys(ref.child) {
+					if err := c.onDelete(ctx, ref.child, childRow, depth+1); err != nil {
+						return err
+					}
+				}
+				if err := ref.deleteChildRow(ctx, childRow); err != nil {
+					return err
+				}
+			}
+		case sql.ForeignKeyReferenceOption_SetNull:
+			for _, childRow := range children {
+				newChildRow := childRow.Copy()
+				for _, idx := range ref.childCols {
+					newChildRow[idx] = nil
+				}
+				if err := ref.updateChildRow(ctx, c, childRow, newChildRow, depth); err != nil {
+					return err
+				}
+			}
+		default:
+			return ref.violation(c.db)
+		}
+	}
+
+	return nil
+}
+
+func (c *foreignKeyCascader) onUpdate(ctx *sql.Context, parent sql.Table, oldRow, newRow sql.Row, depth int) error {
+	if depth >= maxForeignKeyCascadeDepth {
+		return sql.ErrForeignKeyDepthExceeded.New(maxForeignKeyCascadeDepth)
+	}
+
+	schema := parent.Schema()
+	for _, ref := range c.refs[strings.ToLower(parent.Name())] {
+		changed := false
+		for _, idx := range ref.parentCols {
+			cmp, err := schema[idx].Type.Compare(oldRow[idx], newRow[idx])
+			if err != nil {
+				return err
+			}
+			if cmp != 0 {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			continue
+		}
+
+		children, err := ref.matchingChildRows(ctx, schema, oldRow)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			continue
+		}
+
+		switch ref.fk.OnUpdate {
+		case sql.ForeignKeyReferenceOption_Cascade, sql.ForeignKeyReferenceOption_SetNull:
+			for _, childRow := range children {
+				newChildRow := childRow.Copy()
+				for i, idx := range ref.childCols {
+					if ref.fk.OnUpdate == sql.ForeignKeyReferenceOption_Cascade {
+						newChildRow[idx] = newRow[ref.parentCols[i]]
+					} else {
+						newChildRow[idx] = nil
+					}
+				}
+				if err := ref.updateChildRow(ctx, c, childRow, newChildRow, depth); err != nil {
+					return err
+				}
+			}
+		default:
+			return ref.violation(c.db)
+		}
+	}
+
+	return nil
+}
+
+// matchingChildRows returns the rows of the child table referencing the parent row given. Child rows with a NULL in
+// any of the foreign key columns reference no row.
+func (r foreignKeyReference) matchingChildRows(ctx *sql.Context, parentSchema sql.Schema, parentRow sql.Row) ([]sql.Row, error) {
+	for _, idx := range r.parentCols {
+		if parentRow[idx] == nil {
+			return nil, nil
+		}
+	}
+
+	partitions, err := r.child.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	iter := sql.NewTableRowIter(ctx, r.child, partitions)
+	defer iter.Close(ctx)
+
+	var matches []sql.Row
+	for {
+		row, err := iter.Next()
+		if err == io.EOF {
+			return matches, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		matched := true
+		for i, childIdx := range r.childCols {
+			parentIdx := r.parentCols[i]
+			if row[childIdx] == nil {
+				matched = false
+				break
+			}
+			cmp, err := parentSchema[parentIdx].Type.Compare(row[childIdx], parentRow[parentIdx])
+			if err != nil {
+				return nil, err
+			}
+			if cmp != 0 {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, r.child.Schema().StoredRow(row))
+		}
+	}
+}
+
+func (r foreignKeyReference) deleteChildRow(ctx *sql.Context, row sql.Row) error {
+	deletable, ok := r.child.(sql.DeletableTable)
+	if !ok {
+		return ErrDeleteFromNotSupported.New()
+	}
+	deleter := deletable.Deleter(ctx)
+	if err := deleter.Delete(ctx, row); err != nil {
+		_ = deleter.Close(ctx)
+		return err
+	}
+	return deleter.Close(ctx)
+}
+
+func (r foreignKeyReference) updateChildRow(ctx *sql.Context, c *foreignKeyCascader, oldRow, newRow sql.Row, depth int) error {
+	updatable, ok := r.child.(sql.UpdatableTable)
+	if !ok {
+		return ErrUpdateNotSupported.New()
+	}
+	if !enforcesForeignKeys(r.child) {
+		if err := c.onUpdate(ctx, r.child, oldRow, newRow, depth+1); err != nil {
+			return err
+		}
+	}
+	updater := updatable.Updater(ctx)
+	if err := updater.Update(ctx, oldRow, newRow); err != nil {
+		_ = updater.Close(ctx)
+		return err
+	}
+	return updater.Close(ctx)
+}
+
+func (r foreignKeyReference) violation(db sql.Database) error {
+	return sql.ErrForeignKeyChildViolation.New(db.Name(), r.child.Name(), r.fk.Name,
+		strings.Join(r.fk.Columns, ", "), r.fk.ReferencedTable, strings.Join(r.fk.ReferencedColumns, ", "))
+}
+
+func columnIndex(schema sql.Schema, name string) int {
+	for i, col := range schema {
+		if strings.EqualFold(col.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// getResolvedTableDatabase returns the database of the first table in the node given, or nil if there is none.
+func getResolvedTableDatabase(node sql.Node) sql.Database {
+	switch n := node.(type) {
+	case *ResolvedTable:
+		return n.Database
+	case *IndexedTableAccess:
+		return n.ResolvedTable.Database
+	case *PrimaryKeyLookup:
+		return n.ResolvedTable.Database
+	}
+	for _, child := range node.Children() {
+		if db := getResolvedTableDatabase(child); db != nil {
+			return db
+		}
+	}
+	return nil
+}
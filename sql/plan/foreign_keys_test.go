@@ -0,0 +1,100 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// tableNamesCounter counts the calls to GetTableNames of a database.
+type tableNamesCounter struct {
+	*memory.Database
+	calls int
+}
+
+func (d *tableNamesCounter) GetTableNames(ctx *sql.Context) ([]string, error) {
+	d.calls++
+	return d.Database.GetTableNames(ctx)
+}
+
+// foreignKeyEnforcingTable is a table applying the actions of the foreign keys referencing it itself.
+type foreignKeyEnforcingTable struct {
+	*memory.Table
+}
+
+func (foreignKeyEnforcingTable) EnforcesForeignKeys() bool {
+	return true
+}
+
+func TestForeignKeyCascader(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	parent := memory.NewTable("parent", sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "parent", PrimaryKey: true},
+	})
+	child := memory.NewTable("child", sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "child", PrimaryKey: true},
+		{Name: "parent_pk", Type: sql.Int64, Source: "child", Nullable: true},
+	})
+	require.NoError(child.CreateForeignKey(ctx, "fk", []string{"parent_pk"}, "parent", []string{"pk"},
+		sql.ForeignKeyReferenceOption_DefaultAction, sql.ForeignKeyReferenceOption_Cascade))
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(parent.Insert(ctx, sql.NewRow(i)))
+		require.NoError(child.Insert(ctx, sql.NewRow(i*10, i)))
+	}
+
+	db := &tableNamesCounter{Database: memory.NewDatabase("mydb")}
+	db.AddTable("parent", parent)
+	db.AddTable("child", child)
+
+	// the foreign keys of the database are read once for every row deleted by the statement
+	del := NewDeleteFrom(NewFilter(
+		expression.NewLessThan(
+			expression.NewGetFieldWithTable(0, sql.Int64, "parent", "pk", false),
+			expression.NewLiteral(int64(3), sql.Int64),
+		),
+		NewResolvedTable(parent, db, nil),
+	))
+	iter, err := del.RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Len(rows, 2)
+	require.Equal(1, db.calls)
+
+	iter, err = NewResolvedTable(child, db, nil).RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(30), int64(3)}}, rows)
+
+	// tables with no foreign key referencing them, and tables enforcing their own, need no cascader
+	cascader, err := newForeignKeyCascader(ctx, db, child)
+	require.NoError(err)
+	require.Nil(cascader)
+	cascader, err = newForeignKeyCascader(ctx, db, foreignKeyEnforcingTable{parent})
+	require.NoError(err)
+	require.Nil(cascader)
+	cascader, err = newForeignKeyCascader(ctx, db, parent)
+	require.NoError(err)
+	require.NotNil(cascader)
+}
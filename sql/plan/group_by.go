@@ -137,45 +137,33 @@ func (g *GroupBy) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
 }
 
 func (g *GroupBy) String() string {
-	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("GroupBy")
-
-	var selectedExprs = make([]string, len(g.SelectedExprs))
-	for i, e := range g.SelectedExprs {
-		selectedExprs[i] = e.String()
-	}
-
-	var grouping = make([]string, len(g.GroupByExprs))
-	for i, g := range g.GroupByExprs {
-		grouping[i] = g.String()
-	}
-
-	_ = pr.WriteChildren(
-		fmt.Sprintf("SelectedExprs(%s)", strings.Join(selectedExprs, ", ")),
-		fmt.Sprintf("Grouping(%s)", strings.Join(grouping, ", ")),
-		g.Child.String(),
-	)
-	return pr.String()
+	return g.treeString("GroupBy", func(v interface{}) string { return fmt.Sprint(v) })
 }
 
 func (g *GroupBy) DebugString() string {
+	return g.treeString("GroupBy", sql.DebugString)
+}
+
+// treeString returns the tree of the node, with the name given, formatting its expressions and child with the function
+// given.
+func (g *GroupBy) treeString(name string, format func(interface{}) string) string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("GroupBy")
+	_ = pr.WriteNode(name)
 
 	var selectedExprs = make([]string, len(g.SelectedExprs))
 	for i, e := range g.SelectedExprs {
-		selectedExprs[i] = sql.DebugString(e)
+		selectedExprs[i] = format(e)
 	}
 
 	var grouping = make([]string, len(g.GroupByExprs))
 	for i, g := range g.GroupByExprs {
-		grouping[i] = sql.DebugString(g)
+		grouping[i] = format(g)
 	}
 
 	_ = pr.WriteChildren(
 		fmt.Sprintf("SelectedExprs(%s)", strings.Join(selectedExprs, ", ")),
 		fmt.Sprintf("Grouping(%s)", strings.Join(grouping, ", ")),
-		sql.DebugString(g.Child),
+		format(g.Child),
 	)
 	return pr.String()
 }
@@ -188,6 +176,64 @@ func (g *GroupBy) Expressions() []sql.Expression {
 	return exprs
 }
 
+// OrderedGroupBy is a GroupBy whose child returns its rows sorted on the grouping expressions, so that the rows of
+// every group are adjacent. It returns every group as soon as the next one starts, rather than buffering all of them,
+// so its memory use doesn't grow with the number of groups. The analyzer substitutes it for a GroupBy whose child is a
+// Sort on its grouping expressions.
+type OrderedGroupBy struct {
+	GroupBy
+}
+
+// NewOrderedGroupBy creates a new OrderedGroupBy node. The child must return its rows sorted on the grouping
+// expressions given.
+func NewOrderedGroupBy(selectedExprs, groupByExprs []sql.Expression, child sql.Node) *OrderedGroupBy {
+	return &OrderedGroupBy{GroupBy: *NewGroupBy(selectedExprs, groupByExprs, child)}
+}
+
+// RowIter implements the Node interface.
+func (g *OrderedGroupBy) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	span, ctx := ctx.Span("plan.OrderedGroupBy", opentracing.Tags{
+		"groupings":  len(g.GroupByExprs),
+		"aggregates": len(g.SelectedExprs),
+	})
+
+	i, err := g.Child.RowIter(ctx, row)
+	if err != nil {
+		span.Finish()
+		return nil, err
+	}
+
+	return sql.NewSpanIter(span, newOrderedGroupByIter(ctx, g.SelectedExprs, g.GroupByExprs, i)), nil
+}
+
+// WithChildren implements the Node interface.
+func (g *OrderedGroupBy) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(g, len(children), 1)
+	}
+
+	return NewOrderedGroupBy(g.SelectedExprs, g.GroupByExprs, children[0]), nil
+}
+
+// WithExpressions implements the Node interface.
+func (g *OrderedGroupBy) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	n, err := g.GroupBy.WithExpressions(exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	gb := n.(*GroupBy)
+	return NewOrderedGroupBy(gb.SelectedExprs, gb.GroupByExprs, gb.Child), nil
+}
+
+func (g *OrderedGroupBy) String() string {
+	return g.treeString("OrderedGroupBy", func(v interface{}) string { return fmt.Sprint(v) })
+}
+
+func (g *OrderedGroupBy) DebugString() string {
+	return g.treeString("OrderedGroupBy", sql.DebugString)
+}
+
 type groupByIter struct {
 	selectedExprs []sql.Expression
 	child         sql.RowIter
@@ -334,6 +380,88 @@ func (i *groupByGroupingIter) Close(ctx *sql.Context) error {
 	return i.child.Close(ctx)
 }
 
+// orderedGroupByIter aggregates the groups of rows sorted on the grouping expressions, keeping the buffers of the
+// current group only.
+type orderedGroupByIter struct {
+	selectedExprs []sql.Expression
+	groupByExprs  []sql.Expression
+	child         sql.RowIter
+	ctx           *sql.Context
+	// the buffers and the grouping key of the current group, nil before the first row
+	buf  []sql.Row
+	key  uint64
+	done bool
+}
+
+func newOrderedGroupByIter(
+	ctx *sql.Context,
+	selectedExprs, groupByExprs []sql.Expression,
+	child sql.RowIter,
+) *orderedGroupByIter {
+	return &orderedGroupByIter{
+		selectedExprs: selectedExprs,
+		groupByExprs:  groupByExprs,
+		child:         child,
+		ctx:           ctx,
+	}
+}
+
+func (i *orderedGroupByIter) Next() (sql.Row, error) {
+	if i.done {
+		return nil, io.EOF
+	}
+
+	for {
+		row, err := i.child.Next()
+		if err == io.EOF {
+			// the last group ends with the rows
+			i.done = true
+			if i.buf == nil {
+				return nil, io.EOF
+			}
+			return evalBuffers(i.ctx, i.buf, i.selectedExprs)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := groupingKey(i.ctx, i.groupByExprs, row)
+		if err != nil {
+			return nil, err
+		}
+
+		var group sql.Row
+		if i.buf != nil && key != i.key {
+			group, err = evalBuffers(i.ctx, i.buf, i.selectedExprs)
+			if err != nil {
+				return nil, err
+			}
+			i.buf = nil
+		}
+
+		if i.buf == nil {
+			i.buf = make([]sql.Row, len(i.selectedExprs))
+			for j, a := range i.selectedExprs {
+				i.buf[j] = newAggregationBuffer(a)
+			}
+			i.key = key
+		}
+
+		if err := updateBuffers(i.ctx, i.buf, i.selectedExprs, row); err != nil {
+			return nil, err
+		}
+
+		if group != nil {
+			return group, nil
+		}
+	}
+}
+
+func (i *orderedGroupByIter) Close(ctx *sql.Context) error {
+	i.buf = nil
+	return i.child.Close(ctx)
+}
+
 func groupingKey(
 	ctx *sql.Context,
 	exprs []sql.Expression,
@@ -111,6 +111,85 @@ func TestGroupByRowIter(t *testing.T) {
 	require.Equal(sql.NewRow("col1_2", int64(4444)), rows[1])
 }
 
+func TestOrderedGroupByRowIter(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+
+	child := memory.NewTable("test", sql.Schema{
+		{Name: "col1", Type: sql.LongText, Nullable: true},
+		{Name: "col2", Type: sql.Int64, Nullable: true},
+		{Name: "col3", Type: sql.Int64},
+	})
+	rows := []sql.Row{
+		sql.NewRow("b", int64(1), int64(1)),
+		sql.NewRow(nil, int64(2), int64(2)),
+		sql.NewRow("a", nil, int64(3)),
+		sql.NewRow("b", int64(1), int64(4)),
+		sql.NewRow(nil, nil, int64(5)),
+		sql.NewRow("a", int64(3), int64(6)),
+		sql.NewRow("c", int64(3), int64(7)),
+		sql.NewRow("b", int64(2), int64(8)),
+		sql.NewRow(nil, int64(2), int64(9)),
+	}
+	for _, r := range rows {
+		require.NoError(t, child.Insert(ctx, r))
+	}
+
+	col1 := expression.NewGetField(0, sql.LongText, "col1", true)
+	col2 := expression.NewGetField(1, sql.Int64, "col2", true)
+	col3 := expression.NewGetField(2, sql.Int64, "col3", false)
+	sorted := func(fields ...sql.Expression) sql.Node {
+		sortFields := make([]sql.SortField, len(fields))
+		for i, f := range fields {
+			sortFields[i] = sql.SortField{Column: f, Order: sql.Descending}
+		}
+		return NewSort(sortFields, NewResolvedTable(child, nil, nil))
+	}
+	aggregates := func(grouping ...sql.Expression) []sql.Expression {
+		return append(grouping,
+			aggregation.NewCount(expression.NewStar()),
+			aggregation.NewSum(col3),
+			aggregation.NewMax(col3),
+			aggregation.NewFirst(col3),
+		)
+	}
+
+	testCases := []struct {
+		name     string
+		grouping []sql.Expression
+		child    sql.Node
+		groups   int
+	}{
+		{"one column", []sql.Expression{col1}, sorted(col1), 4},
+		{"one column sorted on more", []sql.Expression{col2}, sorted(col2, col3), 4},
+		{"two columns", []sql.Expression{col1, col2}, sorted(col1, col2), 7},
+		{"two columns sorted in another order", []sql.Expression{col1, col2}, sorted(col2, col1), 7},
+		{"one group", []sql.Expression{expression.NewLiteral(int64(1), sql.Int64)}, sorted(col1), 1},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			// the rows of the groups come in the same order from both nodes
+			expected, err := sql.NodeToRows(ctx, NewGroupBy(aggregates(tt.grouping...), tt.grouping, tt.child))
+			require.NoError(err)
+			require.Len(expected, tt.groups)
+
+			rows, err := sql.NodeToRows(ctx, NewOrderedGroupBy(aggregates(tt.grouping...), tt.grouping, tt.child))
+			require.NoError(err)
+			require.Equal(expected, rows)
+		})
+	}
+
+	t.Run("no rows", func(t *testing.T) {
+		require := require.New(t)
+		empty := NewResolvedTable(memory.NewTable("empty", child.Schema()), nil, nil)
+		rows, err := sql.NodeToRows(ctx, NewOrderedGroupBy(aggregates(col1), []sql.Expression{col1}, empty))
+		require.NoError(err)
+		require.Empty(rows)
+	})
+}
+
 func TestGroupByEvalEmptyBuffer(t *testing.T) {
 	require := require.New(t)
 	ctx := sql.NewEmptyContext()
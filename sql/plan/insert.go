@@ -23,7 +23,6 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
-	"github.com/dolthub/go-mysql-server/sql/expression/function"
 )
 
 // ErrInsertIntoNotSupported is thrown when a table doesn't support inserts
@@ -36,7 +35,7 @@ var ErrInsertIntoUnsupportedValues = errors.NewKind("%T is unsupported for inser
 var ErrInsertIntoDuplicateColumn = errors.NewKind("duplicate column name %v")
 var ErrInsertIntoNonexistentColumn = errors.NewKind("invalid column name %v")
 var ErrInsertIntoNonNullableDefaultNullColumn = errors.NewKind("column name '%v' is non-nullable but attempted to set default value of null")
-var ErrInsertIntoNonNullableProvidedNull = errors.NewKind("column name '%v' is non-nullable but attempted to set a value of null")
+var ErrInsertIntoNonNullableProvidedNull = sql.ErrColumnNotNullable
 var ErrInsertIntoIncompatibleTypes = errors.NewKind("cannot convert type %s to %s")
 
 // InsertInto is a node describing the insertion into some table.
@@ -100,6 +99,7 @@ type insertIter struct {
 	insertExprs         []sql.Expression
 	updateExprs         []sql.Expression
 	checks              []sql.Expression
+	warnings            *sql.WarningBuffer
 	tableNode           sql.Node
 	closed              bool
 }
@@ -179,6 +179,7 @@ func newInsertIter(
 		updateExprs: onDupUpdateExpr,
 		insertExprs: insertExpressions,
 		checks:      checks,
+		warnings:    sql.NewWarningBuffer(ctx, sql.DefaultWarningBufferSize),
 		ctx:         ctx,
 	}, nil
 }
@@ -213,7 +214,8 @@ func (i *insertIter) Next() (returnRow sql.Row, returnErr error) {
 		row = row[len(row)-len(i.schema):]
 	}
 
-	err = validateNullability(i.schema, row)
+	// Do any necessary type conversions to the target schema
+	row, err = i.schema.ValidateRow(row)
 	if err != nil {
 		_ = i.rowSource.Close(i.ctx)
 		return nil, err
@@ -227,21 +229,11 @@ func (i *insertIter) Next() (returnRow sql.Row, returnErr error) {
 			return nil, err
 		}
 		if val, ok := res.(bool); !ok || !val {
-			i.ctx.Warn(3819, "Check constraint '%s' is violated", check.String())
+			i.warnings.Warn(3819, "Check constraint '%s' is violated", check.String())
 			return nil, nil
 		}
 	}
 
-	// Do any necessary type conversions to the target schema
-	for i, col := range i.schema {
-		if row[i] != nil {
-			row[i], err = col.Type.Convert(row[i])
-			if err != nil {
-				return nil, err
-			}
-		}
-	}
-
 	if i.replacer != nil {
 		toReturn := row.Append(row)
 		if err = i.replacer.Delete(i.ctx, row); err != nil {
@@ -267,7 +259,7 @@ func (i *insertIter) Next() (returnRow sql.Row, returnErr error) {
 				return nil, err
 			}
 
-			return i.handleOnDuplicateKeyUpdate(row)
+			return i.handleOnDuplicateKeyUpdate(row, err)
 		}
 	}
 
@@ -276,21 +268,16 @@ func (i *insertIter) Next() (returnRow sql.Row, returnErr error) {
 	return row, nil
 }
 
-func (i *insertIter) handleOnDuplicateKeyUpdate(row sql.Row) (returnRow sql.Row, returnErr error) {
-	var pkExpression sql.Expression
-	for index, col := range i.schema {
-		if col.PrimaryKey {
-			value := row[index]
-			exp := expression.NewEquals(expression.NewGetField(index, col.Type, col.Name, col.Nullable), expression.NewLiteral(value, col.Type))
-			if pkExpression != nil {
-				pkExpression = expression.NewAnd(pkExpression, exp)
-			} else {
-				pkExpression = exp
-			}
-		}
+func (i *insertIter) handleOnDuplicateKeyUpdate(row sql.Row, insertErr error) (returnRow sql.Row, returnErr error) {
+	keyExpression, err := i.duplicateKeyExpression(row)
+	if err != nil {
+		return nil, err
+	}
+	if keyExpression == nil {
+		return nil, insertErr
 	}
 
-	filter := NewFilter(pkExpression, i.tableNode)
+	filter := NewFilter(keyExpression, i.tableNode)
 	filterIter, err := filter.RowIter(i.ctx, row)
 	if err != nil {
 		return nil, err
@@ -303,26 +290,36 @@ func (i *insertIter) handleOnDuplicateKeyUpdate(row sql.Row) (returnRow sql.Row,
 		}
 	}()
 
-	// By definition, there can only be a single row here. And only one row should ever be updated according to the
-	// spec:
+	// Only one row should ever be updated according to the spec, even if the inserted row conflicts with several rows
+	// on different unique keys:
 	// https://dev.mysql.com/doc/refman/8.0/en/insert-on-duplicate.html
 	rowToUpdate, err := filterIter.Next()
+	if err == io.EOF {
+		return nil, insertErr
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = i.resolveValues(i.ctx, row)
+	updateExprs, err := bindValues(i.updateExprs, row)
 	if err != nil {
 		return nil, err
 	}
 
-	newRow, err := applyUpdateExpressions(i.ctx, i.updateExprs, rowToUpdate)
+	newRow, err := applyUpdateExpressions(i.ctx, updateExprs, rowToUpdate)
 	if err != nil {
 		return nil, err
 	}
 
-	err = i.updater.Update(i.ctx, rowToUpdate, newRow)
-	if err != nil {
+	// A row that doesn't change isn't written, and counts as no affected row
+	if equals, err := rowToUpdate.Equals(newRow, i.schema); err == nil {
+		if !equals {
+			err = i.updater.Update(i.ctx, rowToUpdate, newRow)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
 		return nil, err
 	}
 
@@ -330,54 +327,102 @@ func (i *insertIter) handleOnDuplicateKeyUpdate(row sql.Row) (returnRow sql.Row,
 	return rowToUpdate.Append(newRow), nil
 }
 
-// resolveValues resolves all VALUES functions.
-func (i *insertIter) resolveValues(ctx *sql.Context, insertRow sql.Row) error {
-	for _, updateExpr := range i.updateExprs {
-		var err error
-		sql.Inspect(updateExpr, func(expr sql.Expression) bool {
-			valuesExpr, ok := expr.(*function.Values)
-			if !ok {
-				return true
+// duplicateKeyExpression returns a filter matching the rows the row given conflicts with: the row with the same primary
+// key, or any row with the same values for the columns of a unique index of the table. Unique index values with a NULL
+// never conflict. Returns nil if the table has no key the row can conflict on.
+func (i *insertIter) duplicateKeyExpression(row sql.Row) (sql.Expression, error) {
+	var keys [][]int
+	var pkCols []int
+	for index, col := range i.schema {
+		if col.PrimaryKey {
+			pkCols = append(pkCols, index)
+		}
+	}
+	if len(pkCols) > 0 {
+		keys = append(keys, pkCols)
+	}
+
+	uniqueKeys, err := i.uniqueKeyColumns()
+	if err != nil {
+		return nil, err
+	}
+	keys = append(keys, uniqueKeys...)
+
+	var keyExpression sql.Expression
+	for _, key := range keys {
+		var exp sql.Expression
+		for _, index := range key {
+			value := row[index]
+			if value == nil {
+				exp = nil
+				break
 			}
-			getField, ok := valuesExpr.Child.(*expression.GetField)
-			if !ok {
-				err = fmt.Errorf("VALUES functions may only contain column names")
-				return false
+			col := i.schema[index]
+			eq := expression.NewEquals(expression.NewGetField(index, col.Type, col.Name, col.Nullable), expression.NewLiteral(value, col.Type))
+			if exp != nil {
+				exp = expression.NewAnd(exp, eq)
+			} else {
+				exp = eq
 			}
-			valuesExpr.Value = insertRow[getField.Index()]
-			return false
-		})
-		if err != nil {
-			return err
+		}
+		if exp == nil {
+			continue
+		}
+		if keyExpression != nil {
+			keyExpression = expression.NewOr(keyExpression, exp)
+		} else {
+			keyExpression = exp
 		}
 	}
-	return nil
+
+	return keyExpression, nil
 }
 
-func (i *insertIter) Close(ctx *sql.Context) error {
-	if !i.closed {
-		i.closed = true
-		if i.inserter != nil {
-			if err := i.inserter.Close(ctx); err != nil {
-				return err
-			}
-		}
-		if i.replacer != nil {
-			if err := i.replacer.Close(ctx); err != nil {
-				return err
-			}
+// uniqueKeyColumns returns the schema indexes of the columns of each unique index of the table inserted into. Indexes
+// on expressions other than columns are ignored.
+func (i *insertIter) uniqueKeyColumns() ([][]int, error) {
+	insertable, err := GetInsertable(i.tableNode)
+	if err != nil {
+		return nil, err
+	}
+	indexed, ok := insertable.(sql.IndexedTable)
+	if !ok {
+		return nil, nil
+	}
+
+	indexes, err := indexed.GetIndexes(i.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys [][]int
+	for _, index := range indexes {
+		if !index.IsUnique() {
+			continue
 		}
-		if i.updater != nil {
-			if err := i.updater.Close(ctx); err != nil {
-				return err
+		var cols []int
+		for _, expr := range index.Expressions() {
+			// Index expressions are qualified column names, such as mytable.i
+			colIdx := columnIndex(i.schema, expr[strings.LastIndex(expr, ".")+1:])
+			if colIdx < 0 {
+				cols = nil
+				break
 			}
+			cols = append(cols, colIdx)
 		}
-		if i.rowSource != nil {
-			if err := i.rowSource.Close(ctx); err != nil {
-				return err
-			}
+		if len(cols) > 0 {
+			keys = append(keys, cols)
 		}
 	}
+	return keys, nil
+}
+
+func (i *insertIter) Close(ctx *sql.Context) error {
+	if !i.closed {
+		i.closed = true
+		i.warnings.Flush()
+		return sql.CloseAll(ctx, i.inserter, i.replacer, i.updater, i.rowSource)
+	}
 
 	return nil
 }
@@ -436,6 +481,10 @@ func toInt64(x interface{}) int64 {
 
 // RowIter implements the Node interface.
 func (p *InsertInto) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkTableReadOnly(ctx, p.Destination); err != nil {
+		return nil, err
+	}
+
 	return newInsertIter(ctx, p.Destination, p.Source, p.IsReplace, p.OnDupExprs, p.Checks, row)
 }
 
@@ -459,10 +508,11 @@ func (p *InsertInto) WithSource(src sql.Node) sql.Node {
 
 func (p InsertInto) String() string {
 	pr := sql.NewTreePrinter()
+	columnNames := sql.QuoteIdentifiers(p.ColumnNames)
 	if p.IsReplace {
-		_ = pr.WriteNode("Replace(%s)", strings.Join(p.ColumnNames, ", "))
+		_ = pr.WriteNode("Replace(%s)", strings.Join(columnNames, ", "))
 	} else {
-		_ = pr.WriteNode("Insert(%s)", strings.Join(p.ColumnNames, ", "))
+		_ = pr.WriteNode("Insert(%s)", strings.Join(columnNames, ", "))
 	}
 	_ = pr.WriteChildren(p.Destination.String(), p.Source.String())
 	return pr.String()
@@ -470,7 +520,7 @@ func (p InsertInto) String() string {
 
 func (p InsertInto) DebugString() string {
 	pr := sql.NewTreePrinter()
-	var columnNames []string
+	columnNames := sql.QuoteIdentifiers(p.ColumnNames)
 	if p.IsReplace {
 		_ = pr.WriteNode("Replace(%s)", strings.Join(columnNames, ", "))
 	} else {
@@ -480,15 +530,6 @@ func (p InsertInto) DebugString() string {
 	return pr.String()
 }
 
-func validateNullability(dstSchema sql.Schema, row sql.Row) error {
-	for i, col := range dstSchema {
-		if !col.Nullable && row[i] == nil {
-			return ErrInsertIntoNonNullableProvidedNull.New(col.Name)
-		}
-	}
-	return nil
-}
-
 func (p *InsertInto) Expressions() []sql.Expression {
 	return append(p.OnDupExprs, p.Checks...)
 }
@@ -145,6 +145,10 @@ func (l *LoadData) setParsingValues() error {
 }
 
 func (l *LoadData) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkTableReadOnly(ctx, l.Destination); err != nil {
+		return nil, err
+	}
+
 	// Start the parsing by grabbing all the config variables.
 	err := l.setParsingValues()
 	if err != nil {
@@ -0,0 +1,146 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// LockingRead is a SELECT ... FOR UPDATE or LOCK IN SHARE MODE. The rows it reads from every LockingTable are locked,
+// as done by the LockingTableAccess nodes the analyzer puts around them.
+type LockingRead struct {
+	UnaryNode
+	Type sql.LockType
+	Mode sql.LockMode
+}
+
+var _ sql.Node = (*LockingRead)(nil)
+
+// NewLockingRead creates a new LockingRead node with the lock type and mode given.
+func NewLockingRead(typ sql.LockType, mode sql.LockMode, child sql.Node) *LockingRead {
+	return &LockingRead{UnaryNode: UnaryNode{child}, Type: typ, Mode: mode}
+}
+
+// WithLock returns a copy of the node with the lock type and mode given.
+func (l *LockingRead) WithLock(typ sql.LockType, mode sql.LockMode) *LockingRead {
+	nl := *l
+	nl.Type = typ
+	nl.Mode = mode
+	return &nl
+}
+
+// RowIter implements the sql.Node interface.
+func (l *LockingRead) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	return l.Child.RowIter(ctx, row)
+}
+
+// WithChildren implements the sql.Node interface.
+func (l *LockingRead) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(l, len(children), 1)
+	}
+	return NewLockingRead(l.Type, l.Mode, children[0]), nil
+}
+
+func (l *LockingRead) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("LockingRead(%s %s)", l.Type, l.Mode)
+	_ = pr.WriteChildren(l.Child.String())
+	return pr.String()
+}
+
+func (l *LockingRead) DebugString() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("LockingRead(%s %s)", l.Type, l.Mode)
+	_ = pr.WriteChildren(sql.DebugString(l.Child))
+	return pr.String()
+}
+
+// LockingTableAccess locks the rows read from the LockingTable of its child, a ResolvedTable, IndexedTableAccess or
+// PrimaryKeyLookup, with a lock type and mode. Rows the table tells to skip are left out.
+type LockingTableAccess struct {
+	UnaryNode
+	Table sql.LockingTable
+	Type  sql.LockType
+	Mode  sql.LockMode
+}
+
+var _ sql.Node = (*LockingTableAccess)(nil)
+
+// NewLockingTableAccess creates a new LockingTableAccess node for the table read by the child given.
+func NewLockingTableAccess(table sql.LockingTable, typ sql.LockType, mode sql.LockMode, child sql.Node) *LockingTableAccess {
+	return &LockingTableAccess{UnaryNode: UnaryNode{child}, Table: table, Type: typ, Mode: mode}
+}
+
+// RowIter implements the sql.Node interface.
+func (l *LockingTableAccess) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	iter, err := l.Child.RowIter(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return &lockingIter{ctx: ctx, table: l.Table, typ: l.Type, mode: l.Mode, childIter: iter}, nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (l *LockingTableAccess) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(l, len(children), 1)
+	}
+	return NewLockingTableAccess(l.Table, l.Type, l.Mode, children[0]), nil
+}
+
+func (l *LockingTableAccess) String() string {
+	return l.describe(l.Child.String())
+}
+
+func (l *LockingTableAccess) DebugString() string {
+	return l.describe(sql.DebugString(l.Child))
+}
+
+func (l *LockingTableAccess) describe(child string) string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("LockingTableAccess(%s %s)", l.Type, l.Mode)
+	_ = pr.WriteChildren(child)
+	return pr.String()
+}
+
+type lockingIter struct {
+	ctx       *sql.Context
+	table     sql.LockingTable
+	typ       sql.LockType
+	mode      sql.LockMode
+	childIter sql.RowIter
+}
+
+func (i *lockingIter) Next() (sql.Row, error) {
+	for {
+		row, err := i.childIter.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		locked, err := i.table.LockRow(i.ctx, row, i.typ, i.mode)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return row, nil
+		}
+	}
+}
+
+func (i *lockingIter) Close(ctx *sql.Context) error {
+	return i.childIter.Close(ctx)
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// PrimaryKeyLookup reads the single row of a sql.PrimaryKeyTable with the primary key given by its key expressions,
+// one for each primary key column in schema order. If a key value can't be converted to the type of its column, it
+// falls back to scanning the table, so it must always be used under a Filter with the original condition.
+type PrimaryKeyLookup struct {
+	*ResolvedTable
+	keyExprs []sql.Expression
+}
+
+var _ sql.Node = (*PrimaryKeyLookup)(nil)
+var _ sql.Expressioner = (*PrimaryKeyLookup)(nil)
+
+// NewPrimaryKeyLookup returns a new PrimaryKeyLookup node for the table and key expressions given.
+func NewPrimaryKeyLookup(resolvedTable *ResolvedTable, keyExprs []sql.Expression) *PrimaryKeyLookup {
+	return &PrimaryKeyLookup{
+		ResolvedTable: resolvedTable,
+		keyExprs:      keyExprs,
+	}
+}
+
+// RowIter implements the sql.Node interface.
+func (p *PrimaryKeyLookup) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	table, ok := p.ResolvedTable.Table.(sql.PrimaryKeyTable)
+	if !ok {
+		return p.ResolvedTable.RowIter(ctx, row)
+	}
+
+	var pkCols []*sql.Column
+	for _, col := range table.Schema() {
+		if col.PrimaryKey {
+			pkCols = append(pkCols, col)
+		}
+	}
+	if len(pkCols) != len(p.keyExprs) {
+		return p.ResolvedTable.RowIter(ctx, row)
+	}
+
+	key := make(sql.Row, len(p.keyExprs))
+	for i, keyExpr := range p.keyExprs {
+		val, err := keyExpr.Eval(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+		// NULL never equals any key
+		if val == nil {
+			return sql.RowsToRowIter(), nil
+		}
+		key[i], err = pkCols[i].Type.Convert(val)
+		if err != nil {
+			return p.ResolvedTable.RowIter(ctx, row)
+		}
+	}
+
+	r, ok, err := table.GetRowByKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return sql.RowsToRowIter(), nil
+	}
+	return sql.RowsToRowIter(r), nil
+}
+
+func (p *PrimaryKeyLookup) String() string {
+	keyExprs := make([]string, len(p.keyExprs))
+	for i := range p.keyExprs {
+		keyExprs[i] = p.keyExprs[i].String()
+	}
+	return fmt.Sprintf("PrimaryKeyLookup(%s, key %s)", p.Name(), strings.Join(keyExprs, ", "))
+}
+
+func (p *PrimaryKeyLookup) DebugString() string {
+	keyExprs := make([]string, len(p.keyExprs))
+	for i := range p.keyExprs {
+		keyExprs[i] = sql.DebugString(p.keyExprs[i])
+	}
+	return fmt.Sprintf("PrimaryKeyLookup(%s, key %s)", p.Name(), strings.Join(keyExprs, ", "))
+}
+
+// WithChildren implements the sql.Node interface.
+func (p *PrimaryKeyLookup) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(p, len(children), 0)
+	}
+	return p, nil
+}
+
+// Expressions implements sql.Expressioner
+func (p *PrimaryKeyLookup) Expressions() []sql.Expression {
+	return p.keyExprs
+}
+
+// WithExpressions implements sql.Expressioner
+func (p *PrimaryKeyLookup) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(p.keyExprs) {
+		return nil, sql.ErrInvalidChildrenNumber.New(p, len(exprs), len(p.keyExprs))
+	}
+	return NewPrimaryKeyLookup(p.ResolvedTable, exprs), nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+func TestPrimaryKeyLookup(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+
+	table := memory.NewPartitionedTable("t", sql.Schema{
+		{Name: "a", Type: sql.Int64, Source: "t", PrimaryKey: true},
+		{Name: "b", Type: sql.Text, Source: "t", PrimaryKey: true},
+		{Name: "c", Type: sql.Int64, Source: "t", Nullable: true},
+	}, 2)
+	for _, row := range []sql.Row{
+		{int64(1), "x", int64(10)},
+		{int64(1), "y", int64(20)},
+		{int64(2), "x", nil},
+	} {
+		require.NoError(t, table.Insert(ctx, row))
+	}
+	rt := NewResolvedTable(table, nil, nil)
+
+	testCases := []struct {
+		name string
+		a, b sql.Expression
+	}{
+		{"existing key", expression.NewLiteral(int64(1), sql.Int64), expression.NewLiteral("y", sql.Text)},
+		{"key needing conversion", expression.NewLiteral(int8(2), sql.Int8), expression.NewLiteral("x", sql.Text)},
+		{"missing key", expression.NewLiteral(int64(3), sql.Int64), expression.NewLiteral("x", sql.Text)},
+		{"NULL key part", expression.NewLiteral(nil, sql.Null), expression.NewLiteral("x", sql.Text)},
+		{"unconvertible key part", expression.NewLiteral("abc", sql.Text), expression.NewLiteral("x", sql.Text)},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			cond := expression.NewAnd(
+				expression.NewEquals(expression.NewGetFieldWithTable(0, sql.Int64, "t", "a", false), tt.a),
+				expression.NewEquals(expression.NewGetFieldWithTable(1, sql.Text, "t", "b", false), tt.b),
+			)
+
+			scanned, err := sql.NodeToRows(ctx, NewFilter(cond, rt))
+			require.NoError(err)
+			lookedUp, err := sql.NodeToRows(ctx, NewFilter(cond, NewPrimaryKeyLookup(rt, []sql.Expression{tt.a, tt.b})))
+			require.NoError(err)
+
+			require.Equal(scanned, lookedUp)
+		})
+	}
+}
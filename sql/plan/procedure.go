@@ -54,6 +54,18 @@ type ProcedureParam struct {
 	Type      sql.Type                // Type is the SQL type of the parameter.
 }
 
+// ProcedureParameterInfo describes a parameter of a stored procedure, as listed by information_schema.parameters.
+type ProcedureParameterInfo struct {
+	// OrdinalPosition is the position of the parameter in the parameter list, starting at 1.
+	OrdinalPosition int
+	// Name is the name of the parameter, in lowercase.
+	Name string
+	// Direction is whether the parameter is IN, INOUT or OUT.
+	Direction ProcedureParamDirection
+	// Type is the SQL type of the parameter.
+	Type sql.Type
+}
+
 // Characteristic represents a characteristic that is defined on either a stored procedure or stored function.
 type Characteristic byte
 
@@ -76,6 +88,7 @@ type Procedure struct {
 	Comment               string
 	Characteristics       []Characteristic
 	CreateProcedureString string
+	BodyString            string
 	Body                  sql.Node
 	CreatedAt             time.Time
 	ModifiedAt            time.Time
@@ -119,6 +132,52 @@ func NewProcedure(
 	}
 }
 
+// CreateStatement returns the CREATE PROCEDURE statement of the procedure, as shown by SHOW CREATE PROCEDURE. This is
+// the original statement when it was retained, otherwise the statement is reconstructed from the definition.
+func (p *Procedure) CreateStatement() string {
+	if p.CreateProcedureString != "" {
+		return p.CreateProcedureString
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("CREATE")
+	if p.Definer != "" {
+		sb.WriteString(fmt.Sprintf(" DEFINER = `%s`", p.Definer))
+	}
+	params := make([]string, len(p.Params))
+	for i, param := range p.Params {
+		params[i] = param.String()
+	}
+	sb.WriteString(fmt.Sprintf(" PROCEDURE `%s`(%s)", p.Name, strings.Join(params, ", ")))
+	for _, characteristic := range p.Characteristics {
+		sb.WriteString(" ")
+		sb.WriteString(characteristic.String())
+	}
+	sb.WriteString(" ")
+	sb.WriteString(p.SecurityContext.String())
+	if p.Comment != "" {
+		sb.WriteString(fmt.Sprintf(" COMMENT '%s'", strings.ReplaceAll(p.Comment, "'", "''")))
+	}
+	sb.WriteString(" ")
+	sb.WriteString(p.BodyString)
+	return sb.String()
+}
+
+// ParameterInfo returns the metadata of the parameters of the procedure, in the order they're declared. Procedures
+// don't have variadic parameters or a return value, so every parameter is listed with its own position.
+func (p *Procedure) ParameterInfo() []ProcedureParameterInfo {
+	info := make([]ProcedureParameterInfo, len(p.Params))
+	for i, param := range p.Params {
+		info[i] = ProcedureParameterInfo{
+			OrdinalPosition: i + 1,
+			Name:            param.Name,
+			Direction:       param.Direction,
+			Type:            param.Type,
+		}
+	}
+	return info
+}
+
 // Resolved implements the sql.Node interface.
 func (p *Procedure) Resolved() bool {
 	return p.Body.Resolved()
@@ -155,8 +214,14 @@ func (p *Procedure) WithChildren(children ...sql.Node) (sql.Node, error) {
 	return &np, nil
 }
 
-// RowIter implements the sql.Node interface.
+// RowIter implements the sql.Node interface. The body of a SQL SECURITY DEFINER procedure runs with the privileges of
+// its definer, while the body of a SQL SECURITY INVOKER procedure runs with those of its caller, which are the definer's
+// when called from the body of a SQL SECURITY DEFINER procedure. Without a definer, the body runs with the privileges
+// of its caller either way.
 func (p *Procedure) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if p.SecurityContext == ProcedureSecurityContext_Definer && p.Definer != "" {
+		ctx = ctx.WithEffectiveUser(p.Definer)
+	}
 	return p.Body.RowIter(ctx, row)
 }
 
@@ -174,16 +239,21 @@ func (pst ProcedureSecurityContext) String() string {
 
 // String returns the original SQL representation.
 func (pp ProcedureParam) String() string {
-	direction := ""
-	switch pp.Direction {
+	return fmt.Sprintf("%s %s %s", pp.Direction.String(), pp.Name, pp.Type.String())
+}
+
+// String returns the original SQL representation, which is also the PARAMETER_MODE of information_schema.parameters.
+func (d ProcedureParamDirection) String() string {
+	switch d {
 	case ProcedureParamDirection_In:
-		direction = "IN"
+		return "IN"
 	case ProcedureParamDirection_Inout:
-		direction = "INOUT"
+		return "INOUT"
 	case ProcedureParamDirection_Out:
-		direction = "OUT"
+		return "OUT"
+	default:
+		return ""
 	}
-	return fmt.Sprintf("%s %s %s", direction, pp.Name, pp.Type.String())
 }
 
 // String returns the original SQL representation.
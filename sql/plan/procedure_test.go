@@ -0,0 +1,54 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+func TestProcedureParameterInfo(t *testing.T) {
+	require := require.New(t)
+
+	params := []ProcedureParam{
+		{Direction: ProcedureParamDirection_In, Name: "Id", Type: sql.Int64},
+		{Direction: ProcedureParamDirection_Out, Name: "total", Type: sql.Float64},
+		{Direction: ProcedureParamDirection_Inout, Name: "counter", Type: sql.Int32},
+		{Direction: ProcedureParamDirection_In, Name: "label", Type: sql.LongText},
+	}
+	proc := NewProcedure("p1", "", params, ProcedureSecurityContext_Definer, "", nil, "", NewBlock(nil),
+		time.Unix(0, 0), time.Unix(0, 0))
+
+	require.Equal([]ProcedureParameterInfo{
+		{OrdinalPosition: 1, Name: "id", Direction: ProcedureParamDirection_In, Type: sql.Int64},
+		{OrdinalPosition: 2, Name: "total", Direction: ProcedureParamDirection_Out, Type: sql.Float64},
+		{OrdinalPosition: 3, Name: "counter", Direction: ProcedureParamDirection_Inout, Type: sql.Int32},
+		{OrdinalPosition: 4, Name: "label", Direction: ProcedureParamDirection_In, Type: sql.LongText},
+	}, proc.ParameterInfo())
+
+	var modes []string
+	for _, info := range proc.ParameterInfo() {
+		modes = append(modes, info.Direction.String())
+	}
+	require.Equal([]string{"IN", "OUT", "INOUT", "IN"}, modes)
+
+	noParams := NewProcedure("p2", "", nil, ProcedureSecurityContext_Definer, "", nil, "", NewBlock(nil),
+		time.Unix(0, 0), time.Unix(0, 0))
+	require.Empty(noParams.ParameterInfo())
+}
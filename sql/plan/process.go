@@ -294,9 +294,24 @@ func (i *trackedRowIter) Next() (sql.Row, error) {
 	return row, nil
 }
 
+// ResultSets implements the ResultSetsRowIter interface.
+func (i *trackedRowIter) ResultSets() []ResultSet {
+	if rsIter, ok := i.iter.(ResultSetsRowIter); ok {
+		return rsIter.ResultSets()
+	}
+	return nil
+}
+
 func (i *trackedRowIter) Close(ctx *sql.Context) error {
 	err := i.iter.Close(ctx)
 
+	// Only the iterator of the query process itself has a node, and it's the one that owns the deferred cleanups
+	if i.node != nil {
+		if deferErr := ctx.RunDeferred(); err == nil {
+			err = deferErr
+		}
+	}
+
 	i.updateSessionVars(ctx)
 
 	i.done()
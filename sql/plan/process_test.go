@@ -65,6 +65,33 @@ func TestQueryProcess(t *testing.T) {
 	require.Equal(1, notifications)
 }
 
+func TestQueryProcessRunsDeferred(t *testing.T) {
+	require := require.New(t)
+
+	table := memory.NewTable("foo", sql.Schema{
+		{Name: "a", Type: sql.Int64},
+	})
+	table.Insert(sql.NewEmptyContext(), sql.NewRow(int64(1)))
+
+	node := NewQueryProcess(NewResolvedTable(table, nil, nil), func() {})
+
+	ctx := sql.NewEmptyContext()
+	var cleanups int
+	for i := 0; i < 3; i++ {
+		ctx.Defer(func() error {
+			cleanups++
+			return nil
+		})
+	}
+
+	iter, err := node.RowIter(ctx, nil)
+	require.NoError(err)
+
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal(3, cleanups)
+}
+
 func TestProcessTable(t *testing.T) {
 	require := require.New(t)
 
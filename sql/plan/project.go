@@ -66,6 +66,11 @@ func (p *Project) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 		return nil, err
 	}
 
+	// The projections of each row are memoized apart from those of any other node, such as a Project in a subquery
+	if ctx.RowExpressionCache() != nil {
+		ctx = ctx.WithRowExpressionCache(sql.NewRowExpressionCache())
+	}
+
 	return sql.NewSpanIter(span, &iter{
 		p:         p,
 		childIter: i,
@@ -132,6 +137,9 @@ func (i *iter) Next() (sql.Row, error) {
 		return nil, err
 	}
 
+	if cache := i.ctx.RowExpressionCache(); cache != nil {
+		cache.Reset()
+	}
 	return ProjectRow(i.ctx, i.p.Projections, childRow)
 }
 
@@ -15,6 +15,7 @@
 package plan
 
 import (
+	"context"
 	"io"
 	"testing"
 
@@ -73,6 +74,55 @@ func TestProject(t *testing.T) {
 	require.Equal(schema, p.Schema())
 }
 
+// countingGetField is a GetField counting how many times it's evaluated.
+type countingGetField struct {
+	*expression.GetField
+	evals int
+}
+
+func (e *countingGetField) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	e.evals++
+	return e.GetField.Eval(ctx, row)
+}
+
+func (e *countingGetField) WithChildren(...sql.Expression) (sql.Expression, error) {
+	return e, nil
+}
+
+func TestProjectMemoized(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewContext(context.Background(), sql.WithRowExpressionCache(sql.NewRowExpressionCache()))
+	child := memory.NewTable("test", sql.Schema{
+		{Name: "i", Type: sql.Int64, Source: "test"},
+	})
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(child.Insert(ctx, sql.NewRow(i)))
+	}
+
+	counting := &countingGetField{GetField: expression.NewGetField(0, sql.Int64, "i", false)}
+	memoized := expression.NewMemoized(counting)
+	p := NewProject([]sql.Expression{
+		memoized,
+		expression.NewArithmetic(memoized, expression.NewLiteral(int64(10), sql.Int64), "+"),
+	}, NewResolvedTable(child, nil, nil))
+
+	iter, err := p.RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(11)}, {int64(2), int64(12)}, {int64(3), int64(13)}}, rows)
+	require.Equal(3, counting.evals)
+
+	// without a cache, memoized expressions are evaluated every time
+	counting.evals = 0
+	iter, err = p.RowIter(sql.NewEmptyContext(), nil)
+	require.NoError(err)
+	rows, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Len(rows, 3)
+	require.Equal(6, counting.evals)
+}
+
 func BenchmarkProject(b *testing.B) {
 	require := require.New(b)
 	ctx := sql.NewEmptyContext()
@@ -101,3 +151,34 @@ func BenchmarkProject(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkProjectMemoized(b *testing.B) {
+	// an expression referenced in three projections, as in SELECT x, x * 2, x + 1 FROM t
+	var expr sql.Expression = expression.NewGetField(3, sql.Int32, "intfield", false)
+	for i := 0; i < 5; i++ {
+		expr = expression.NewArithmetic(expr, expression.NewLiteral(int64(i), sql.Int64), "+")
+	}
+
+	bench := func(b *testing.B, ctx *sql.Context, expr sql.Expression) {
+		require := require.New(b)
+		p := NewProject([]sql.Expression{
+			expr,
+			expression.NewArithmetic(expr, expression.NewLiteral(int64(2), sql.Int64), "*"),
+			expression.NewArithmetic(expr, expression.NewLiteral(int64(1), sql.Int64), "+"),
+		}, NewResolvedTable(benchtable, nil, nil))
+		for i := 0; i < b.N; i++ {
+			iter, err := p.RowIter(ctx, nil)
+			require.NoError(err)
+			_, err = sql.RowIterToRows(ctx, iter)
+			require.NoError(err)
+		}
+	}
+
+	b.Run("plain", func(b *testing.B) {
+		bench(b, sql.NewEmptyContext(), expr)
+	})
+	b.Run("memoized", func(b *testing.B) {
+		ctx := sql.NewContext(context.Background(), sql.WithRowExpressionCache(sql.NewRowExpressionCache()))
+		bench(b, ctx, expression.NewMemoized(expr))
+	})
+}
@@ -40,7 +40,7 @@ func (*ResolvedTable) Resolved() bool {
 }
 
 func (t *ResolvedTable) String() string {
-	return fmt.Sprintf("Table(%s)", t.Table.Name())
+	return fmt.Sprintf("Table(%s)", sql.QuoteIdentifier(t.Table.Name()))
 }
 
 func (t *ResolvedTable) DebugString() string {
@@ -193,6 +193,11 @@ type accumulatorIter struct {
 	iter             sql.RowIter
 	once             sync.Once
 	updateRowHandler accumulatorRowHandler
+	// the table updated, whose affected rows are recorded in the changes of the transaction
+	table *ResolvedTable
+	// the binlog event of the statement, if it's logged
+	binlog     *sql.BinlogEvent
+	updateType RowUpdateType
 }
 
 func (a *accumulatorIter) Next() (sql.Row, error) {
@@ -219,7 +224,60 @@ func (a *accumulatorIter) Next() (sql.Row, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		err = a.recordBinlogRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// recordBinlogRow adds the row given, as returned by the child of the accumulator, to the rows of the binlog event of
+// the statement. Updated rows that didn't change are left out.
+func (a *accumulatorIter) recordBinlogRow(row sql.Row) error {
+	if a.binlog == nil || a.binlog.Format != sql.BinlogFormatRow || a.table == nil {
+		return nil
+	}
+
+	var before, after sql.Row
+	switch a.updateType {
+	case UpdateTypeInsert:
+		after = row
+	case UpdateTypeDelete:
+		before = row
+	case UpdateTypeReplace:
+		// the deleted row is all NULLs if the new row didn't replace any
+		before, after = row[:len(row)/2], row[len(row)/2:]
+		if isNullRow(before) {
+			before = nil
+		}
+	case UpdateTypeDuplicateKeyUpdate, UpdateTypeUpdate:
+		schema := a.table.Schema()
+		if len(row) == len(schema) {
+			after = row
+			break
+		}
+		before, after = row[:len(row)/2], row[len(row)/2:]
+		equals, err := before.Equals(after, schema)
+		if err != nil {
+			return err
+		}
+		if equals {
+			return nil
+		}
 	}
+
+	a.binlog.RecordRow(updatedTableDatabase(a.table), a.table.Name(), before, after)
+	return nil
+}
+
+func isNullRow(row sql.Row) bool {
+	for _, v := range row {
+		if v != nil {
+			return false
+		}
+	}
+	return true
 }
 
 func (a *accumulatorIter) Close(ctx *sql.Context) error {
@@ -230,9 +288,23 @@ func (a *accumulatorIter) Close(ctx *sql.Context) error {
 
 	result := a.updateRowHandler.okResult()
 	ctx.SetLastQueryInfo(sql.RowCount, int64(result.RowsAffected))
+	if a.table != nil && a.table.Database != nil {
+		ctx.PendingChanges().Record(a.table.Database.Name(), a.table.Name(), int(result.RowsAffected))
+	}
+	if a.table != nil && a.binlog != nil && result.RowsAffected > 0 {
+		a.binlog.RecordTable(updatedTableDatabase(a.table), a.table.Name(), int(result.RowsAffected))
+	}
 	return nil
 }
 
+// updatedTableDatabase returns the name of the database of the table given, or an empty string if it has none.
+func updatedTableDatabase(table *ResolvedTable) string {
+	if table.Database == nil {
+		return ""
+	}
+	return table.Database.Name()
+}
+
 func (r RowUpdateAccumulator) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	rowIter, err := r.Child.RowIter(ctx, row)
 	if err != nil {
@@ -261,5 +333,26 @@ func (r RowUpdateAccumulator) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIte
 	return &accumulatorIter{
 		iter:             rowIter,
 		updateRowHandler: rowHandler,
+		table:            getUpdatedTable(r.Child),
+		binlog:           ctx.BinlogEvent(),
+		updateType:       r.RowUpdateType,
 	}, nil
 }
+
+// getUpdatedTable returns the first table in the node given, which is the table a node updating rows writes to.
+func getUpdatedTable(node sql.Node) *ResolvedTable {
+	switch n := node.(type) {
+	case *ResolvedTable:
+		return n
+	case *IndexedTableAccess:
+		return n.ResolvedTable
+	case *PrimaryKeyLookup:
+		return n.ResolvedTable
+	}
+	for _, child := range node.Children() {
+		if rt := getUpdatedTable(child); rt != nil {
+			return rt
+		}
+	}
+	return nil
+}
@@ -25,6 +25,8 @@ import (
 // Set represents a set statement. This can be variables, but in some instances can also refer to row values.
 type Set struct {
 	Exprs []sql.Expression
+	// Catalog holds the storage engines default_storage_engine may be set to.
+	Catalog *sql.Catalog
 }
 
 // NewSet creates a new Set node.
@@ -60,7 +62,9 @@ func (s *Set) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
 		return nil, sql.ErrInvalidChildrenNumber.New(s, len(exprs), len(s.Exprs))
 	}
 
-	return NewSet(exprs), nil
+	nc := *s
+	nc.Exprs = exprs
+	return &nc, nil
 }
 
 // Expressions implements the sql.Expressioner interface.
@@ -82,7 +86,7 @@ func (s *Set) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 
 		switch left := setField.Left.(type) {
 		case *expression.SystemVar:
-			_, err := setSystemVar(ctx, left, setField.Right, row)
+			_, err := setSystemVar(ctx, s.Catalog, left, setField.Right, row)
 			if err != nil {
 				return nil, err
 			}
@@ -142,7 +146,7 @@ func setUserVar(ctx *sql.Context, userVar *expression.UserVar, right sql.Express
 	return value, nil
 }
 
-func setSystemVar(ctx *sql.Context, sysVar *expression.SystemVar, right sql.Expression, row sql.Row) (interface{}, error) {
+func setSystemVar(ctx *sql.Context, catalog *sql.Catalog, sysVar *expression.SystemVar, right sql.Expression, row sql.Row) (interface{}, error) {
 	var (
 		value interface{}
 		typ   sql.Type
@@ -151,6 +155,10 @@ func setSystemVar(ctx *sql.Context, sysVar *expression.SystemVar, right sql.Expr
 
 	var varName = sysVar.Name
 
+	if sql.IsGlobalOnlySystemVariable(varName) && sysVar.Scope != sql.SystemVariableScope_Global {
+		return nil, sql.ErrGlobalOnlySystemVariable.New(varName)
+	}
+
 	// TODO: value checking for system variables. Each one has specific lists of acceptable values.
 	value, err = right.Eval(ctx, row)
 	if err != nil {
@@ -158,6 +166,33 @@ func setSystemVar(ctx *sql.Context, sysVar *expression.SystemVar, right sql.Expr
 	}
 	typ = sysVar.Type()
 
+	if strings.EqualFold(varName, sql.TimeZoneSessionVar) {
+		if _, err = sql.ParseTimeZone(fmt.Sprint(value)); err != nil {
+			return nil, err
+		}
+	}
+	if strings.EqualFold(varName, sql.OptimizerSwitchSessionVar) {
+		// flags the value doesn't list keep their current value
+		_, current := ctx.Get(sql.OptimizerSwitchSessionVar)
+		if value, err = sql.MergeOptimizerSwitch(fmt.Sprint(current), fmt.Sprint(value)); err != nil {
+			return nil, err
+		}
+	}
+	if strings.EqualFold(varName, sql.DefaultStorageEngineSessionVar) {
+		if _, ok := sql.EngineByName(storageEngines(catalog), fmt.Sprint(value)); !ok {
+			return nil, sql.ErrUnknownStorageEngine.New(value)
+		}
+	}
+
+	// Variables already set keep their type, so later reads get a value of the type they expect
+	if typ != sql.Null {
+		converted, err := sql.TypedValue{Typ: right.Type(), Value: value}.Convert(typ)
+		if err != nil {
+			return nil, err
+		}
+		value = converted.Value
+	}
+
 	// TODO: differentiate between system and user vars here
 	err = ctx.Set(ctx, varName, typ, value)
 	if err != nil {
@@ -47,3 +47,30 @@ func TestSet(t *testing.T) {
 	require.Equal(sql.Int64, typ)
 	require.Equal(int64(1), v)
 }
+
+func TestSetTimeZone(t *testing.T) {
+	require := require.New(t)
+
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+
+	setTimeZone := func(tz string) error {
+		s := NewSet([]sql.Expression{
+			expression.NewSetField(expression.NewSystemVar("time_zone", sql.LongText), expression.NewLiteral(tz, sql.LongText)),
+		})
+		_, err := s.RowIter(ctx, nil)
+		return err
+	}
+
+	require.NoError(setTimeZone("+05:30"))
+	loc, err := ctx.TimeZone()
+	require.NoError(err)
+	_, offset := ctx.QueryTime().In(loc).Zone()
+	require.Equal(5*3600+30*60, offset)
+
+	err = setTimeZone("Not/A_Zone")
+	require.True(sql.ErrUnknownTimeZone.Is(err))
+
+	// The previous, valid, time zone is kept
+	_, v := ctx.Get("time_zone")
+	require.Equal("+05:30", v)
+}
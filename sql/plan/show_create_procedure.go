@@ -0,0 +1,109 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// ShowCreateProcedure returns the CREATE PROCEDURE statement of a stored procedure. The procedure is filled in by the
+// analyzer from its procedure cache.
+type ShowCreateProcedure struct {
+	db            sql.Database
+	ProcedureName string
+	Procedure     *Procedure
+}
+
+var _ sql.Databaser = (*ShowCreateProcedure)(nil)
+var _ sql.Node = (*ShowCreateProcedure)(nil)
+
+var showCreateProcedureSchema = sql.Schema{
+	&sql.Column{Name: "Procedure", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "sql_mode", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "Create Procedure", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "character_set_client", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "collation_connection", Type: sql.LongText, Nullable: false},
+	&sql.Column{Name: "Database Collation", Type: sql.LongText, Nullable: false},
+}
+
+// NewShowCreateProcedure creates a new *ShowCreateProcedure node.
+func NewShowCreateProcedure(db sql.Database, procedureName string) *ShowCreateProcedure {
+	return &ShowCreateProcedure{
+		db:            db,
+		ProcedureName: procedureName,
+	}
+}
+
+// String implements the sql.Node interface.
+func (s *ShowCreateProcedure) String() string {
+	return fmt.Sprintf("SHOW CREATE PROCEDURE %s", s.ProcedureName)
+}
+
+// Resolved implements the sql.Node interface.
+func (s *ShowCreateProcedure) Resolved() bool {
+	_, ok := s.db.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the sql.Node interface.
+func (s *ShowCreateProcedure) Children() []sql.Node {
+	return nil
+}
+
+// Schema implements the sql.Node interface.
+func (s *ShowCreateProcedure) Schema() sql.Schema {
+	return showCreateProcedureSchema
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowCreateProcedure) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if s.Procedure == nil {
+		return nil, sql.ErrStoredProcedureDoesNotExist.New(s.ProcedureName)
+	}
+
+	_, sqlMode := ctx.Get("sql_mode")
+	if sqlMode == nil {
+		sqlMode = ""
+	}
+	_, characterSetClient := ctx.Get("character_set_client")
+	_, collationConnection := ctx.Get("collation_connection")
+	return sql.RowsToRowIter(sql.Row{
+		s.Procedure.Name,               // Procedure
+		sqlMode,                        // sql_mode
+		s.Procedure.CreateStatement(),  // Create Procedure
+		characterSetClient,             // character_set_client
+		collationConnection,            // collation_connection
+		sql.Collation_Default.String(), // Database Collation
+	}), nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (s *ShowCreateProcedure) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(s, children...)
+}
+
+// Database implements the sql.Databaser interface.
+func (s *ShowCreateProcedure) Database() sql.Database {
+	return s.db
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (s *ShowCreateProcedure) WithDatabase(db sql.Database) (sql.Node, error) {
+	ns := *s
+	ns.db = db
+	return &ns, nil
+}
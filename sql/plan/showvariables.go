@@ -16,7 +16,6 @@ package plan
 
 import (
 	"fmt"
-	"sort"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
@@ -70,7 +69,8 @@ func (*ShowVariables) Schema() sql.Schema {
 func (*ShowVariables) Children() []sql.Node { return nil }
 
 // RowIter implements the sql.Node interface.
-// The function returns an iterator for filtered variables (based on like pattern)
+// The function returns an iterator for filtered variables (based on like pattern), in the order of
+// sql.SortedVariables for the ones of the session.
 func (sv *ShowVariables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	var (
 		rows []sql.Row
@@ -83,9 +83,9 @@ func (sv *ShowVariables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, er
 		)
 	}
 
-	for k, v := range ctx.GetAll() {
+	for _, v := range sql.AllSystemVariables(ctx.Session) {
 		if like != nil {
-			b, err := like.Eval(ctx, sql.NewRow(k, sv.pattern))
+			b, err := like.Eval(ctx, sql.NewRow(v.Name, sv.pattern))
 			if err != nil {
 				return nil, err
 			}
@@ -94,12 +94,8 @@ func (sv *ShowVariables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, er
 			}
 		}
 
-		rows = append(rows, sql.NewRow(k, v.Value))
+		rows = append(rows, sql.NewRow(v.Name, v.Value))
 	}
 
-	sort.Slice(rows, func(i, j int) bool {
-		return rows[i][0].(string) < rows[j][0].(string)
-	})
-
 	return sql.RowsToRowIter(rows...), nil
 }
@@ -35,9 +35,12 @@ func TestShowVariables(t *testing.T) {
 	require.NoError(err)
 
 	vars := ctx.GetAll()
+	var last string
 	for row, err := it.Next(); err == nil; row, err = it.Next() {
 		key := row[0].(string)
 		val := row[1]
+		require.True(last < key, "%s is listed after %s", key, last)
+		last = key
 
 		t.Logf("key: %s\tval: %v\n", key, val)
 
@@ -56,20 +59,26 @@ func TestShowVariablesWithLike(t *testing.T) {
 	require.True(t, sv.Resolved())
 
 	context := sql.NewEmptyContext()
+	context.Set(context, "int3", sql.Int32, 3)
 	context.Set(context, "int1", sql.Int32, 1)
 	context.Set(context, "int2", sql.Int32, 2)
 	context.Set(context, "txt", sql.LongText, "abcdefghijklmnoprstuwxyz")
 
-	it, err := sv.RowIter(context, nil)
-	require.NoError(t, err)
-
-	rows, err := sql.RowIterToRows(context, it)
-	require.NoError(t, err)
-
 	expectedRows := []sql.Row{
 		{"int1", 1},
 		{"int2", 2},
+		{"int3", 3},
+		{"interactive_timeout", int64(28800)},
 	}
 
-	assert.Equal(t, expectedRows, rows)
+	// the filtered variables are sorted by name on every call
+	for i := 0; i < 10; i++ {
+		it, err := sv.RowIter(context, nil)
+		require.NoError(t, err)
+
+		rows, err := sql.RowIterToRows(context, it)
+		require.NoError(t, err)
+
+		assert.Equal(t, expectedRows, rows)
+	}
 }
@@ -40,13 +40,9 @@ func (ShowWarnings) String() string {
 	return "SHOW WARNINGS"
 }
 
-// Schema returns a new Schema reference for "SHOW VARIABLES" query.
+// Schema returns a new Schema reference for "SHOW WARNINGS" query.
 func (ShowWarnings) Schema() sql.Schema {
-	return sql.Schema{
-		&sql.Column{Name: "Level", Type: sql.LongText, Nullable: false},
-		&sql.Column{Name: "Code", Type: sql.Int32, Nullable: true},
-		&sql.Column{Name: "Message", Type: sql.LongText, Nullable: false},
-	}
+	return sql.WarningsSchema
 }
 
 // Children implements sql.Node interface. The function always returns nil.
@@ -55,10 +51,6 @@ func (ShowWarnings) Children() []sql.Node { return nil }
 // RowIter implements the sql.Node interface.
 // The function returns an iterator for warnings (considering offset and counter)
 func (sw ShowWarnings) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
-	var rows []sql.Row
-	for _, w := range sw {
-		rows = append(rows, sql.NewRow(w.Level, w.Code, w.Message))
-	}
-
+	rows, _ := sql.WarningsToRows(sw)
 	return sql.RowsToRowIter(rows...), nil
 }
@@ -40,12 +40,12 @@ func TestShowWarnings(t *testing.T) {
 	n := 3
 	for row, err := it.Next(); err == nil; row, err = it.Next() {
 		level := row[0].(string)
-		code := row[1].(int)
+		code := row[1].(uint32)
 		message := row[2].(string)
 
 		t.Logf("level: %s\tcode: %v\tmessage: %s\n", level, code, message)
 
-		require.Equal(n, code)
+		require.Equal(uint32(n), code)
 		n--
 	}
 	if err != io.EOF {
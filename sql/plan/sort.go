@@ -126,6 +126,10 @@ type sortIter struct {
 	childIter  sql.RowIter
 	sortedRows []sql.Row
 	idx        int
+	// spillFiles are the files sorted runs were spilled to when the rows didn't fit in memory.
+	spillFiles []string
+	runs       []*spillFileIter
+	runHeads   []sql.Row
 }
 
 func newSortIter(ctx *sql.Context, s *Sort, child sql.RowIter) *sortIter {
@@ -146,6 +150,10 @@ func (i *sortIter) Next() (sql.Row, error) {
 		i.idx = 0
 	}
 
+	if i.runs != nil {
+		return i.nextMerged()
+	}
+
 	if i.idx >= len(i.sortedRows) {
 		return nil, io.EOF
 	}
@@ -156,12 +164,23 @@ func (i *sortIter) Next() (sql.Row, error) {
 
 func (i *sortIter) Close(ctx *sql.Context) error {
 	i.sortedRows = nil
-	return i.childIter.Close(ctx)
+	for _, run := range i.runs {
+		run.Close()
+	}
+	i.runs = nil
+	err := removeSpillFiles(ctx, i.spillFiles)
+	i.spillFiles = nil
+	if closeErr := i.childIter.Close(ctx); closeErr != nil {
+		return closeErr
+	}
+	return err
 }
 
 func (i *sortIter) computeSortedRows() error {
 	cache, dispose := i.ctx.Memory.NewRowsCache()
-	defer dispose()
+	defer func() {
+		dispose()
+	}()
 
 	for {
 		row, err := i.childIter.Next()
@@ -174,11 +193,38 @@ func (i *sortIter) computeSortedRows() error {
 		}
 
 		if err := cache.Add(row); err != nil {
-			return err
+			if !sql.ErrNoMemoryAvailable.Is(err) {
+				return err
+			}
+
+			// The rows don't fit in memory, so the ones read so far are sorted and spilled to a file, to be merged
+			// with the rest once the child is exhausted.
+			if err := i.spill(append(cache.Get(), row)); err != nil {
+				return err
+			}
+			dispose()
+			cache, dispose = i.ctx.Memory.NewRowsCache()
 		}
 	}
 
 	rows := cache.Get()
+	if len(i.spillFiles) > 0 {
+		if len(rows) > 0 {
+			if err := i.spill(rows); err != nil {
+				return err
+			}
+		}
+		return i.openRuns()
+	}
+
+	if err := i.sortRows(rows); err != nil {
+		return err
+	}
+	i.sortedRows = rows
+	return nil
+}
+
+func (i *sortIter) sortRows(rows []sql.Row) error {
 	sorter := &expression.Sorter{
 		SortFields: i.s.SortFields,
 		Rows:       rows,
@@ -186,9 +232,83 @@ func (i *sortIter) computeSortedRows() error {
 		Ctx:        i.ctx,
 	}
 	sort.Stable(sorter)
-	if sorter.LastError != nil {
-		return sorter.LastError
+	return sorter.LastError
+}
+
+// spill sorts the rows given and writes them to a new spill file.
+func (i *sortIter) spill(rows []sql.Row) error {
+	if err := i.sortRows(rows); err != nil {
+		return err
 	}
-	i.sortedRows = rows
+	name, err := spillRows(i.ctx, "sort", rows)
+	if err != nil {
+		return err
+	}
+	i.spillFiles = append(i.spillFiles, name)
 	return nil
 }
+
+// openRuns opens every spill file and reads the first row of each of them for the merge.
+func (i *sortIter) openRuns() error {
+	i.runs = make([]*spillFileIter, 0, len(i.spillFiles))
+	i.runHeads = make([]sql.Row, len(i.spillFiles))
+	for j, name := range i.spillFiles {
+		run, err := openSpillFile(i.ctx, name)
+		if err != nil {
+			return err
+		}
+		i.runs = append(i.runs, run)
+		if err := i.advanceRun(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *sortIter) advanceRun(j int) error {
+	row, err := i.runs[j].Next()
+	if err == io.EOF {
+		i.runHeads[j] = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	i.runHeads[j] = row
+	return nil
+}
+
+// nextMerged returns the smallest of the first rows of the spilled runs. Ties go to the earliest run, which keeps the
+// sort stable.
+func (i *sortIter) nextMerged() (sql.Row, error) {
+	sorter := &expression.Sorter{
+		SortFields: i.s.SortFields,
+		Rows:       make([]sql.Row, 2),
+		Ctx:        i.ctx,
+	}
+	min := -1
+	for j, head := range i.runHeads {
+		if head == nil {
+			continue
+		}
+		if min >= 0 {
+			sorter.Rows[0], sorter.Rows[1] = head, i.runHeads[min]
+			if !sorter.Less(0, 1) {
+				if sorter.LastError != nil {
+					return nil, sorter.LastError
+				}
+				continue
+			}
+		}
+		min = j
+	}
+	if min < 0 {
+		return nil, io.EOF
+	}
+
+	row := i.runHeads[min]
+	if err := i.advanceRun(min); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
@@ -15,6 +15,7 @@
 package plan
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -48,9 +49,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow(nil, int32(1), nil),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsLast},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow("c", nil, nil),
@@ -66,9 +67,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow("c", int32(3), nil),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsLast},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow("c", int32(3), nil),
@@ -84,8 +85,8 @@ func TestSort(t *testing.T) {
 				sql.NewRow(nil, int32(1), nil),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsLast},
 			},
 			expected: []sql.Row{
@@ -102,9 +103,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow("a", int32(1), 1),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow("a", int32(1), 1),
@@ -121,9 +122,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow("c", int32(3), 1),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Descending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow("a", int32(3), 1),
@@ -140,9 +141,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow(nil, nil, 1),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow(nil, nil, 1),
@@ -155,9 +156,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow(nil, nil, 2),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Descending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Descending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow(nil, nil, 2),
@@ -170,9 +171,9 @@ func TestSort(t *testing.T) {
 				sql.NewRow(nil, nil, nil),
 			},
 			sortFields: []sql.SortField{
-				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
-				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+				{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(1, sql.Int32, "col2", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+				{Column: expression.NewGetField(2, sql.Float64, "col3", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 			},
 			expected: []sql.Row{
 				sql.NewRow(nil, nil, nil),
@@ -237,7 +238,7 @@ func TestSortAscending(t *testing.T) {
 	}
 
 	sf := []sql.SortField{
-		{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+		{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
 	}
 	s := NewSort(sf, NewResolvedTable(child, nil, nil))
 	require.Equal(schema, s.Schema())
@@ -255,6 +256,95 @@ func TestSortAscending(t *testing.T) {
 	require.Equal(expected, actual)
 }
 
+func TestSortSpill(t *testing.T) {
+	require := require.New(t)
+	store := sql.NewMemorySpillStore()
+	ctx := sql.NewContext(context.TODO(),
+		sql.WithMemoryManager(sql.NewMemoryManager(mockReporter{2, 1})),
+		sql.WithSpillStore(store),
+	)
+
+	schema := sql.Schema{
+		{Name: "col1", Type: sql.Int64, Nullable: true},
+		{Name: "col2", Type: sql.Text, Nullable: true},
+	}
+	child := memory.NewTable("test", schema)
+	for _, row := range []sql.Row{
+		sql.NewRow(int64(3), "a"),
+		sql.NewRow(int64(1), "b"),
+		sql.NewRow(nil, "c"),
+		sql.NewRow(int64(3), "d"),
+		sql.NewRow(int64(2), "e"),
+	} {
+		require.NoError(child.Insert(sql.NewEmptyContext(), row))
+	}
+
+	sf := []sql.SortField{
+		{Column: expression.NewGetField(0, sql.Int64, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+	}
+	iter, err := NewSort(sf, NewResolvedTable(child, nil, nil)).RowIter(ctx, nil)
+	require.NoError(err)
+
+	// No memory is ever available, so every row is spilled to its own run and the runs are merged
+	actual, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{
+		sql.NewRow(nil, "c"),
+		sql.NewRow(int64(1), "b"),
+		sql.NewRow(int64(2), "e"),
+		sql.NewRow(int64(3), "a"),
+		sql.NewRow(int64(3), "d"),
+	}, actual)
+	require.Empty(store.Files())
+
+	// Cleaning up after the iterator was closed is fine
+	require.NoError(ctx.RunDeferred())
+}
+
+func TestSortSpillCleanupOnError(t *testing.T) {
+	require := require.New(t)
+	store := sql.NewMemorySpillStore()
+	ctx := sql.NewContext(context.TODO(),
+		sql.WithMemoryManager(sql.NewMemoryManager(mockReporter{2, 1})),
+		sql.WithSpillStore(store),
+	)
+
+	sf := []sql.SortField{
+		{Column: expression.NewGetField(0, sql.Int64, "col1", true), Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+	}
+	child := &failingRowIter{rows: []sql.Row{sql.NewRow(int64(2)), sql.NewRow(int64(1))}, err: fmt.Errorf("boom")}
+	iter := newSortIter(ctx, NewSort(sf, nil), child)
+
+	_, err := iter.Next()
+	require.Equal(child.err, err)
+	require.Len(store.Files(), 2)
+
+	// The iterator is never closed, but the files are removed at the end of the query
+	require.NoError(ctx.RunDeferred())
+	require.Empty(store.Files())
+}
+
+// failingRowIter returns its rows and then fails with its error.
+type failingRowIter struct {
+	rows []sql.Row
+	err  error
+}
+
+func (i *failingRowIter) Next() (sql.Row, error) {
+	if len(i.rows) == 0 {
+		return nil, i.err
+	}
+	row := i.rows[0]
+	i.rows = i.rows[1:]
+	return row, nil
+}
+
+func (i *failingRowIter) Close(*sql.Context) error {
+	return nil
+}
+
+var _ sql.RowIter = (*failingRowIter)(nil)
+
 func TestSortDescending(t *testing.T) {
 	require := require.New(t)
 	ctx := sql.NewEmptyContext()
@@ -277,7 +367,7 @@ func TestSortDescending(t *testing.T) {
 	}
 
 	sf := []sql.SortField{
-		{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsFirst},
+		{Column: expression.NewGetField(0, sql.Text, "col1", true), Order: sql.Descending, NullOrdering: sql.NullsDefault},
 	}
 	s := NewSort(sf, NewResolvedTable(child, nil, nil))
 	require.Equal(schema, s.Schema())
@@ -294,3 +384,84 @@ func TestSortDescending(t *testing.T) {
 	require.NoError(err)
 	require.Equal(expected, actual)
 }
+
+func TestSortNullOrdering(t *testing.T) {
+	schema := sql.Schema{
+		{Name: "a", Type: sql.Int64, Nullable: true},
+		{Name: "b", Type: sql.Int64, Nullable: true},
+	}
+	rows := []sql.Row{
+		sql.NewRow(int64(2), int64(1)),
+		sql.NewRow(nil, int64(1)),
+		sql.NewRow(int64(1), nil),
+		sql.NewRow(int64(1), int64(2)),
+		sql.NewRow(int64(1), int64(1)),
+	}
+
+	testCases := []struct {
+		name     string
+		a, b     sql.SortField
+		expected []sql.Row
+	}{
+		{
+			name: "asc default, desc default",
+			a:    sql.SortField{Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+			b:    sql.SortField{Order: sql.Descending, NullOrdering: sql.NullsDefault},
+			expected: []sql.Row{
+				{nil, int64(1)}, {int64(1), int64(2)}, {int64(1), int64(1)}, {int64(1), nil}, {int64(2), int64(1)},
+			},
+		},
+		{
+			name: "asc nulls last, desc nulls first",
+			a:    sql.SortField{Order: sql.Ascending, NullOrdering: sql.NullsLast},
+			b:    sql.SortField{Order: sql.Descending, NullOrdering: sql.NullsFirst},
+			expected: []sql.Row{
+				{int64(1), nil}, {int64(1), int64(2)}, {int64(1), int64(1)}, {int64(2), int64(1)}, {nil, int64(1)},
+			},
+		},
+		{
+			name: "desc nulls last, asc nulls first",
+			a:    sql.SortField{Order: sql.Descending, NullOrdering: sql.NullsLast},
+			b:    sql.SortField{Order: sql.Ascending, NullOrdering: sql.NullsFirst},
+			expected: []sql.Row{
+				{int64(2), int64(1)}, {int64(1), nil}, {int64(1), int64(1)}, {int64(1), int64(2)}, {nil, int64(1)},
+			},
+		},
+		{
+			name: "desc nulls first, asc nulls last",
+			a:    sql.SortField{Order: sql.Descending, NullOrdering: sql.NullsFirst},
+			b:    sql.SortField{Order: sql.Ascending, NullOrdering: sql.NullsLast},
+			expected: []sql.Row{
+				{nil, int64(1)}, {int64(2), int64(1)}, {int64(1), int64(1)}, {int64(1), int64(2)}, {int64(1), nil},
+			},
+		},
+		{
+			name: "desc default, asc default",
+			a:    sql.SortField{Order: sql.Descending, NullOrdering: sql.NullsDefault},
+			b:    sql.SortField{Order: sql.Ascending, NullOrdering: sql.NullsDefault},
+			expected: []sql.Row{
+				{int64(2), int64(1)}, {int64(1), nil}, {int64(1), int64(1)}, {int64(1), int64(2)}, {nil, int64(1)},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := sql.NewEmptyContext()
+
+			tbl := memory.NewTable("test", schema)
+			for _, row := range rows {
+				require.NoError(tbl.Insert(ctx, row))
+			}
+
+			tt.a.Column = expression.NewGetField(0, sql.Int64, "a", true)
+			tt.b.Column = expression.NewGetField(1, sql.Int64, "b", true)
+			sort := NewSort([]sql.SortField{tt.a, tt.b}, NewResolvedTable(tbl, nil, nil))
+
+			actual, err := sql.NodeToRows(ctx, sort)
+			require.NoError(err)
+			require.Equal(tt.expected, actual)
+		})
+	}
+}
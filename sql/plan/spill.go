@@ -0,0 +1,105 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+func init() {
+	// Row values are encoded as interfaces, so every concrete type that isn't a gob basic type must be registered.
+	gob.Register(time.Time{})
+	gob.Register(decimal.Decimal{})
+	gob.Register(sql.JSONDocument{})
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// spillFileCount is used to give every spill file of the process a unique name.
+var spillFileCount uint64
+
+// spillRows writes the rows given to a new file of the spill store of the context and returns its name. The removal
+// of the file is registered with Context.Defer, so it doesn't outlive the query even if the operator that created it
+// is never closed. Operators should still remove the file as soon as they're done with it.
+func spillRows(ctx *sql.Context, prefix string, rows []sql.Row) (string, error) {
+	store := ctx.SpillStore()
+	name := fmt.Sprintf("%s-%d-%d", prefix, ctx.Pid(), atomic.AddUint64(&spillFileCount, 1))
+	w, err := store.Create(name)
+	if err != nil {
+		return "", err
+	}
+	ctx.Defer(func() error {
+		return store.Remove(name)
+	})
+
+	enc := gob.NewEncoder(w)
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			w.Close()
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// spillFileIter iterates the rows of a file written with spillRows.
+type spillFileIter struct {
+	r   io.ReadCloser
+	dec *gob.Decoder
+}
+
+func openSpillFile(ctx *sql.Context, name string) (*spillFileIter, error) {
+	r, err := ctx.SpillStore().Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &spillFileIter{r: r, dec: gob.NewDecoder(r)}, nil
+}
+
+func (i *spillFileIter) Next() (sql.Row, error) {
+	var row sql.Row
+	if err := i.dec.Decode(&row); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (i *spillFileIter) Close() error {
+	return i.r.Close()
+}
+
+// removeSpillFiles removes the files given from the spill store of the context. Files that were already removed, such
+// as by the cleanup of the query, are ignored.
+func removeSpillFiles(ctx *sql.Context, names []string) error {
+	var firstErr error
+	store := ctx.SpillStore()
+	for _, name := range names {
+		if err := store.Remove(name); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
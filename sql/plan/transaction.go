@@ -16,14 +16,16 @@ package plan
 
 import "github.com/dolthub/go-mysql-server/sql"
 
-// Begin starts a transaction. This is provided just for compatibility with SQL clients and is a no-op.
+// Begin starts a transaction. This is provided mostly for compatibility with SQL clients, and only marks the session
+// as being in an explicit transaction.
 type Begin struct{}
 
 // NewBegin creates a new Begin node.
 func NewBegin() *Begin { return new(Begin) }
 
 // RowIter implements the sql.Node interface.
-func (*Begin) RowIter(*sql.Context, sql.Row) (sql.RowIter, error) {
+func (*Begin) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	ctx.Session.SetInExplicitTransaction(true)
 	return sql.RowsToRowIter(), nil
 }
 
@@ -47,15 +49,16 @@ func (*Begin) Children() []sql.Node { return nil }
 // Schema implements the sql.Node interface.
 func (*Begin) Schema() sql.Schema { return nil }
 
-// Commit commits the changes performed in a transaction. This is provided just for compatibility with SQL clients and
-// is a no-op.
+// Commit commits the changes performed in a transaction. This is provided mostly for compatibility with SQL clients,
+// and only ends the explicit transaction of the session, if any.
 type Commit struct{}
 
 // NewCommit creates a new Commit node.
 func NewCommit() *Commit { return new(Commit) }
 
 // RowIter implements the sql.Node interface.
-func (*Commit) RowIter(*sql.Context, sql.Row) (sql.RowIter, error) {
+func (*Commit) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	ctx.Session.SetInExplicitTransaction(false)
 	return sql.RowsToRowIter(), nil
 }
 
@@ -79,15 +82,21 @@ func (*Commit) Children() []sql.Node { return nil }
 // Schema implements the sql.Node interface.
 func (*Commit) Schema() sql.Schema { return nil }
 
-// Rollback undoes the changes performed in a transaction. This is provided just for compatibility with SQL clients and
-// is a no-op.
+// Rollback undoes the changes performed in a transaction. Only the changes of sessions that are
+// sql.TransactionRollbackSessions are undone, but the changes of the transaction are never delivered to the change
+// listeners of the catalog.
 type Rollback struct{}
 
 // NewRollback creates a new Rollback node.
 func NewRollback() *Rollback { return new(Rollback) }
 
-// RowIter implements the sql.Node interface.
-func (*Rollback) RowIter(*sql.Context, sql.Row) (sql.RowIter, error) {
+// RowIter implements the sql.Node interface. The transaction is rolled back with sql.RollbackSessionTransaction: the
+// changes recorded for it are discarded, so they're never delivered to change listeners, and the explicit transaction
+// of the session, if any, ends.
+func (*Rollback) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if err := sql.RollbackSessionTransaction(ctx, ctx.GetCurrentDatabase()); err != nil {
+		return nil, err
+	}
 	return sql.RowsToRowIter(), nil
 }
 
@@ -77,6 +77,10 @@ func (p *Truncate) DatabaseName() string {
 
 // RowIter implements the Node interface.
 func (p *Truncate) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if err := checkTableReadOnly(ctx, p.Child); err != nil {
+		return nil, err
+	}
+
 	truncatable, err := GetTruncatable(p.Child)
 	if err != nil {
 		return nil, err
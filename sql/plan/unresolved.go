@@ -89,5 +89,5 @@ func (t *UnresolvedTable) WithDatabase(database string) (*UnresolvedTable, error
 }
 
 func (t UnresolvedTable) String() string {
-	return fmt.Sprintf("UnresolvedTable(%s)", t.name)
+	return fmt.Sprintf("UnresolvedTable(%s)", sql.QuoteIdentifier(t.name))
 }
@@ -16,6 +16,8 @@ package plan
 
 import (
 	"fmt"
+	"io"
+	"reflect"
 
 	"gopkg.in/src-d/go-errors.v1"
 
@@ -28,11 +30,65 @@ var ErrUpdateUnexpectedSetResult = errors.NewKind("attempted to set field but ex
 // Update is a node for updating rows on tables.
 type Update struct {
 	UnaryNode
+	// OnChange, if set, is called for every row that was changed by the update.
+	OnChange UpdateChangeFunc
+	// OnProgress, if set, is called every ProgressInterval rows matched by the update.
+	OnProgress       UpdateProgressFunc
+	ProgressInterval int
+	// CollatedChanges makes the update compare string values according to their collation. See WithCollatedChanges.
+	CollatedChanges bool
+	// PoolRows makes the update reuse the buffers its source builds rows into across rows. See WithRowPooling.
+	PoolRows bool
 }
 
+// UpdateChangeFunc is a function to notify about a row changed by an update. Changed holds the indexes in the table
+// schema of the columns whose values differ between the old and new rows.
+type UpdateChangeFunc func(ctx *sql.Context, oldRow, newRow sql.Row, changed []int) error
+
+// UpdateProgressFunc is a function to report the progress of an update, with the number of rows matched and changed so
+// far. It's called synchronously from the update loop, so it should return quickly. Returning an error aborts the
+// update.
+type UpdateProgressFunc func(ctx *sql.Context, matched, updated int) error
+
 // NewUpdate creates an Update node.
 func NewUpdate(n sql.Node, updateExprs []sql.Expression) *Update {
-	return &Update{UnaryNode{NewUpdateSource(n, updateExprs)}}
+	return &Update{UnaryNode: UnaryNode{NewUpdateSource(n, updateExprs)}}
+}
+
+// WithChangeFunc returns a copy of this node that calls the function given for every row it changes.
+func (p *Update) WithChangeFunc(onChange UpdateChangeFunc) *Update {
+	np := *p
+	np.OnChange = onChange
+	return &np
+}
+
+// WithProgressFunc returns a copy of this node that calls the function given every interval rows it matches.
+func (p *Update) WithProgressFunc(interval int, onProgress UpdateProgressFunc) *Update {
+	np := *p
+	np.OnProgress = onProgress
+	np.ProgressInterval = interval
+	return &np
+}
+
+// WithRowPooling returns a copy of this node whose UpdateSource builds the old and new values of every row into a
+// buffer from a pool instead of a new row, reducing allocations on large updates. The buffer goes back to the pool as
+// soon as the update copied the values out of it, and the rows returned by the node are new rows, so no buffer is
+// reused while a row is still referenced. Updates whose child isn't an UpdateSource, such as those running triggers,
+// don't pool rows.
+func (p *Update) WithRowPooling(pool bool) *Update {
+	np := *p
+	np.PoolRows = pool
+	return &np
+}
+
+// WithCollatedChanges returns a copy of this node that compares the old and new values of string columns according to
+// their collation when finding the rows it changes, as EqualsCollated does. A row whose values only change in case, or
+// in accents under an accent-insensitive collation, is then left as is rather than written, and isn't counted as
+// changed.
+func (p *Update) WithCollatedChanges(collated bool) *Update {
+	np := *p
+	np.CollatedChanges = collated
+	return &np
 }
 
 func getUpdatable(node sql.Node) (sql.UpdatableTable, error) {
@@ -41,6 +97,8 @@ func getUpdatable(node sql.Node) (sql.UpdatableTable, error) {
 		return node, nil
 	case *IndexedTableAccess:
 		return getUpdatable(node.ResolvedTable)
+	case *PrimaryKeyLookup:
+		return getUpdatable(node.ResolvedTable)
 	case *ResolvedTable:
 		return getUpdatableTable(node.Table)
 	case sql.TableWrapper:
@@ -72,6 +130,8 @@ func updateDatabaseHelper(node sql.Node) string {
 		return ""
 	case *IndexedTableAccess:
 		return updateDatabaseHelper(node.ResolvedTable)
+	case *PrimaryKeyLookup:
+		return updateDatabaseHelper(node.ResolvedTable)
 	case *ResolvedTable:
 		return node.Database.Name()
 	case *UnresolvedTable:
@@ -101,22 +161,73 @@ func (ui UpdateInfo) String() string {
 
 type updateIter struct {
 	childIter sql.RowIter
-	schema    sql.Schema
-	updater   sql.RowUpdater
-	ctx       *sql.Context
-	closed    bool
+	// the schema of table when the update started, which the rows of childIter have
+	schema      sql.Schema
+	table       sql.UpdatableTable
+	updater     sql.RowUpdater
+	onChange    UpdateChangeFunc
+	foreignKeys *foreignKeyCascader
+	ctx         *sql.Context
+	closed      bool
+	// the first error returned by Next, which makes Close roll the updates back if the updater supports it
+	err error
+
+	onProgress       UpdateProgressFunc
+	progressInterval int
+	matched          int
+	updated          int
+
+	collatedChanges bool
+	// the child iterator, if its rows are buffers of rowBufferPool, which go back to it once copied
+	pooledSource *updateSourceIter
 }
 
 func (u *updateIter) Next() (sql.Row, error) {
+	row, err := u.next()
+	if err == nil && u.onProgress != nil && u.progressInterval > 0 && u.matched%u.progressInterval == 0 {
+		err = u.onProgress(u.ctx, u.matched, u.updated)
+	}
+	if err != nil && err != io.EOF && u.err == nil {
+		u.err = err
+	}
+	return row, err
+}
+
+func (u *updateIter) next() (sql.Row, error) {
 	oldAndNewRow, err := u.childIter.Next()
 	if err != nil {
 		return nil, err
 	}
+	u.matched++
+
+	if err = u.checkSchema(oldAndNewRow); err != nil {
+		return nil, err
+	}
+
+	// The new values are validated into the second half of the row returned, so each row is allocated once
+	n := len(oldAndNewRow) / 2
+	row := make(sql.Row, 2*n)
+	copy(row, oldAndNewRow[:n])
+	_, err = u.schema.ValidateRowInto(row[n:n], oldAndNewRow[n:])
+	if u.pooledSource != nil {
+		u.pooledSource.releaseRow()
+	}
+	if err != nil {
+		return nil, err
+	}
+	oldAndNewRow = row
+	oldRow, newRow := row[:n], row[n:]
+
+	if u.onChange != nil {
+		if err = u.updateAndNotify(oldRow, newRow); err != nil {
+			return nil, err
+		}
+		return oldAndNewRow, nil
+	}
 
-	oldRow, newRow := oldAndNewRow[:len(oldAndNewRow)/2], oldAndNewRow[len(oldAndNewRow)/2:]
-	if equals, err := oldRow.Equals(newRow, u.schema); err == nil {
+	if equals, err := u.rowsEqual(oldRow, newRow); err == nil {
 		if !equals {
-			err = u.updater.Update(u.ctx, oldRow, newRow)
+			err = u.update(oldRow, newRow)
 			if err != nil {
 				return nil, err
 			}
@@ -128,46 +239,97 @@ func (u *updateIter) Next() (sql.Row, error) {
 	return oldAndNewRow, nil
 }
 
-// Applies the update expressions given to the row given, returning the new resultant row.
-// TODO: a set of update expressions should probably be its own expression type with an Eval method that does this
-func applyUpdateExpressions(ctx *sql.Context, updateExprs []sql.Expression, row sql.Row) (sql.Row, error) {
-	var ok bool
-	prev := row
-	for _, updateExpr := range updateExprs {
-		val, err := updateExpr.Eval(ctx, prev)
-		if err != nil {
-			return nil, err
+// checkSchema returns ErrSchemaChangedDuringQuery if the row given doesn't have the old and new values of every column
+// of the schema the update started with, or if the columns of the table changed since, such as by a concurrent ALTER
+// TABLE. Writing the row would then misalign its values with the columns. Schema changes that keep the name and type
+// of every column, like a new column default, are ignored.
+func (u *updateIter) checkSchema(oldAndNewRow sql.Row) error {
+	current := u.table.Schema()
+	if len(oldAndNewRow) != 2*len(u.schema) || len(current) != len(u.schema) {
+		return sql.ErrSchemaChangedDuringQuery.New(u.table.Name())
+	}
+	for i, col := range current {
+		if col.Name != u.schema[i].Name || !reflect.DeepEqual(col.Type, u.schema[i].Type) {
+			return sql.ErrSchemaChangedDuringQuery.New(u.table.Name())
 		}
-		prev, ok = val.(sql.Row)
-		if !ok {
-			return nil, ErrUpdateUnexpectedSetResult.New(val)
+	}
+	return nil
+}
+
+// rowsEqual returns whether the old and new values of a row are equal, comparing strings according to their collation
+// if the update was configured to.
+func (u *updateIter) rowsEqual(oldRow, newRow sql.Row) (bool, error) {
+	if u.collatedChanges {
+		return oldRow.EqualsCollated(newRow, u.schema)
+	}
+	return oldRow.Equals(newRow, u.schema)
+}
+
+// updateAndNotify updates the row if any of its columns changed, and reports the changed columns to onChange.
+func (u *updateIter) updateAndNotify(oldRow, newRow sql.Row) error {
+	changedColumns := oldRow.ChangedColumns
+	if u.collatedChanges {
+		changedColumns = oldRow.ChangedColumnsCollated
+	}
+	changed, err := changedColumns(newRow, u.schema)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err = u.update(oldRow, newRow); err != nil {
+		return err
+	}
+	return u.onChange(u.ctx, oldRow, newRow, changed)
+}
+
+// update applies the foreign key actions for the change to the row given, then updates it.
+func (u *updateIter) update(oldRow, newRow sql.Row) error {
+	if u.foreignKeys != nil {
+		if err := u.foreignKeys.OnUpdate(u.ctx, oldRow, newRow); err != nil {
+			return err
 		}
 	}
-	return prev, nil
+	if err := u.updater.Update(u.ctx, oldRow, newRow); err != nil {
+		return err
+	}
+	u.updated++
+	return nil
 }
 
+// Close finalizes the updater, rolling the updates back if Next failed and the updater supports it, then closes the
+// child iterator, even if finalizing the updater failed.
 func (u *updateIter) Close(ctx *sql.Context) error {
 	if !u.closed {
 		u.closed = true
-		if err := u.updater.Close(ctx); err != nil {
-			return err
+		var finalizer sql.Closer = u.updater
+		if rollbacker, ok := u.updater.(sql.RollbackableRowUpdater); ok && u.err != nil {
+			finalizer = sql.CloserFunc(rollbacker.Rollback)
 		}
-		return u.childIter.Close(ctx)
+		return sql.CloseAll(ctx, finalizer, u.childIter)
 	}
 	return nil
 }
 
-func newUpdateIter(childIter sql.RowIter, schema sql.Schema, updater sql.RowUpdater, ctx *sql.Context) *updateIter {
+func newUpdateIter(childIter sql.RowIter, table sql.UpdatableTable, updater sql.RowUpdater, onChange UpdateChangeFunc, ctx *sql.Context) *updateIter {
 	return &updateIter{
 		childIter: childIter,
 		updater:   updater,
-		schema:    schema,
+		schema:    table.Schema(),
+		table:     table,
+		onChange:  onChange,
 		ctx:       ctx,
 	}
 }
 
 // RowIter implements the Node interface.
 func (u *Update) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if err := checkTableReadOnly(ctx, u.Child); err != nil {
+		return nil, err
+	}
+
 	updatable, err := getUpdatable(u.Child)
 	if err != nil {
 		return nil, err
@@ -179,7 +341,19 @@ func (u *Update) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 		return nil, err
 	}
 
-	return newUpdateIter(iter, updatable.Schema(), updater, ctx), nil
+	updateIter := newUpdateIter(iter, updatable, updater, u.OnChange, ctx)
+	updateIter.foreignKeys, err = newForeignKeyCascader(ctx, getResolvedTableDatabase(u.Child), updatable)
+	if err != nil {
+		_ = updateIter.Close(ctx)
+		return nil, err
+	}
+	updateIter.onProgress, updateIter.progressInterval = u.OnProgress, u.ProgressInterval
+	updateIter.collatedChanges = u.CollatedChanges
+	if source, ok := iter.(*updateSourceIter); ok && u.PoolRows {
+		source.pooledRows = true
+		updateIter.pooledSource = source
+	}
+	return updateIter, nil
 }
 
 // WithChildren implements the Node interface.
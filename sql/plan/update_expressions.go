@@ -0,0 +1,67 @@
+// Copyright 2020-2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+)
+
+// Applies the update expressions given to the row given, returning the new resultant row. Used by UPDATE, SET and the
+// ON DUPLICATE KEY UPDATE clause of INSERT.
+// TODO: a set of update expressions should probably be its own expression type with an Eval method that does this
+func applyUpdateExpressions(ctx *sql.Context, updateExprs []sql.Expression, row sql.Row) (sql.Row, error) {
+	var ok bool
+	prev := row
+	for _, updateExpr := range updateExprs {
+		val, err := updateExpr.Eval(ctx, prev)
+		if err != nil {
+			return nil, err
+		}
+		prev, ok = val.(sql.Row)
+		if !ok {
+			return nil, ErrUpdateUnexpectedSetResult.New(val)
+		}
+	}
+	return prev, nil
+}
+
+// bindValues returns a copy of the update expressions given with every VALUES function bound to the value its column
+// has in the row that was attempted to be inserted. The expressions given are left untouched, so they can be bound
+// again for every conflicting row.
+func bindValues(updateExprs []sql.Expression, insertRow sql.Row) ([]sql.Expression, error) {
+	bound := make([]sql.Expression, len(updateExprs))
+	for i, updateExpr := range updateExprs {
+		var err error
+		bound[i], err = expression.TransformUp(updateExpr, func(e sql.Expression) (sql.Expression, error) {
+			valuesExpr, ok := e.(*function.Values)
+			if !ok {
+				return e, nil
+			}
+			getField, ok := valuesExpr.Child.(*expression.GetField)
+			if !ok || getField.Index() >= len(insertRow) {
+				return nil, fmt.Errorf("VALUES functions may only contain column names")
+			}
+			return &function.Values{UnaryExpression: valuesExpr.UnaryExpression, Value: insertRow[getField.Index()]}, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bound, nil
+}
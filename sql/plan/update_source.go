@@ -16,8 +16,10 @@ package plan
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
 )
 
 // UpdateSource is the source of updates for an Update node. Its schema is the concatenation of the old and new rows,
@@ -93,6 +95,31 @@ type updateSourceIter struct {
 	updateExprs []sql.Expression
 	tableSchema sql.Schema
 	ctx         *sql.Context
+	// whether the rows returned are buffers of rowBufferPool, which the consumer gives back with releaseRow
+	pooledRows bool
+	// the buffer of the last row returned, if pooling rows and it wasn't released yet
+	buf *sql.Row
+}
+
+// rowBufferPool holds the buffers updateSourceIter builds rows into when pooling rows.
+var rowBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(sql.Row)
+	},
+}
+
+// releaseRow gives the buffer of the last row returned back to rowBufferPool, cleared so that it doesn't keep the
+// values alive. The row mustn't be used afterwards.
+func (u *updateSourceIter) releaseRow() {
+	if u.buf == nil {
+		return
+	}
+	for i := range *u.buf {
+		(*u.buf)[i] = nil
+	}
+	*u.buf = (*u.buf)[:0]
+	rowBufferPool.Put(u.buf)
+	u.buf = nil
 }
 
 func (u *updateSourceIter) Next() (sql.Row, error) {
@@ -106,6 +133,11 @@ func (u *updateSourceIter) Next() (sql.Row, error) {
 		return nil, err
 	}
 
+	newRow, err = u.computeGeneratedColumns(newRow)
+	if err != nil {
+		return nil, err
+	}
+
 	// Reduce the row to the length of the schema. The length can differ when some update values come from an outer
 	// scope, which will be the first N values in the row.
 	// TODO: handle this in the analyzer instead?
@@ -115,9 +147,49 @@ func (u *updateSourceIter) Next() (sql.Row, error) {
 		newRow = newRow[len(newRow)-expectedSchemaLen:]
 	}
 
+	// The values of virtual columns are computed when read, the table is given the rows it stores
+	oldRow, newRow = u.tableSchema.StoredRow(oldRow), u.tableSchema.StoredRow(newRow)
+
+	if u.pooledRows {
+		u.releaseRow()
+		u.buf = rowBufferPool.Get().(*sql.Row)
+		*u.buf = append(append(*u.buf, oldRow...), newRow...)
+		return *u.buf, nil
+	}
 	return oldRow.Append(newRow), nil
 }
 
+// computeGeneratedColumns recomputes the values of the stored generated columns of the row given, in schema order, so
+// that generated columns may depend on the ones before them. Virtual columns before a stored one are evaluated for it
+// to depend on, but aren't written: the caller stores the row with StoredRow.
+func (u *updateSourceIter) computeGeneratedColumns(row sql.Row) (sql.Row, error) {
+	offset := len(row) - len(u.tableSchema)
+	if offset < 0 {
+		return row, nil
+	}
+
+	last := -1
+	for i, col := range u.tableSchema {
+		if col.IsGenerated() && !col.IsVirtual() {
+			last = i
+		}
+	}
+
+	for i, col := range u.tableSchema[:last+1] {
+		if !col.IsGenerated() {
+			continue
+		}
+		tableRow := row[offset:]
+		val, err := col.Generated.Eval(u.ctx, tableRow)
+		if err != nil {
+			return nil, err
+		}
+		row[offset+i] = val
+	}
+
+	return row, nil
+}
+
 func (u *updateSourceIter) Close(ctx *sql.Context) error {
 	return u.childIter.Close(ctx)
 }
@@ -133,6 +205,11 @@ func (u *UpdateSource) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, erro
 		return nil, err
 	}
 
+	if err = validateNoGeneratedColumnsSet(table.Schema(), tableReferences(u.Child, table), u.UpdateExprs); err != nil {
+		_ = rowIter.Close(ctx)
+		return nil, err
+	}
+
 	return &updateSourceIter{
 		childIter:   rowIter,
 		updateExprs: u.UpdateExprs,
@@ -141,6 +218,58 @@ func (u *UpdateSource) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, erro
 	}, nil
 }
 
+// tableReferences returns the names the table given is referred to by in the node given: its own name and the names
+// of the aliases of it.
+func tableReferences(node sql.Node, table sql.Table) []string {
+	names := []string{table.Name()}
+	Inspect(node, func(n sql.Node) bool {
+		if alias, ok := n.(*TableAlias); ok {
+			if aliased, err := getUpdatable(alias.Child); err == nil && strings.EqualFold(aliased.Name(), table.Name()) {
+				names = append(names, alias.Name())
+			}
+		}
+		return true
+	})
+	return names
+}
+
+// validateNoGeneratedColumnsSet returns ErrCannotSetGeneratedColumn if any of the update expressions assigns a value
+// to a generated column of the table schema given, referred to by one of the table names given.
+func validateNoGeneratedColumnsSet(schema sql.Schema, tableNames []string, updateExprs []sql.Expression) error {
+	for _, updateExpr := range updateExprs {
+		setField, ok := updateExpr.(*expression.SetField)
+		if !ok {
+			continue
+		}
+		gf, ok := setField.Left.(*expression.GetField)
+		if !ok {
+			continue
+		}
+		if !referencesTable(gf.Table(), tableNames) {
+			continue
+		}
+		for _, col := range schema {
+			if col.IsGenerated() && strings.EqualFold(col.Name, gf.Name()) {
+				return sql.ErrCannotSetGeneratedColumn.New(col.Name, col.Source)
+			}
+		}
+	}
+	return nil
+}
+
+// referencesTable returns whether the table of a field, if it has one, is one of the table names given.
+func referencesTable(fieldTable string, tableNames []string) bool {
+	if fieldTable == "" {
+		return true
+	}
+	for _, name := range tableNames {
+		if strings.EqualFold(fieldTable, name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (u *UpdateSource) WithChildren(children ...sql.Node) (sql.Node, error) {
 	if len(children) != 1 {
 		return nil, sql.ErrInvalidChildrenNumber.New(u, len(children), 1)
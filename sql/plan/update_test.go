@@ -0,0 +1,600 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+func TestUpdateChangedColumns(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+		{Name: "b", Type: sql.Float64, Source: "foo", Nullable: true},
+	}
+	table := memory.NewTable("foo", schema)
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), int64(1), float64(1))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(2), nil, float64(1))))
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(3), int64(1), float64(2))))
+
+	// SET a = 1, b = 1: only changes a NULL in row 2 and b in row 3. The integer literal assigned to the float column
+	// compares equal to the existing value in rows 1 and 2.
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(1), sql.Int64),
+		),
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(2, sql.Float64, "foo", "b", true),
+			expression.NewLiteral(int64(1), sql.Int64),
+		),
+	}
+
+	changes := make(map[int64][]int)
+	update := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs).
+		WithChangeFunc(func(ctx *sql.Context, oldRow, newRow sql.Row, changed []int) error {
+			changes[oldRow[0].(int64)] = changed
+			return nil
+		})
+
+	iter, err := update.RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+
+	require.Equal(map[int64][]int{
+		2: {1},
+		3: {2},
+	}, changes)
+}
+
+func TestUpdateGeneratedColumns(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	// c = a * 2 is stored, d = c + 1 is virtual and depends on c
+	stored, err := sql.NewColumnDefaultValue(
+		expression.NewMult(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", false),
+			expression.NewLiteral(int64(2), sql.Int64),
+		), sql.Int64, false, false)
+	require.NoError(err)
+	virtual, err := sql.NewColumnDefaultValue(
+		expression.NewPlus(
+			expression.NewGetFieldWithTable(2, sql.Int64, "foo", "c", false),
+			expression.NewLiteral(int64(1), sql.Int64),
+		), sql.Int64, false, false)
+	require.NoError(err)
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo"},
+		{Name: "c", Type: sql.Int64, Source: "foo", Generated: stored},
+		{Name: "d", Type: sql.Int64, Source: "foo", Generated: virtual, Virtual: true},
+	}
+	table := memory.NewTable("foo", schema)
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), int64(1), int64(2), nil)))
+
+	setC := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(2, sql.Int64, "foo", "c", false),
+			expression.NewLiteral(int64(10), sql.Int64),
+		),
+	}
+	_, err = NewUpdate(NewResolvedTable(table, nil, nil), setC).RowIter(ctx, nil)
+	require.True(sql.ErrCannotSetGeneratedColumn.Is(err))
+
+	// the columns of other tables, such as joined ones, may have the names of generated columns
+	setJoinedC := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(5, sql.Int64, "bar", "c", false),
+			expression.NewLiteral(int64(10), sql.Int64),
+		),
+	}
+	require.NoError(validateNoGeneratedColumnsSet(schema, []string{"foo"}, setJoinedC))
+	require.True(sql.ErrCannotSetGeneratedColumn.Is(validateNoGeneratedColumnsSet(schema, []string{"foo", "bar"}, setJoinedC)))
+
+	setA := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", false),
+			expression.NewLiteral(int64(5), sql.Int64),
+		),
+	}
+	iter, err := NewUpdate(NewResolvedTable(table, nil, nil), setA).RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+
+	// the virtual column isn't stored, but computed when read
+	partitions, err := table.Partitions(ctx)
+	require.NoError(err)
+	partition, err := partitions.Next()
+	require.NoError(err)
+	partitionRows, err := table.PartitionRows(ctx, partition)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, partitionRows)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(5), int64(10), nil}}, rows)
+
+	rows, err = sql.NodeToRows(ctx, NewResolvedTable(table, nil, nil))
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(5), int64(10), int64(11)}}, rows)
+}
+
+func TestUpdateReadOnlySession(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	table := memory.NewTable("foo", schema)
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), int64(1))))
+
+	update := NewUpdate(NewResolvedTable(table, nil, nil), []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(2), sql.Int64),
+		),
+	})
+
+	ctx.SetReadOnly(true)
+	require.True(ctx.IsReadOnly())
+	_, err := update.RowIter(ctx, nil)
+	require.True(sql.ErrReadOnlyTransaction.Is(err))
+	require.Contains(err.Error(), "--read-only")
+
+	require.NoError(ctx.Set(ctx, sql.SuperReadOnlySessionVar, sql.Int8, int8(1)))
+	_, err = update.RowIter(ctx, nil)
+	require.True(sql.ErrReadOnlyTransaction.Is(err))
+	require.Contains(err.Error(), "--super-read-only")
+
+	// turning read_only off also turns off super_read_only
+	ctx.SetReadOnly(false)
+	require.False(ctx.IsReadOnly())
+	iter, err := update.RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+
+	iter, err = NewResolvedTable(table, nil, nil).RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(2)}}, rows)
+}
+
+// bypassSession is a session whose user bypasses read_only.
+type bypassSession struct {
+	sql.Session
+}
+
+var _ sql.ReadOnlyBypassSession = bypassSession{}
+
+func (bypassSession) BypassesReadOnly() bool {
+	return true
+}
+
+func TestUpdateReadOnlyBypassSession(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewContext(context.Background(), sql.WithSession(bypassSession{sql.NewBaseSession()}))
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	table := memory.NewTable("foo", schema)
+	require.NoError(table.Insert(ctx, sql.NewRow(int64(1), int64(1))))
+
+	update := NewUpdate(NewResolvedTable(table, nil, nil), []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(2), sql.Int64),
+		),
+	})
+
+	ctx.SetReadOnly(true)
+	iter, err := update.RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+
+	require.NoError(ctx.Set(ctx, sql.SuperReadOnlySessionVar, sql.Int8, int8(1)))
+	_, err = update.RowIter(ctx, nil)
+	require.True(sql.ErrReadOnlyTransaction.Is(err))
+	require.Contains(err.Error(), "--super-read-only")
+}
+
+// rollbackTable is a table whose updater fails on a row with the primary key given, and records how it was finalized.
+type rollbackTable struct {
+	*memory.Table
+	failOn     int64
+	closed     bool
+	rolledBack bool
+}
+
+func (t *rollbackTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return &rollbackUpdater{RowUpdater: t.Table.Updater(ctx), table: t}
+}
+
+type rollbackUpdater struct {
+	sql.RowUpdater
+	table *rollbackTable
+}
+
+var _ sql.RollbackableRowUpdater = (*rollbackUpdater)(nil)
+
+func (u *rollbackUpdater) Update(ctx *sql.Context, old sql.Row, new sql.Row) error {
+	if old[0] == u.table.failOn {
+		return fmt.Errorf("cannot update row %d", u.table.failOn)
+	}
+	return u.RowUpdater.Update(ctx, old, new)
+}
+
+func (u *rollbackUpdater) Close(ctx *sql.Context) error {
+	u.table.closed = true
+	return u.RowUpdater.Close(ctx)
+}
+
+func (u *rollbackUpdater) Rollback(*sql.Context) error {
+	u.table.rolledBack = true
+	return nil
+}
+
+func TestUpdateRollback(t *testing.T) {
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(2), sql.Int64),
+		),
+	}
+
+	tests := []struct {
+		name       string
+		failOn     int64
+		rolledBack bool
+	}{
+		{"update fails", 2, true},
+		{"update succeeds", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := sql.NewEmptyContext()
+
+			table := &rollbackTable{Table: memory.NewTable("foo", schema), failOn: test.failOn}
+			for i := int64(1); i <= 3; i++ {
+				require.NoError(table.Insert(ctx, sql.NewRow(i, int64(1))))
+			}
+
+			iter, err := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs).RowIter(ctx, nil)
+			require.NoError(err)
+			for err == nil {
+				_, err = iter.Next()
+			}
+			require.Equal(test.rolledBack, err != io.EOF)
+			require.NoError(iter.Close(ctx))
+
+			require.Equal(test.rolledBack, table.rolledBack)
+			require.Equal(!test.rolledBack, table.closed)
+		})
+	}
+}
+
+func TestUpdateProgress(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	table := memory.NewTable("foo", schema)
+	for i := int64(1); i <= 7; i++ {
+		// odd rows already have the value set
+		require.NoError(table.Insert(ctx, sql.NewRow(i, i%2)))
+	}
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(1), sql.Int64),
+		),
+	}
+
+	type progress struct{ matched, updated int }
+	var reported []progress
+	update := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs).
+		WithProgressFunc(3, func(ctx *sql.Context, matched, updated int) error {
+			reported = append(reported, progress{matched, updated})
+			return nil
+		})
+
+	iter, err := update.RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	require.Equal([]progress{{3, 1}, {6, 3}}, reported)
+
+	// an error from the callback aborts the update
+	stop := fmt.Errorf("stop")
+	update = update.WithProgressFunc(2, func(ctx *sql.Context, matched, updated int) error {
+		return stop
+	})
+	iter, err = update.RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = iter.Next()
+	require.NoError(err)
+	_, err = iter.Next()
+	require.Equal(stop, err)
+	require.NoError(iter.Close(ctx))
+}
+
+// schemaChangingTable is a table whose schema can be changed while it is being updated.
+type schemaChangingTable struct {
+	*memory.Table
+	schema sql.Schema
+}
+
+func (t *schemaChangingTable) Schema() sql.Schema {
+	return t.schema
+}
+
+func TestUpdateSchemaChanged(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	memTable := memory.NewTable("foo", schema)
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(memTable.Insert(ctx, sql.NewRow(i, int64(1))))
+	}
+	table := &schemaChangingTable{Table: memTable, schema: schema}
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(2), sql.Int64),
+		),
+	}
+
+	iter, err := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs).RowIter(ctx, nil)
+	require.NoError(err)
+	_, err = iter.Next()
+	require.NoError(err)
+
+	// refreshing the schema without changing its columns is fine
+	refreshed := *schema[1]
+	refreshed.Comment = "refreshed"
+	table.schema = sql.Schema{schema[0], &refreshed}
+	_, err = iter.Next()
+	require.NoError(err)
+
+	table.schema = sql.Schema{schema[0], schema[1], {Name: "b", Type: sql.Int64, Source: "foo", Nullable: true}}
+	_, err = iter.Next()
+	require.True(sql.ErrSchemaChangedDuringQuery.Is(err))
+	require.NoError(iter.Close(ctx))
+
+	partitions, err := memTable.Partitions(ctx)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, sql.NewTableRowIter(ctx, memTable, partitions))
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(2)}, {int64(2), int64(2)}, {int64(3), int64(1)}}, rows)
+}
+
+// closeFailingTable is a table whose updater fails to close.
+type closeFailingTable struct {
+	*memory.Table
+}
+
+func (t *closeFailingTable) Updater(ctx *sql.Context) sql.RowUpdater {
+	return &closeFailingUpdater{t.Table.Updater(ctx)}
+}
+
+type closeFailingUpdater struct {
+	sql.RowUpdater
+}
+
+var errUpdaterClose = fmt.Errorf("cannot close updater")
+
+func (u *closeFailingUpdater) Close(ctx *sql.Context) error {
+	_ = u.RowUpdater.Close(ctx)
+	return errUpdaterClose
+}
+
+// closeRecordingIter is a row iterator that records whether it was closed.
+type closeRecordingIter struct {
+	sql.RowIter
+	closed int
+}
+
+func (i *closeRecordingIter) Close(ctx *sql.Context) error {
+	i.closed++
+	return i.RowIter.Close(ctx)
+}
+
+func TestUpdateCloseAfterUpdaterCloseFails(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+	}
+	table := &closeFailingTable{memory.NewTable("foo", schema)}
+	childIter := &closeRecordingIter{RowIter: sql.RowsToRowIter()}
+
+	iter := newUpdateIter(childIter, table, table.Updater(ctx), nil, ctx)
+	_, err := iter.Next()
+	require.Equal(io.EOF, err)
+
+	require.Equal(errUpdaterClose, iter.Close(ctx))
+	require.Equal(1, childIter.closed)
+
+	// closing again is a no-op
+	require.NoError(iter.Close(ctx))
+	require.Equal(1, childIter.closed)
+}
+
+func TestUpdateCollatedChanges(t *testing.T) {
+	ciType := sql.MustCreateString(sqltypes.VarChar, 10, sql.Collation_utf8mb4_0900_ai_ci)
+	binType := sql.MustCreateString(sqltypes.VarChar, 10, sql.Collation_utf8mb4_bin)
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "ci", Type: ciType, Source: "foo"},
+		{Name: "bin", Type: binType, Source: "foo"},
+	}
+
+	tests := []struct {
+		name     string
+		collated bool
+		col      int
+		typ      sql.Type
+		value    string
+		updated  int
+	}{
+		{"ci column", false, 1, ciType, "a", 1},
+		{"ci column with collated changes", true, 1, ciType, "a", 0},
+		{"ai column with collated accent changes", true, 1, ciType, "á", 0},
+		{"binary column", false, 2, binType, "a", 1},
+		{"binary column with collated changes", true, 2, binType, "a", 1},
+		{"binary column with collated accent changes", true, 2, binType, "á", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			ctx := sql.NewEmptyContext()
+
+			newUpdate := func() *Update {
+				table := memory.NewTable("foo", schema)
+				require.NoError(table.Insert(ctx, sql.NewRow(int64(1), "A", "A")))
+				return NewUpdate(NewResolvedTable(table, nil, nil), []sql.Expression{
+					expression.NewSetField(
+						expression.NewGetFieldWithTable(test.col, test.typ, "foo", schema[test.col].Name, false),
+						expression.NewLiteral(test.value, sql.LongText),
+					),
+				}).WithCollatedChanges(test.collated)
+			}
+
+			var changed [][]int
+			update := newUpdate().WithChangeFunc(func(ctx *sql.Context, oldRow, newRow sql.Row, cols []int) error {
+				changed = append(changed, cols)
+				return nil
+			})
+			iter, err := update.RowIter(ctx, nil)
+			require.NoError(err)
+			_, err = sql.RowIterToRows(ctx, iter)
+			require.NoError(err)
+			require.Len(changed, test.updated)
+
+			// without a change func, the rows are compared with Equals instead of ChangedColumns
+			iter, err = newUpdate().RowIter(ctx, nil)
+			require.NoError(err)
+			_, err = sql.RowIterToRows(ctx, iter)
+			require.NoError(err)
+			require.Equal(test.updated, iter.(*updateIter).updated)
+		})
+	}
+}
+
+func TestUpdateRowPooling(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	schema := sql.Schema{
+		{Name: "pk", Type: sql.Int64, Source: "foo", PrimaryKey: true},
+		{Name: "a", Type: sql.Int64, Source: "foo", Nullable: true},
+	}
+	table := memory.NewTable("foo", schema)
+	for i := int64(1); i <= 3; i++ {
+		require.NoError(table.Insert(ctx, sql.NewRow(i, i)))
+	}
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetFieldWithTable(1, sql.Int64, "foo", "a", true),
+			expression.NewLiteral(int64(2), sql.Int64),
+		),
+	}
+
+	update := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs).WithRowPooling(true)
+	iter, err := update.RowIter(ctx, nil)
+	require.NoError(err)
+	require.NotNil(iter.(*updateIter).pooledSource)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(err)
+	// the rows returned don't share the pooled buffers
+	require.Equal([]sql.Row{
+		{int64(1), int64(1), int64(1), int64(2)},
+		{int64(2), int64(2), int64(2), int64(2)},
+		{int64(3), int64(3), int64(3), int64(2)},
+	}, rows)
+
+	rows, err = sql.NodeToRows(ctx, NewResolvedTable(table, nil, nil))
+	require.NoError(err)
+	require.Equal([]sql.Row{{int64(1), int64(2)}, {int64(2), int64(2)}, {int64(3), int64(2)}}, rows)
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	table := benchmarkTable(b)
+	updateExprs := []sql.Expression{
+		expression.NewSetField(
+			expression.NewGetField(1, sql.Int64, "b", false),
+			expression.NewPlus(
+				expression.NewGetField(1, sql.Int64, "b", false),
+				expression.NewLiteral(int64(1), sql.Int64),
+			),
+		),
+	}
+
+	bench := func(node sql.Node) func(*testing.B) {
+		return func(b *testing.B) {
+			require := require.New(b)
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				ctx := sql.NewEmptyContext()
+				iter, err := node.RowIter(ctx, nil)
+				require.NoError(err)
+
+				_, err = sql.RowIterToRows(ctx, iter)
+				require.NoError(err)
+			}
+		}
+	}
+
+	update := NewUpdate(NewResolvedTable(table, nil, nil), updateExprs)
+	b.Run("no pooling", bench(update))
+	b.Run("pooling", bench(update.WithRowPooling(true)))
+}
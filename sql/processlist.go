@@ -91,14 +91,15 @@ func (p ProcessType) String() string {
 
 // Process represents a process in the SQL server.
 type Process struct {
-	Pid        uint64
-	Connection uint32
-	User       string
-	Type       ProcessType
-	Query      string
-	Progress   map[string]TableProgress
-	StartedAt  time.Time
-	Kill       context.CancelFunc
+	Pid           uint64
+	Connection    uint32
+	User          string
+	Type          ProcessType
+	Query         string
+	Progress      map[string]TableProgress
+	StartedAt     time.Time
+	ResourceGroup string
+	Kill          context.CancelFunc
 }
 
 // Done needs to be called when this process has finished.
@@ -114,12 +115,15 @@ func (p *Process) Seconds() uint64 {
 type ProcessList struct {
 	mu    sync.RWMutex
 	procs map[uint64]*Process
+	// cancels are the functions cancelling the context of each process with a reason.
+	cancels map[uint64]CancelWithReasonFunc
 }
 
 // NewProcessList creates a new process list.
 func NewProcessList() *ProcessList {
 	return &ProcessList{
-		procs: make(map[uint64]*Process),
+		procs:   make(map[uint64]*Process),
+		cancels: make(map[uint64]CancelWithReasonFunc),
 	}
 }
 
@@ -143,19 +147,28 @@ func (pl *ProcessList) AddProcess(
 		return nil, ErrPidAlreadyUsed.New(ctx.Pid())
 	}
 
-	newCtx, cancel := context.WithCancel(ctx)
-	ctx = ctx.WithContext(newCtx)
+	ctx, cancel := ctx.NewSubContextWithCancelReason()
+
+	// Cleanups still pending when the process is killed or finished are run once the context is cancelled.
+	go func(ctx *Context) {
+		<-ctx.Done()
+		if err := ctx.RunDeferred(); err != nil {
+			logrus.WithField("pid", ctx.Pid()).Errorf("error running deferred cleanup: %s", err)
+		}
+	}(ctx)
 
 	pl.procs[ctx.Pid()] = &Process{
-		Pid:        ctx.Pid(),
-		Connection: ctx.ID(),
-		Type:       typ,
-		Query:      query,
-		Progress:   make(map[string]TableProgress),
-		User:       ctx.Session.Client().User,
-		StartedAt:  time.Now(),
-		Kill:       cancel,
+		Pid:           ctx.Pid(),
+		Connection:    ctx.ID(),
+		Type:          typ,
+		Query:         query,
+		Progress:      make(map[string]TableProgress),
+		User:          ctx.Session.Client().User,
+		StartedAt:     time.Now(),
+		ResourceGroup: ctx.Session.ResourceGroup(),
+		Kill:          func() { cancel(CancelReason_None) },
 	}
+	pl.cancels[ctx.Pid()] = cancel
 
 	return ctx, nil
 }
@@ -282,31 +295,49 @@ func (pl *ProcessList) RemovePartitionProgress(pid uint64, tableName, partitionN
 	delete(tablePg.PartitionsProgress, partitionName)
 }
 
-// Kill terminates all queries for a given connection id.
+// Kill terminates all queries for a given connection id. Their statements fail with ErrQueryInterrupted.
 func (pl *ProcessList) Kill(connID uint32) {
+	pl.kill(connID, false, CancelReason_Killed)
+}
+
+// KillOnlyQueries kills all queries, but not index creation queries, for a
+// given connection id.
+func (pl *ProcessList) KillOnlyQueries(connID uint32) {
+	pl.kill(connID, true, CancelReason_Killed)
+}
+
+// ConnectionClosed kills all queries, but not index creation queries, for a given connection id whose client closed
+// the connection. Their statements fail with ErrConnectionClosed.
+func (pl *ProcessList) ConnectionClosed(connID uint32) {
+	pl.kill(connID, true, CancelReason_ConnectionClosed)
+}
+
+// SetConnectionUser records the user given as the user of the processes of the connection given, as when the user of
+// the connection changes with COM_CHANGE_USER.
+func (pl *ProcessList) SetConnectionUser(connID uint32, user string) {
 	pl.mu.Lock()
 	defer pl.mu.Unlock()
 
-	for pid, proc := range pl.procs {
+	for _, proc := range pl.procs {
 		if proc.Connection == connID {
-			logrus.Infof("kill query: pid %d", pid)
-			proc.Done()
-			delete(pl.procs, pid)
+			proc.User = user
 		}
 	}
 }
 
-// KillOnlyQueries kills all queries, but not index creation queries, for a
-// given connection id.
-func (pl *ProcessList) KillOnlyQueries(connID uint32) {
+func (pl *ProcessList) kill(connID uint32, onlyQueries bool, reason CancelReason) {
 	pl.mu.Lock()
 	defer pl.mu.Unlock()
 
 	for pid, proc := range pl.procs {
-		if proc.Connection == connID && proc.Type == QueryProcess {
+		if proc.Connection == connID && (!onlyQueries || proc.Type == QueryProcess) {
 			logrus.Infof("kill query: pid %d", pid)
+			if cancel, ok := pl.cancels[pid]; ok {
+				cancel(reason)
+			}
 			proc.Done()
 			delete(pl.procs, pid)
+			delete(pl.cancels, pid)
 		}
 	}
 }
@@ -322,6 +353,7 @@ func (pl *ProcessList) Done(pid uint64) {
 	}
 
 	delete(pl.procs, pid)
+	delete(pl.cancels, pid)
 }
 
 // Processes returns the list of current running processes.
@@ -18,6 +18,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -152,3 +153,70 @@ func TestKillConnection(t *testing.T) {
 	require.False(t, killed[2])
 	require.True(t, killed[3])
 }
+
+func TestSetConnectionUser(t *testing.T) {
+	require := require.New(t)
+	pl := NewProcessList()
+
+	for i := uint64(1); i <= 2; i++ {
+		_, err := pl.AddProcess(
+			NewContext(context.Background(), WithPid(i), WithSession(NewSession("", "", "foo", uint32(i)))),
+			QueryProcess,
+			"foo",
+		)
+		require.NoError(err)
+	}
+
+	pl.SetConnectionUser(1, "bar")
+	require.Equal("bar", pl.procs[1].User)
+	require.Equal("foo", pl.procs[2].User)
+}
+
+func TestKillCancellationReason(t *testing.T) {
+	pl := NewProcessList()
+	sess := NewSession("", "", "", 1)
+
+	killed, err := pl.AddProcess(NewContext(context.Background(), WithPid(1), WithSession(sess)), QueryProcess, "foo")
+	require.NoError(t, err)
+	pl.Kill(1)
+	<-killed.Done()
+	require.Equal(t, CancelReason_Killed, killed.CancellationReason())
+	require.True(t, ErrQueryInterrupted.Is(killed.CancellationError()))
+
+	closed, err := pl.AddProcess(NewContext(context.Background(), WithPid(2), WithSession(sess)), QueryProcess, "foo")
+	require.NoError(t, err)
+	pl.ConnectionClosed(1)
+	<-closed.Done()
+	require.Equal(t, CancelReason_ConnectionClosed, closed.CancellationReason())
+	require.True(t, ErrConnectionClosed.Is(closed.CancellationError()))
+
+	// Finished processes are cancelled without a reason
+	done, err := pl.AddProcess(NewContext(context.Background(), WithPid(3), WithSession(sess)), QueryProcess, "foo")
+	require.NoError(t, err)
+	pl.Done(3)
+	<-done.Done()
+	require.Equal(t, CancelReason_None, done.CancellationReason())
+	require.Equal(t, context.Canceled, done.CancellationError())
+	require.Empty(t, pl.cancels)
+}
+
+func TestKillRunsDeferred(t *testing.T) {
+	pl := NewProcessList()
+
+	ctx, err := pl.AddProcess(NewContext(context.Background(), WithPid(1)), QueryProcess, "foo")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	ctx.Defer(func() error {
+		close(done)
+		return nil
+	})
+
+	pl.Kill(ctx.ID())
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deferred cleanup was not run after the process was killed")
+	}
+}
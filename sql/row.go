@@ -47,15 +47,28 @@ func (r Row) Append(r2 Row) Row {
 	return row
 }
 
-// Equals checks whether two rows are equal given a schema.
+// Equals checks whether two rows are equal given a schema. Values are compared NULL-safely, as <=> does: two NULLs are
+// equal, while a NULL never equals a non-NULL value, such as an empty string, a zero or a JSON null, whatever the type
+// of the column.
 func (r Row) Equals(row Row, schema Schema) (bool, error) {
+	return r.equals(row, schema, false)
+}
+
+// EqualsCollated checks whether two rows are equal given a schema, like Equals, except that the values of string
+// columns with a case-insensitive or accent-insensitive collation are compared as CompareCollated does. Binary and
+// case-sensitive, accent-sensitive collations compare as in Equals.
+func (r Row) EqualsCollated(row Row, schema Schema) (bool, error) {
+	return r.equals(row, schema, true)
+}
+
+func (r Row) equals(row Row, schema Schema, collated bool) (bool, error) {
 	if len(row) != len(r) || len(row) != len(schema) {
 		return false, nil
 	}
 
 	for i, colLeft := range r {
 		colRight := row[i]
-		cmp, err := schema[i].Type.Compare(colLeft, colRight)
+		cmp, err := compareColumnValues(schema[i], colLeft, colRight, collated)
 		if err != nil {
 			return false, err
 		}
@@ -67,6 +80,62 @@ func (r Row) Equals(row Row, schema Schema) (bool, error) {
 	return true, nil
 }
 
+// ChangedColumns returns the indexes of the columns whose values differ between this row and the one given, using
+// the same comparison as Equals. A NULL compared to a non-NULL value is a change.
+func (r Row) ChangedColumns(row Row, schema Schema) ([]int, error) {
+	return r.changedColumns(row, schema, false)
+}
+
+// ChangedColumnsCollated returns the indexes of the columns whose values differ between this row and the one given,
+// like ChangedColumns, but using the same comparison as EqualsCollated.
+func (r Row) ChangedColumnsCollated(row Row, schema Schema) ([]int, error) {
+	return r.changedColumns(row, schema, true)
+}
+
+func (r Row) changedColumns(row Row, schema Schema, collated bool) ([]int, error) {
+	if len(row) != len(r) || len(row) != len(schema) {
+		return nil, ErrUnexpectedRowLength.New(len(schema), len(row))
+	}
+
+	var changed []int
+	for i, colLeft := range r {
+		cmp, err := compareColumnValues(schema[i], colLeft, row[i], collated)
+		if err != nil {
+			return nil, err
+		}
+		if cmp != 0 {
+			changed = append(changed, i)
+		}
+	}
+
+	return changed, nil
+}
+
+// compareColumnValues compares two values of the column given with the type of the column. NULLs are compared before
+// the type is consulted, since not every type tells them apart from the values they convert to. If collated is true and
+// the column is a string with a case-insensitive or accent-insensitive collation, values are compared with
+// CompareCollated instead.
+func compareColumnValues(col *Column, left, right interface{}, collated bool) (int, error) {
+	if hasNulls, res := compareNulls(left, right); hasNulls {
+		return res, nil
+	}
+
+	st, ok := col.Type.(StringType)
+	if !collated || !ok || !(st.Collation().IsCaseInsensitive() || st.Collation().IsAccentInsensitive()) {
+		return col.Type.Compare(left, right)
+	}
+
+	l, err := st.Convert(left)
+	if err != nil {
+		return 0, err
+	}
+	r, err := st.Convert(right)
+	if err != nil {
+		return 0, err
+	}
+	return CompareCollated(st.Collation(), l.(string), r.(string)), nil
+}
+
 // FormatRow returns a formatted string representing this row's values
 func FormatRow(row Row) string {
 	var sb strings.Builder
@@ -18,6 +18,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/dolthub/vitess/go/sqltypes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -66,3 +67,93 @@ func TestRowsToRowIter(t *testing.T) {
 	err = iter.Close(ctx)
 	require.NoError(err)
 }
+
+func TestRowChangedColumns(t *testing.T) {
+	require := require.New(t)
+
+	schema := Schema{
+		{Name: "a", Type: Int64, Nullable: true},
+		{Name: "b", Type: Float64, Nullable: true},
+		{Name: "c", Type: LongText, Nullable: true},
+	}
+
+	changed, err := NewRow(int64(1), float64(1), "foo").ChangedColumns(NewRow(int64(1), int64(1), "foo"), schema)
+	require.NoError(err)
+	require.Empty(changed)
+
+	changed, err = NewRow(nil, float64(1), "foo").ChangedColumns(NewRow(int64(1), float64(1), nil), schema)
+	require.NoError(err)
+	require.Equal([]int{0, 2}, changed)
+
+	_, err = NewRow(int64(1)).ChangedColumns(NewRow(int64(1)), schema)
+	require.True(ErrUnexpectedRowLength.Is(err))
+}
+
+func TestRowEqualsNulls(t *testing.T) {
+	schema := Schema{
+		{Name: "i", Type: Int64, Nullable: true},
+		{Name: "s", Type: LongText, Nullable: true},
+		{Name: "j", Type: JSON, Nullable: true},
+		{Name: "t", Type: CreateTuple(Int64, Int64), Nullable: true},
+	}
+
+	testCases := []struct {
+		name        string
+		left, right Row
+		changed     []int
+		equals      bool
+	}{
+		{"NULL to NULL", NewRow(nil, nil, nil, nil), NewRow(nil, nil, nil, nil), nil, true},
+		{"NULL to value", NewRow(nil, nil, nil, nil), NewRow(int64(0), "", JSONDocument{Val: nil}, []interface{}{int64(0), int64(0)}), []int{0, 1, 2, 3}, false},
+		{"value to NULL", NewRow(int64(0), "", JSONDocument{Val: nil}, []interface{}{int64(0), int64(0)}), NewRow(nil, nil, nil, nil), []int{0, 1, 2, 3}, false},
+		{"value to same value", NewRow(int64(1), "a", JSONDocument{Val: nil}, []interface{}{int64(1), int64(2)}), NewRow(int64(1), "a", JSONDocument{Val: nil}, []interface{}{int64(1), int64(2)}), nil, true},
+		{"value to other value", NewRow(int64(1), "a", JSONDocument{Val: nil}, []interface{}{int64(1), int64(2)}), NewRow(int64(2), "b", JSONDocument{Val: true}, []interface{}{int64(2), int64(1)}), []int{0, 1, 2, 3}, false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			for _, collated := range []bool{false, true} {
+				equals, err := tt.left.equals(tt.right, schema, collated)
+				require.NoError(err)
+				require.Equal(tt.equals, equals)
+
+				changed, err := tt.left.changedColumns(tt.right, schema, collated)
+				require.NoError(err)
+				require.Equal(tt.changed, changed)
+			}
+		})
+	}
+}
+
+func TestRowEqualsCollated(t *testing.T) {
+	require := require.New(t)
+
+	schema := Schema{
+		{Name: "ci", Type: MustCreateString(sqltypes.VarChar, 10, Collation_utf8mb4_0900_ai_ci), Nullable: true},
+		{Name: "bin", Type: MustCreateString(sqltypes.VarChar, 10, Collation_utf8mb4_bin), Nullable: true},
+	}
+
+	// a change in case is only a change for the binary collation
+	equals, err := NewRow("A", "b").EqualsCollated(NewRow("a", "b"), schema)
+	require.NoError(err)
+	require.True(equals)
+	equals, err = NewRow("A", "b").Equals(NewRow("a", "b"), schema)
+	require.NoError(err)
+	require.False(equals)
+	equals, err = NewRow("a", "B").EqualsCollated(NewRow("a", "b"), schema)
+	require.NoError(err)
+	require.False(equals)
+
+	changed, err := NewRow("A", "B").ChangedColumnsCollated(NewRow("a", "b"), schema)
+	require.NoError(err)
+	require.Equal([]int{1}, changed)
+	changed, err = NewRow("A", "B").ChangedColumns(NewRow("a", "b"), schema)
+	require.NoError(err)
+	require.Equal([]int{0, 1}, changed)
+
+	// other differences are still changes, and so is a NULL compared to a non-NULL value
+	changed, err = NewRow("a", nil).ChangedColumnsCollated(NewRow("b", "b"), schema)
+	require.NoError(err)
+	require.Equal([]int{0, 1}, changed)
+}
@@ -24,6 +24,9 @@ import (
 var (
 	// ErrUnexpectedType is thrown when a received type is not the expected
 	ErrUnexpectedType = errors.NewKind("value at %d has unexpected type: %s")
+
+	// ErrColumnNotNullable is returned when a row being written has a null value for a non-nullable column.
+	ErrColumnNotNullable = errors.NewKind("column name '%v' is non-nullable but attempted to set a value of null")
 )
 
 // Schema is the definition of a table.
@@ -50,6 +53,91 @@ func (s Schema) CheckRow(row Row) error {
 	return nil
 }
 
+// ValidateRow checks that the row given can be written to a table with this schema: it must have a value for every
+// column, a null value only for nullable columns, and values that can be converted to the type of their column.
+// Returns a copy of the row with its values converted to the types of their columns. Fails with
+// ErrUnexpectedRowLength, ErrColumnNotNullable or the conversion error of the first column that doesn't conform.
+func (s Schema) ValidateRow(row Row) (Row, error) {
+	return s.ValidateRowInto(nil, row)
+}
+
+// ValidateRowInto is like ValidateRow, but writes the converted values into dst, which it returns, when it has the
+// capacity for them, instead of allocating a new row.
+func (s Schema) ValidateRowInto(dst, row Row) (Row, error) {
+	if len(row) != len(s) {
+		return nil, ErrUnexpectedRowLength.New(len(s), len(row))
+	}
+
+	var converted Row
+	if cap(dst) >= len(row) {
+		converted = dst[:len(row)]
+	} else {
+		converted = make(Row, len(row))
+	}
+	for i, col := range s {
+		if row[i] == nil {
+			if !col.Nullable && !col.IsVirtual() {
+				return nil, ErrColumnNotNullable.New(col.Name)
+			}
+			converted[i] = nil
+			continue
+		}
+
+		val, err := col.Type.Convert(row[i])
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = val
+	}
+
+	return converted, nil
+}
+
+// HasVirtualColumns returns whether the schema has any virtual generated column.
+func (s Schema) HasVirtualColumns() bool {
+	for _, col := range s {
+		if col.IsVirtual() {
+			return true
+		}
+	}
+	return false
+}
+
+// StoredRow returns the row given as it's stored by a table with this schema: with nil values for its virtual generated
+// columns, which aren't stored but computed when read. The row given isn't modified.
+func (s Schema) StoredRow(row Row) Row {
+	if !s.HasVirtualColumns() || len(row) != len(s) {
+		return row
+	}
+	stored := row.Copy()
+	for i, col := range s {
+		if col.IsVirtual() {
+			stored[i] = nil
+		}
+	}
+	return stored
+}
+
+// ComputeVirtualColumns returns a copy of the row given, read from a table with this schema, with the values of its
+// virtual generated columns computed in schema order, so that they may depend on the ones before them.
+func (s Schema) ComputeVirtualColumns(ctx *Context, row Row) (Row, error) {
+	if len(row) != len(s) {
+		return row, nil
+	}
+	computed := row.Copy()
+	for i, col := range s {
+		if !col.IsVirtual() {
+			continue
+		}
+		val, err := col.Generated.Eval(ctx, computed)
+		if err != nil {
+			return nil, err
+		}
+		computed[i] = val
+	}
+	return computed, nil
+}
+
 // Contains returns whether the schema contains a column with the given name.
 func (s Schema) Contains(column string, source string) bool {
 	return s.IndexOf(column, source) >= 0
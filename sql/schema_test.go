@@ -0,0 +1,62 @@
+// Copyright 2020-2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+func TestSchemaValidateRow(t *testing.T) {
+	schema := Schema{
+		{Name: "pk", Type: Int64, Nullable: false},
+		{Name: "name", Type: MustCreateStringWithDefaults(sqltypes.VarChar, 5), Nullable: true},
+		{Name: "d", Type: Date, Nullable: true},
+	}
+
+	tests := []struct {
+		name     string
+		row      Row
+		expected Row
+		err      *errors.Kind
+	}{
+		{"converts values", NewRow(int8(1), "abc", "2021-04-01"), NewRow(int64(1), "abc", time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)), nil},
+		{"nullable columns", NewRow("2", nil, nil), NewRow(int64(2), nil, nil), nil},
+		{"too few values", NewRow(int64(1), "abc"), nil, ErrUnexpectedRowLength},
+		{"too many values", NewRow(int64(1), "abc", nil, nil), nil, ErrUnexpectedRowLength},
+		{"null in non-nullable column", NewRow(nil, "abc", nil), nil, ErrColumnNotNullable},
+		{"value too long", NewRow(int64(1), "abcdef", nil), nil, ErrLengthBeyondLimit},
+		{"invalid date", NewRow(int64(1), "abc", "not a date"), nil, ErrConvertingToTime},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			row := test.row.Copy()
+			converted, err := schema.ValidateRow(row)
+			if test.err != nil {
+				require.Error(t, err)
+				require.True(t, test.err.Is(err), "unexpected error %s", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, converted)
+			require.Equal(t, test.row, row)
+		})
+	}
+}
@@ -19,7 +19,10 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/rand"
 	"os"
+	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -38,14 +41,44 @@ const (
 const (
 	CurrentDBSessionVar  = "current_database"
 	AutoCommitSessionVar = "autocommit"
+	TimeZoneSessionVar   = "time_zone"
+
+	ReadOnlySessionVar      = "read_only"
+	SuperReadOnlySessionVar = "super_read_only"
+
+	MaxExecutionTimeSessionVar   = "max_execution_time"
+	WaitTimeoutSessionVar        = "wait_timeout"
+	InteractiveTimeoutSessionVar = "interactive_timeout"
+	SQLSafeUpdatesSessionVar     = "sql_safe_updates"
+	LongQueryTimeSessionVar      = "long_query_time"
+
+	CharacterSetResultsSessionVar = "character_set_results"
+	SQLModeSessionVar             = "sql_mode"
+
+	AutoIncrementIncrementSessionVar = "auto_increment_increment"
+	AutoIncrementOffsetSessionVar    = "auto_increment_offset"
+
+	MaxPreparedStmtCountSessionVar = "max_prepared_stmt_count"
+	DefaultStorageEngineSessionVar = "default_storage_engine"
+	BinlogFormatSessionVar         = "binlog_format"
 )
 
+// SQLModeNoBackslashEscapes is the sql_mode that makes a backslash an ordinary character in string literals and LIKE
+// patterns, rather than an escape character.
+const SQLModeNoBackslashEscapes = "NO_BACKSLASH_ESCAPES"
+
 // Client holds session user information.
 type Client struct {
 	// User of the session.
 	User string
 	// Address of the client.
 	Address string
+	// Attributes are the connection attributes the client sent when connecting, such as program_name or
+	// _client_name. Empty, rather than nil, for a session without attributes. Must not be modified.
+	Attributes map[string]string
+	// Interactive is whether the client connected with the CLIENT_INTERACTIVE flag, such as the mysql command line
+	// client, so that the session expires after interactive_timeout rather than wait_timeout.
+	Interactive bool
 }
 
 // Session holds the session data.
@@ -54,6 +87,10 @@ type Session interface {
 	Address() string
 	// User of the session.
 	Client() Client
+	// SetClientAttributes sets the connection attributes of the client of the session, as returned in Client.
+	SetClientAttributes(attrs map[string]string)
+	// SetClientInteractive sets whether the client of the session is interactive, as returned in Client.
+	SetClientInteractive(interactive bool)
 	// Set session configuration.
 	Set(ctx context.Context, key string, typ Type, value interface{}) error
 	// Get session configuration.
@@ -66,14 +103,25 @@ type Session interface {
 	CommitTransaction(ctx *Context, dbName string) error
 	// GetAll returns a copy of session configuration
 	GetAll() map[string]TypedValue
+	// OverriddenVariables returns a copy of the session configuration entries that differ from DefaultSessionConfig,
+	// as checked by HasDefaultValue, including the variables without a default.
+	OverriddenVariables() map[string]TypedValue
 	// ID returns the unique ID of the connection.
 	ID() uint32
 	// Warn stores the warning in the session.
 	Warn(warn *Warning)
+	// WarnBatch stores the warnings given in the session, in order, as a single operation.
+	WarnBatch(warns []*Warning)
+	// MergeWarnings stores the warnings of the session given after the warnings of this session, in the order they
+	// were raised, such as when a nested execution with its own session completes.
+	MergeWarnings(from Session)
 	// Warnings returns a copy of session warnings (from the most recent).
 	Warnings() []*Warning
 	// ClearWarnings cleans up session warnings.
 	ClearWarnings()
+	// Diagnostics returns the diagnostics area of the session, as read by GET DIAGNOSTICS. Its conditions are the
+	// session warnings, so it's cleared along with them.
+	Diagnostics() DiagnosticsArea
 	// WarningCount returns a number of session warnings
 	WarningCount() uint16
 	// AddLock adds a lock to the set of locks owned by this user which will need to be released if this session terminates
@@ -91,6 +139,202 @@ type Session interface {
 	SetLastQueryInfo(key string, value int64)
 	// GetLastQueryInfo returns the session-level query info for the key given, for the query most recently executed.
 	GetLastQueryInfo(key string) int64
+	// SetResourceGroup sets the resource group queries of this session are assigned to.
+	SetResourceGroup(name string)
+	// ResourceGroup returns the resource group queries of this session are assigned to. An empty name is the default
+	// group.
+	ResourceGroup() string
+	// DefaultStorageEngine returns the name of the storage engine of the tables created without an ENGINE option, as
+	// set by default_storage_engine. SET checks the name against Catalog.StorageEngines, but the session doesn't.
+	DefaultStorageEngine() string
+	// TimeZone returns the location for the current value of the time_zone session variable.
+	TimeZone() (*time.Location, error)
+	// Status returns the counters of the status variables of the session.
+	Status() *StatusCounters
+	// ResetStats sets the status variables of the session to zero, as FLUSH STATUS does.
+	ResetStats()
+	// Dialect returns the flavor of MySQL the session emulates.
+	Dialect() Dialect
+	// SetDialect sets the flavor of MySQL the session emulates, along with the version and version_comment session
+	// variables that report it, unless they were set to values other than the ones of the previous dialect.
+	SetDialect(d Dialect)
+	// SetReadOnly sets the read_only session variable. Turning it off also turns off super_read_only.
+	SetReadOnly(readOnly bool)
+	// IsReadOnly returns whether statements writing data or changing the schema are rejected for this session, because
+	// either read_only or super_read_only is set.
+	IsReadOnly() bool
+	// MarkKilled marks the session as killed, as done by KILL. The statement running in the session, or the next one
+	// if none is running, aborts with ErrQueryInterrupted.
+	MarkKilled()
+	// IsKilled returns whether the session was marked as killed since the flag was last cleared.
+	IsKilled() bool
+	// ClearKilled clears the killed flag, once the statement it aborted is done.
+	ClearKilled()
+	// OptimizerSwitch returns the value of every flag of the optimizer_switch session variable.
+	OptimizerSwitch() map[string]bool
+	// SetOptimizerFlag sets a flag of the optimizer_switch session variable, rewriting its value. Returns
+	// ErrUnknownOptimizerFlag if there is no such flag.
+	SetOptimizerFlag(name string, on bool) error
+	// TemporaryTables returns the temporary tables of this session, which are consulted before the databases when
+	// resolving table names.
+	TemporaryTables() *TemporaryTableRegistry
+	// PendingChanges returns the changes made by the current transaction of this session, delivered to the change
+	// listeners of the catalog when the transaction is committed.
+	PendingChanges() *PendingChanges
+	// SetLastActivity records the time of the latest activity of the session, such as the start or end of a statement.
+	SetLastActivity(t time.Time)
+	// LastActivity returns the time recorded by SetLastActivity, or the creation time of the session if none was.
+	LastActivity() time.Time
+	// SetInExplicitTransaction records whether the session is in a transaction started by BEGIN or START TRANSACTION,
+	// until it's ended by COMMIT or ROLLBACK.
+	SetInExplicitTransaction(inTx bool)
+	// InExplicitTransaction returns whether the session is in a transaction started by BEGIN or START TRANSACTION,
+	// rather than running its statements in their own transactions.
+	InExplicitTransaction() bool
+	// Reset returns the session to the state of a new one, as COM_RESET_CONNECTION does when a pooled connection is
+	// handed to another user: its transaction is rolled back, and its user variables, temporary tables, warnings and
+	// named locks are dropped, while its system variables get their default values back. The connection ID, client,
+	// current database and dialect of the session are kept. The session only records the names of its named locks, so
+	// they must be released with LockSubsystem.ReleaseAll beforehand. The transaction is rolled back with
+	// RollbackSessionTransaction, so the context given must be one of this session.
+	Reset(ctx *Context) error
+	// ChangeUser resets the session as Reset does and binds it to the client given, as COM_CHANGE_USER does once the
+	// new user is authenticated. Unlike Reset, it doesn't keep the client of the session.
+	ChangeUser(ctx *Context, client Client) error
+	// AddPreparedStatement registers the server-side prepared statement with the ID given, replacing any with that ID.
+	// Returns ErrMaxPreparedStmtCountReached if the session already has max_prepared_stmt_count statements. Unlike
+	// MySQL's, where the cap is shared by every session of the server, the cap applies to each session on its own.
+	AddPreparedStatement(id uint32, query string) error
+	// RemovePreparedStatement removes the prepared statement with the ID given, as COM_STMT_CLOSE does.
+	RemovePreparedStatement(id uint32)
+	// PreparedStatements returns the queries of the prepared statements of the session, by ID.
+	PreparedStatements() map[uint32]string
+}
+
+// TransactionStateSession is a Session that knows whether it has a transaction open, which keeps it from being expired
+// while idle. Sessions that aren't TransactionStateSessions have a transaction open while they have PendingChanges.
+type TransactionStateSession interface {
+	Session
+	// InTransaction returns whether the session has a transaction open.
+	InTransaction() bool
+}
+
+// StrictFoundRowsSession is a Session whose FOUND_ROWS() can follow MySQL strictly, returning 0 rather than 1 before
+// the session issues any SELECT. BaseSession is a StrictFoundRowsSession.
+type StrictFoundRowsSession interface {
+	Session
+	// SetStrictFoundRows sets whether FOUND_ROWS() follows MySQL strictly for this session.
+	SetStrictFoundRows(strict bool)
+	// StrictFoundRows returns whether FOUND_ROWS() follows MySQL strictly for this session.
+	StrictFoundRows() bool
+}
+
+var _ StrictFoundRowsSession = (*BaseSession)(nil)
+
+// ReadOnlyBypassSession is a Session whose user may write while the server is read_only, as users with the SUPER or
+// CONNECTION_ADMIN privilege may in MySQL. No session may write while the server is super_read_only. BaseSession has no
+// privileges and isn't a ReadOnlyBypassSession, so integrators with privileges implement it to tell the two apart.
+type ReadOnlyBypassSession interface {
+	Session
+	// BypassesReadOnly returns whether the user of the session may write while the server is read_only.
+	BypassesReadOnly() bool
+}
+
+// IdleTime returns the time the session given has been idle for at the time given, since its LastActivity.
+func IdleTime(s Session, now time.Time) time.Duration {
+	idle := now.Sub(s.LastActivity())
+	if idle < 0 {
+		return 0
+	}
+	return idle
+}
+
+// IdleTimeout returns the time the session given may stay idle before it's expired, given by the wait_timeout session
+// variable in seconds. Returns 0, for no timeout, if the variable is unset, zero, or not a positive number.
+func IdleTimeout(s Session) time.Duration {
+	return timeoutSessionVar(s, WaitTimeoutSessionVar)
+}
+
+// EffectiveIdleTimeout returns the time the session given may stay idle before it's expired, which is its IdleTimeout
+// unless its client is interactive, in which case it's given by the interactive_timeout session variable in seconds,
+// with the same rules.
+func EffectiveIdleTimeout(s Session) time.Duration {
+	if s.Client().Interactive {
+		return timeoutSessionVar(s, InteractiveTimeoutSessionVar)
+	}
+	return IdleTimeout(s)
+}
+
+// timeoutSessionVar returns the duration of the session variable given, in seconds, or 0 if it's unset, zero, or not a
+// positive number.
+func timeoutSessionVar(s Session, name string) time.Duration {
+	_, val := s.Get(name)
+	if val == nil {
+		return 0
+	}
+	secs, err := Int64.Convert(val)
+	if err != nil || secs.(int64) <= 0 {
+		return 0
+	}
+	return time.Duration(secs.(int64)) * time.Second
+}
+
+// IsIdleExpired returns whether the session given has been idle for longer than its EffectiveIdleTimeout at the time
+// given, so that its connection should be closed. A session with a transaction open never expires, so its changes
+// aren't lost, and neither does one in an explicit transaction, even if it hasn't made any changes yet.
+func IsIdleExpired(s Session, now time.Time) bool {
+	timeout := EffectiveIdleTimeout(s)
+	if timeout <= 0 || IdleTime(s, now) <= timeout {
+		return false
+	}
+	if s.InExplicitTransaction() {
+		return false
+	}
+	if ts, ok := s.(TransactionStateSession); ok {
+		return !ts.InTransaction()
+	}
+	return s.PendingChanges().Empty()
+}
+
+// TransactionWarningsSession is a Session that wants to be given the warnings pending in the session when a
+// transaction is committed, for instance to record them in an audit log along with the transaction. Warnings are never
+// delivered when a transaction is rolled back.
+type TransactionWarningsSession interface {
+	Session
+	// CommitTransactionWithWarnings commits the current transaction for the database given, like CommitTransaction,
+	// along with the session warnings at the time of the commit, from the most recent.
+	CommitTransactionWithWarnings(ctx *Context, dbName string, warnings []*Warning) error
+}
+
+// CommitSessionTransaction commits the current transaction of the session in the context given. If the session is a
+// TransactionWarningsSession, its pending warnings are passed along to the commit.
+func CommitSessionTransaction(ctx *Context, dbName string) error {
+	if ws, ok := ctx.Session.(TransactionWarningsSession); ok {
+		return ws.CommitTransactionWithWarnings(ctx, dbName, ws.Warnings())
+	}
+	return ctx.Session.CommitTransaction(ctx, dbName)
+}
+
+// TransactionRollbackSession is a Session that can undo the changes of its current transaction. Rolling back the
+// transaction of other sessions only discards the changes pending delivery to the change listeners of the catalog.
+type TransactionRollbackSession interface {
+	Session
+	// RollbackTransaction undoes the changes of the current transaction for the database given.
+	RollbackTransaction(ctx *Context, dbName string) error
+}
+
+// RollbackSessionTransaction rolls back the current transaction of the session in the context given, as ROLLBACK does:
+// it's undone if the session is a TransactionRollbackSession, its pending changes are discarded, and its explicit
+// transaction, if any, ends.
+func RollbackSessionTransaction(ctx *Context, dbName string) error {
+	if rs, ok := ctx.Session.(TransactionRollbackSession); ok {
+		if err := rs.RollbackTransaction(ctx, dbName); err != nil {
+			return err
+		}
+	}
+	ctx.PendingChanges().Discard()
+	ctx.SetInExplicitTransaction(false)
+	return nil
 }
 
 // BaseSession is the basic session type.
@@ -106,6 +350,28 @@ type BaseSession struct {
 	locks         map[string]bool
 	queriedDb     string
 	lastQueryInfo map[string]int64
+	resourceGroup string
+	// see SetStrictFoundRows
+	strictFoundRows bool
+	// set to 1 by MarkKilled, and read without holding mu
+	killed int32
+	// the last time zone parsed by TimeZone, and its location
+	tzName string
+	tzLoc  *time.Location
+	// the temporary tables created by the session
+	tempTables *TemporaryTableRegistry
+	// the changes of the current transaction
+	pendingChanges *PendingChanges
+	// see SetLastActivity
+	lastActivity time.Time
+	// the counters of the status variables of the session
+	status *StatusCounters
+	// see SetDialect
+	dialect Dialect
+	// see SetInExplicitTransaction
+	explicitTx bool
+	// the queries of the prepared statements of the session, by ID
+	prepared map[uint32]string
 }
 
 // CommitTransaction commits the current transaction for the current database.
@@ -118,7 +384,29 @@ func (s *BaseSession) CommitTransaction(*Context, string) error {
 func (s *BaseSession) Address() string { return s.addr }
 
 // Client returns session's client information.
-func (s *BaseSession) Client() Client { return s.client }
+func (s *BaseSession) Client() Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.client
+}
+
+// SetClientAttributes implements the Session interface. The attributes given are copied.
+func (s *BaseSession) SetClientAttributes(attrs map[string]string) {
+	copied := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		copied[k] = v
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client.Attributes = copied
+}
+
+// SetClientInteractive implements the Session interface.
+func (s *BaseSession) SetClientInteractive(interactive bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client.Interactive = interactive
+}
 
 // Set implements the Session interface.
 func (s *BaseSession) Set(ctx context.Context, key string, typ Type, value interface{}) error {
@@ -152,6 +440,22 @@ func (s *BaseSession) GetAll() map[string]TypedValue {
 	return m
 }
 
+// OverriddenVariables implements the Session interface.
+func (s *BaseSession) OverriddenVariables() map[string]TypedValue {
+	defaults := DefaultSessionConfig()
+	m := make(map[string]TypedValue)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for k, v := range s.config {
+		if cfg, ok := defaults[k]; ok && cfg.Typ == v.Typ && cfg.Value == v.Value {
+			continue
+		}
+		m[k] = v
+	}
+	return m
+}
+
 // GetCurrentDatabase gets the current database for this session
 func (s *BaseSession) GetCurrentDatabase() string {
 	return s.currentDB
@@ -165,11 +469,54 @@ func (s *BaseSession) SetCurrentDatabase(dbName string) {
 // ID implements the Session interface.
 func (s *BaseSession) ID() uint32 { return s.id }
 
-// Warn stores the warning in the session.
+// Warn stores the warning in the session. Warnings beyond the math.MaxUint16 a session can report are dropped.
 func (s *BaseSession) Warn(warn *Warning) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.warnings = append(s.warnings, warn)
+	s.appendWarnings([]*Warning{warn})
+}
+
+// WarnBatch stores the warnings given in the session, taking the session lock only once. Warnings beyond the
+// math.MaxUint16 a session can report are dropped.
+func (s *BaseSession) WarnBatch(warns []*Warning) {
+	if len(warns) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appendWarnings(warns)
+}
+
+// MergeWarnings stores the warnings of the session given after the warnings of this session, in the order they were
+// raised. Nothing is merged from this same session, whose warnings are already stored. Warnings beyond the
+// math.MaxUint16 a session can report are dropped.
+func (s *BaseSession) MergeWarnings(from Session) {
+	if from == nil || from == Session(s) {
+		return
+	}
+
+	// Warnings are returned from the most recent
+	recent := from.Warnings()
+	warns := make([]*Warning, len(recent))
+	for i, warn := range recent {
+		warns[len(recent)-i-1] = warn
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appendWarnings(warns)
+}
+
+// appendWarnings stores the warnings given after the warnings of the session, up to math.MaxUint16 in all. The
+// session lock must be held.
+func (s *BaseSession) appendWarnings(warns []*Warning) {
+	if room := math.MaxUint16 - len(s.warnings); len(warns) > room {
+		if room < 0 {
+			room = 0
+		}
+		warns = warns[:room]
+	}
+	s.warnings = append(s.warnings, warns...)
 }
 
 // Warnings returns a copy of session warnings (from the most recent - the last one)
@@ -187,6 +534,11 @@ func (s *BaseSession) Warnings() []*Warning {
 	return warns
 }
 
+// Diagnostics implements the Session interface.
+func (s *BaseSession) Diagnostics() DiagnosticsArea {
+	return DiagnosticsArea{Conditions: s.Warnings()}
+}
+
 // ClearWarnings cleans up session warnings
 func (s *BaseSession) ClearWarnings() {
 	s.mu.Lock()
@@ -254,6 +606,51 @@ func (s *BaseSession) SetQueriedDatabase(dbName string) {
 	s.queriedDb = dbName
 }
 
+// SetResourceGroup implements the Session interface.
+func (s *BaseSession) SetResourceGroup(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resourceGroup = name
+}
+
+// ResourceGroup implements the Session interface.
+func (s *BaseSession) ResourceGroup() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.resourceGroup
+}
+
+// DefaultStorageEngine implements the Session interface.
+func (s *BaseSession) DefaultStorageEngine() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if name, ok := s.config[DefaultStorageEngineSessionVar].Value.(string); ok && name != "" {
+		return name
+	}
+	return DefaultStorageEngine
+}
+
+// TimeZone implements the Session interface.
+func (s *BaseSession) TimeZone() (*time.Location, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tz string
+	if v, ok := s.config[TimeZoneSessionVar]; ok && v.Value != nil {
+		tz = fmt.Sprint(v.Value)
+	}
+	if s.tzLoc != nil && s.tzName == tz {
+		return s.tzLoc, nil
+	}
+
+	loc, err := ParseTimeZone(tz)
+	if err != nil {
+		return nil, err
+	}
+	s.tzName, s.tzLoc = tz, loc
+	return loc, nil
+}
+
 type (
 	// TypedValue is a value along with its type.
 	TypedValue struct {
@@ -266,15 +663,39 @@ type (
 		Level   string
 		Message string
 		Code    int
+		// SQLState is the five character SQLSTATE of the condition, such as 01000, or empty if it's unknown.
+		SQLState string
 	}
 )
 
+// Convert returns the value converted to the type given, so that the Go type of the value matches the type, as
+// expected of every value stored in a session. Numeric types also accept ON and OFF, as 1 and 0. Returns an error if
+// the value can't be represented in the type, such as a number out of its range.
+func (v TypedValue) Convert(target Type) (TypedValue, error) {
+	value := v.Value
+	if str, ok := value.(string); ok && IsNumber(target) {
+		switch strings.ToUpper(strings.TrimSpace(str)) {
+		case "ON", "TRUE":
+			value = 1
+		case "OFF", "FALSE":
+			value = 0
+		}
+	}
+
+	converted, err := target.Convert(value)
+	if err != nil {
+		return TypedValue{}, err
+	}
+	return TypedValue{target, converted}, nil
+}
+
 // DefaultSessionConfig returns default values for session variables
 // TODO: allow integrators to specify defaults for their system variables
 func DefaultSessionConfig() map[string]TypedValue {
 	return map[string]TypedValue{
 		"auto_increment_increment": TypedValue{Int64, int64(1)},
-		"time_zone":                TypedValue{LongText, "SYSTEM"},
+		"auto_increment_offset":    TypedValue{Int64, int64(1)},
+		"time_zone":                TypedValue{LongText, SystemTimeZone},
 		"system_time_zone":         TypedValue{LongText, time.Now().UTC().Location().String()},
 		"max_allowed_packet":       TypedValue{Int32, math.MaxInt32},
 		"sql_mode":                 TypedValue{LongText, ""},
@@ -283,9 +704,9 @@ func DefaultSessionConfig() map[string]TypedValue {
 		"ndbinfo_version":          TypedValue{LongText, ""},
 		"sql_select_limit":         TypedValue{Int32, math.MaxInt32},
 		"transaction_isolation":    TypedValue{LongText, "READ UNCOMMITTED"},
-		"version":                  TypedValue{LongText, ""},
-		"version_comment":          TypedValue{LongText, ""},
-		"autocommit":               TypedValue{Int8, 0},
+		"version":                  TypedValue{LongText, Dialect_MySQL.Version()},
+		"version_comment":          TypedValue{LongText, Dialect_MySQL.VersionComment()},
+		"autocommit":               TypedValue{Int8, int8(0)},
 		"character_set_client":     TypedValue{LongText, Collation_Default.CharacterSet().String()},
 		"character_set_connection": TypedValue{LongText, Collation_Default.CharacterSet().String()},
 		"character_set_results":    TypedValue{LongText, Collation_Default.CharacterSet().String()},
@@ -293,7 +714,145 @@ func DefaultSessionConfig() map[string]TypedValue {
 		"tmpdir":                   TypedValue{LongText, GetTmpdirSessionVar()},
 		"local_infile":             TypedValue{Int8, int8(0)},
 		"secure_file_priv":         TypedValue{LongText, nil},
+		ReadOnlySessionVar:         TypedValue{Int8, int8(0)},
+		SuperReadOnlySessionVar:    TypedValue{Int8, int8(0)},
+		OptimizerSwitchSessionVar:  TypedValue{LongText, DefaultOptimizerSwitch()},
+		MaxExecutionTimeSessionVar: TypedValue{Int64, int64(0)},
+		WaitTimeoutSessionVar:      TypedValue{Int64, int64(28800)},
+		"interactive_timeout":      TypedValue{Int64, int64(28800)},
+		"max_prepared_stmt_count":  TypedValue{Int64, int64(16382)},
+		"default_storage_engine":   TypedValue{LongText, DefaultStorageEngine},
+		BinlogFormatSessionVar:     TypedValue{LongText, string(BinlogFormatRow)},
+		SQLSafeUpdatesSessionVar:   TypedValue{Int8, int8(0)},
+		LongQueryTimeSessionVar:    TypedValue{Float64, float64(10)},
+	}
+}
+
+// SetInExplicitTransaction implements the Session interface.
+func (s *BaseSession) SetInExplicitTransaction(inTx bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.explicitTx = inTx
+}
+
+// InExplicitTransaction implements the Session interface.
+func (s *BaseSession) InExplicitTransaction() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.explicitTx
+}
+
+// Status implements the Session interface.
+func (s *BaseSession) Status() *StatusCounters {
+	return s.status
+}
+
+// ResetStats implements the Session interface.
+func (s *BaseSession) ResetStats() {
+	s.status.Reset()
+}
+
+// Dialect implements the Session interface.
+func (s *BaseSession) Dialect() Dialect {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dialect
+}
+
+// SetDialect implements the Session interface.
+func (s *BaseSession) SetDialect(d Dialect) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.dialect
+	s.dialect = d
+	s.setDialectVar("version", prev.Version(), d.Version())
+	s.setDialectVar("version_comment", prev.VersionComment(), d.VersionComment())
+}
+
+// setDialectVar sets the session variable given, reporting the dialect of the session, to its value under a new
+// dialect, unless it was set to something other than its value under the previous one. The caller must hold s.mu.
+func (s *BaseSession) setDialectVar(name, prev, value string) {
+	if v, ok := s.config[name]; ok && v.Value != nil && v.Value != "" && v.Value != prev {
+		return
+	}
+	s.config[name] = TypedValue{LongText, value}
+}
+
+// SetReadOnly implements the Session interface.
+func (s *BaseSession) SetReadOnly(readOnly bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if readOnly {
+		s.config[ReadOnlySessionVar] = TypedValue{Int8, int8(1)}
+	} else {
+		s.config[ReadOnlySessionVar] = TypedValue{Int8, int8(0)}
+		s.config[SuperReadOnlySessionVar] = TypedValue{Int8, int8(0)}
+	}
+}
+
+// IsReadOnly implements the Session interface.
+func (s *BaseSession) IsReadOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, name := range []string{ReadOnlySessionVar, SuperReadOnlySessionVar} {
+		if v, ok := s.config[name]; ok && v.Value != nil {
+			if enabled, err := ConvertToBool(v.Value); err == nil && enabled {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// OptimizerSwitch implements the Session interface.
+func (s *BaseSession) OptimizerSwitch() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.optimizerFlags()
+}
+
+// SetOptimizerFlag implements the Session interface.
+func (s *BaseSession) SetOptimizerFlag(name string, on bool) error {
+	name = strings.ToLower(name)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	flags := s.optimizerFlags()
+	if _, ok := flags[name]; !ok {
+		return ErrUnknownOptimizerFlag.New(name)
 	}
+	flags[name] = on
+	s.config[OptimizerSwitchSessionVar] = TypedValue{LongText, formatOptimizerSwitch(flags)}
+	return nil
+}
+
+func (s *BaseSession) optimizerFlags() map[string]bool {
+	value, _ := s.config[OptimizerSwitchSessionVar].Value.(string)
+	return parseOptimizerSwitch(value)
+}
+
+// MarkKilled implements the Session interface.
+func (s *BaseSession) MarkKilled() {
+	atomic.StoreInt32(&s.killed, 1)
+}
+
+// IsKilled implements the Session interface.
+func (s *BaseSession) IsKilled() bool {
+	return atomic.LoadInt32(&s.killed) == 1
+}
+
+// ClearKilled implements the Session interface.
+func (s *BaseSession) ClearKilled() {
+	atomic.StoreInt32(&s.killed, 0)
+}
+
+// TemporaryTables implements the Session interface.
+func (s *BaseSession) TemporaryTables() *TemporaryTableRegistry {
+	return s.tempTables
+}
+
+// PendingChanges implements the Session interface.
+func (s *BaseSession) PendingChanges() *PendingChanges {
+	return s.pendingChanges
 }
 
 const (
@@ -310,6 +869,123 @@ func defaultLastQueryInfo() map[string]int64 {
 	}
 }
 
+// Reset implements the Session interface.
+func (s *BaseSession) Reset(ctx *Context) error {
+	if err := RollbackSessionTransaction(ctx, s.GetCurrentDatabase()); err != nil {
+		return err
+	}
+	s.tempTables.Clear()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// the variables reporting the dialect go with it
+	config := DefaultSessionConfig()
+	config["version"] = s.config["version"]
+	config["version_comment"] = s.config["version_comment"]
+	s.config = config
+	s.warnings = nil
+	s.warncnt = 0
+	s.locks = make(map[string]bool)
+	s.queriedDb = ""
+	// strict FOUND_ROWS() semantics are a property of the session, not of its state
+	s.lastQueryInfo = defaultLastQueryInfo()
+	if s.strictFoundRows {
+		s.lastQueryInfo[FoundRows] = 0
+	}
+	s.resourceGroup = ""
+	s.prepared = make(map[uint32]string)
+	return nil
+}
+
+// ChangeUser implements the Session interface. The attributes of the client given are copied.
+func (s *BaseSession) ChangeUser(ctx *Context, client Client) error {
+	if err := s.Reset(ctx); err != nil {
+		return err
+	}
+
+	attrs := make(map[string]string, len(client.Attributes))
+	for k, v := range client.Attributes {
+		attrs[k] = v
+	}
+	client.Attributes = attrs
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+	return nil
+}
+
+// AddPreparedStatement implements the Session interface.
+func (s *BaseSession) AddPreparedStatement(id uint32, query string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.prepared[id]; !ok {
+		var max int64
+		if v, err := Int64.Convert(s.config[MaxPreparedStmtCountSessionVar].Value); err == nil {
+			max = v.(int64)
+		}
+		if int64(len(s.prepared)) >= max {
+			return ErrMaxPreparedStmtCountReached.New(max)
+		}
+	}
+	s.prepared[id] = query
+	return nil
+}
+
+// RemovePreparedStatement implements the Session interface.
+func (s *BaseSession) RemovePreparedStatement(id uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prepared, id)
+}
+
+// PreparedStatements implements the Session interface.
+func (s *BaseSession) PreparedStatements() map[uint32]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	prepared := make(map[uint32]string, len(s.prepared))
+	for id, query := range s.prepared {
+		prepared[id] = query
+	}
+	return prepared
+}
+
+// SetLastActivity implements the Session interface.
+func (s *BaseSession) SetLastActivity(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = t
+}
+
+// LastActivity implements the Session interface.
+func (s *BaseSession) LastActivity() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastActivity
+}
+
+// SetStrictFoundRows sets whether FOUND_ROWS() follows MySQL strictly for this session. By default, FOUND_ROWS() returns
+// 1 before the session issues any SELECT, which is what `SELECT FOUND_ROWS()` itself would count. With strict
+// semantics it returns 0 instead, as MySQL does. It resets the last FOUND_ROWS() value, so it should be called before
+// the session runs any query.
+func (s *BaseSession) SetStrictFoundRows(strict bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strictFoundRows = strict
+	if strict {
+		s.lastQueryInfo[FoundRows] = 0
+	} else {
+		s.lastQueryInfo[FoundRows] = 1
+	}
+}
+
+// StrictFoundRows returns whether FOUND_ROWS() follows MySQL strictly for this session. See SetStrictFoundRows.
+func (s *BaseSession) StrictFoundRows() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.strictFoundRows
+}
+
 func (s *BaseSession) SetLastQueryInfo(key string, value int64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -355,13 +1031,19 @@ func NewSession(server, client, user string, id uint32) Session {
 		id:   id,
 		addr: server,
 		client: Client{
-			Address: client,
-			User:    user,
+			Address:    client,
+			User:       user,
+			Attributes: map[string]string{},
 		},
-		config:        DefaultSessionConfig(),
-		lastQueryInfo: defaultLastQueryInfo(),
-		mu:            &sync.RWMutex{},
-		locks:         make(map[string]bool),
+		config:         DefaultSessionConfig(),
+		lastQueryInfo:  defaultLastQueryInfo(),
+		mu:             &sync.RWMutex{},
+		locks:          make(map[string]bool),
+		prepared:       make(map[uint32]string),
+		tempTables:     NewTemporaryTableRegistry(),
+		pendingChanges: NewPendingChanges(),
+		lastActivity:   time.Now(),
+		status:         NewStatusCounters(),
 	}
 }
 
@@ -371,11 +1053,17 @@ var autoSessionIDs uint32 = 1
 // NewBaseSession creates a new empty session.
 func NewBaseSession() Session {
 	return &BaseSession{
-		id:            atomic.AddUint32(&autoSessionIDs, 1),
-		config:        DefaultSessionConfig(),
-		mu:            &sync.RWMutex{},
-		locks:         make(map[string]bool),
-		lastQueryInfo: defaultLastQueryInfo(),
+		id:             atomic.AddUint32(&autoSessionIDs, 1),
+		client:         Client{Attributes: map[string]string{}},
+		config:         DefaultSessionConfig(),
+		mu:             &sync.RWMutex{},
+		locks:          make(map[string]bool),
+		prepared:       make(map[uint32]string),
+		lastQueryInfo:  defaultLastQueryInfo(),
+		tempTables:     NewTemporaryTableRegistry(),
+		pendingChanges: NewPendingChanges(),
+		lastActivity:   time.Now(),
+		status:         NewStatusCounters(),
 	}
 }
 
@@ -389,8 +1077,21 @@ type Context struct {
 	pid       uint64
 	query     string
 	queryTime time.Time
+	nowFunc   func() time.Time
 	tracer    opentracing.Tracer
 	rootSpan  opentracing.Span
+	deferred  *deferredFuncs
+	rand      *lockedRand
+	metadata  *contextMetadata
+	cancel    *cancellation
+	// the user whose privileges the statement runs with, if it isn't the user of the session. See EffectiveUser.
+	effectiveUser *string
+	// the store of the temporary files of the statement, if not the default one. See SpillStore.
+	spillStore SpillStore
+	// the binlog event of the DML statement run with the context, if it's logged. See BinlogEvent.
+	binlogEvent *BinlogEvent
+	// the results of the memoized expressions of the current row, if enabled. See RowExpressionCache.
+	exprCache *RowExpressionCache
 }
 
 // ContextOption is a function to configure the context.
@@ -443,6 +1144,23 @@ func WithMemoryManager(m *MemoryManager) ContextOption {
 	}
 }
 
+// WithSpillStore makes the context, and the contexts derived from it, keep temporary files in the store given instead
+// of the directory of the tmpdir session variable.
+func WithSpillStore(s SpillStore) ContextOption {
+	return func(ctx *Context) {
+		ctx.spillStore = s
+	}
+}
+
+// WithRowExpressionCache enables the memoization of expressions for the context, and the contexts derived from it.
+// The cache given is the one of the rows the context evaluates, and the nodes evaluating memoized expressions on rows
+// of their own derive a context with a cache of their own. See RowExpressionCache.
+func WithRowExpressionCache(c *RowExpressionCache) ContextOption {
+	return func(ctx *Context) {
+		ctx.exprCache = c
+	}
+}
+
 // WithRootSpan sets the root span of the context.
 func WithRootSpan(s opentracing.Span) ContextOption {
 	return func(ctx *Context) {
@@ -450,6 +1168,22 @@ func WithRootSpan(s opentracing.Span) ContextOption {
 	}
 }
 
+// WithRandSeed makes the random numbers generated for the context, such as those returned by RAND() without an
+// argument, come from a source seeded with the value given. Contexts derived from this one share the same source.
+func WithRandSeed(seed int64) ContextOption {
+	return func(ctx *Context) {
+		ctx.rand = &lockedRand{r: rand.New(rand.NewSource(seed))}
+	}
+}
+
+// WithNowFunc makes the context read the current time from the function given, for both its query time and Now.
+// Contexts derived from this one use the same function.
+func WithNowFunc(nowFunc func() time.Time) ContextOption {
+	return func(ctx *Context) {
+		ctx.nowFunc = nowFunc
+	}
+}
+
 var ctxNowFunc = time.Now
 var ctxNowFuncMutex = &sync.Mutex{}
 
@@ -475,10 +1209,11 @@ func NewContext(
 	ctx context.Context,
 	opts ...ContextOption,
 ) *Context {
-	c := &Context{ctx, NewBaseSession(), nil, nil, nil, 0, "", ctxNowFunc(), opentracing.NoopTracer{}, nil}
+	c := &Context{ctx, NewBaseSession(), nil, nil, nil, 0, "", time.Time{}, ctxNowFunc, opentracing.NoopTracer{}, nil, &deferredFuncs{}, nil, &contextMetadata{}, &cancellation{}, nil, nil, nil, nil}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.queryTime = c.nowFunc()
 
 	if c.IndexRegistry == nil {
 		c.IndexRegistry = NewIndexRegistry()
@@ -515,6 +1250,234 @@ func (c *Context) QueryTime() time.Time {
 	return c.queryTime
 }
 
+// StartStatement records the current time as the start of the statement being run with this context, as returned by
+// StatementStartTime. The engine calls it when it dispatches a statement, so that a context used for many statements
+// reports the start of the current one. Statements run by a stored procedure are part of the CALL statement, and
+// don't start a new one. The start is shared with every context derived from this one. It's also recorded as the
+// LastActivity of the session. It's read from the system clock rather than the one of WithNowFunc, which is the time
+// reported by functions like NOW() and may be fixed, so that it can be used to measure how long the statement runs.
+func (c *Context) StartStatement() {
+	now := time.Now()
+	if c.Session != nil {
+		c.SetLastActivity(now)
+	}
+	atomic.StoreInt64(&c.metadata.rowsExamined, 0)
+	c.metadata.mu.Lock()
+	defer c.metadata.mu.Unlock()
+	c.metadata.statementStart = now
+	c.metadata.analyzed = nil
+}
+
+// AddRowsExamined adds the number given to the rows read from tables by the current statement, as returned by
+// RowsExamined. TableRowIter counts every row it reads. The count is shared with every context derived from this one,
+// and reset by StartStatement.
+func (c *Context) AddRowsExamined(n int64) {
+	atomic.AddInt64(&c.metadata.rowsExamined, n)
+}
+
+// RowsExamined returns the number of rows read from tables by the current statement so far.
+func (c *Context) RowsExamined() int64 {
+	return atomic.LoadInt64(&c.metadata.rowsExamined)
+}
+
+// AnalyzedNode returns the result of the analysis of the subtree given recorded with SetAnalyzedNode during the current
+// statement, if any. The analyzer uses it to analyze the subtrees that don't depend on their surroundings, such as the
+// definition of a view, once per statement no matter how many times they're referenced. Subtrees are compared by
+// identity, and the ones of a type that can't be compared are never found.
+func (c *Context) AnalyzedNode(n Node) (Node, bool) {
+	if !reflect.TypeOf(n).Comparable() {
+		return nil, false
+	}
+	c.metadata.mu.RLock()
+	defer c.metadata.mu.RUnlock()
+	analyzed, ok := c.metadata.analyzed[n]
+	return analyzed, ok
+}
+
+// SetAnalyzedNode records the result of the analysis of the subtree given, returned by AnalyzedNode until the next
+// call to StartStatement. It's shared with every context derived from this one.
+func (c *Context) SetAnalyzedNode(n, analyzed Node) {
+	if !reflect.TypeOf(n).Comparable() {
+		return
+	}
+	c.metadata.mu.Lock()
+	defer c.metadata.mu.Unlock()
+	if c.metadata.analyzed == nil {
+		c.metadata.analyzed = make(map[Node]Node)
+	}
+	c.metadata.analyzed[n] = analyzed
+}
+
+// StatementStartTime returns the time the current statement started, as recorded by StartStatement. Unlike QueryTime,
+// which is the creation time of the context, it changes with every statement run with the context. Returns QueryTime
+// if no statement was started.
+func (c *Context) StatementStartTime() time.Time {
+	c.metadata.mu.RLock()
+	defer c.metadata.mu.RUnlock()
+	if c.metadata.statementStart.IsZero() {
+		return c.queryTime
+	}
+	return c.metadata.statementStart
+}
+
+// Now returns the current time. Unlike QueryTime, which stays the same for the whole query, it changes as the query
+// runs, such as for SYSDATE().
+func (c *Context) Now() time.Time {
+	return c.nowFunc()
+}
+
+// MaxExecutionTime returns the execution timeout of SELECT statements given by the max_execution_time session
+// variable, in milliseconds. Returns 0, for no timeout, if the variable is unset, zero, or not a positive number.
+func (c *Context) MaxExecutionTime() time.Duration {
+	if c.Session == nil {
+		return 0
+	}
+	_, val := c.Get(MaxExecutionTimeSessionVar)
+	if val == nil {
+		return 0
+	}
+	ms, err := Int64.Convert(val)
+	if err != nil || ms.(int64) <= 0 {
+		return 0
+	}
+	return time.Duration(ms.(int64)) * time.Millisecond
+}
+
+// ResultsCharacterSet returns the character set the string values of results are sent to the client in, given by the
+// character_set_results session variable, and whether they're converted to it. As in MySQL, setting the variable to
+// NULL turns the conversion off, so that values are sent in the character set of their column. A variable that isn't
+// set at all, which reads as a NULL of type Null rather than of the type of the variable, or that doesn't name a known
+// character set, gives the default character set instead.
+func (c *Context) ResultsCharacterSet() (CharacterSet, bool) {
+	if c.Session == nil {
+		return Collation_Default.CharacterSet(), true
+	}
+	typ, val := c.Get(CharacterSetResultsSessionVar)
+	if val == nil {
+		if typ == Null {
+			return Collation_Default.CharacterSet(), true
+		}
+		return "", false
+	}
+	cs, err := ParseCharacterSet(fmt.Sprint(val))
+	if err != nil {
+		return Collation_Default.CharacterSet(), true
+	}
+	return cs, true
+}
+
+// HasSQLMode returns whether the comma-separated sql_mode session variable includes the mode given, compared
+// case-insensitively.
+func (c *Context) HasSQLMode(mode string) bool {
+	if c.Session == nil {
+		return false
+	}
+	_, val := c.Get(SQLModeSessionVar)
+	s, ok := val.(string)
+	if !ok {
+		return false
+	}
+	for _, m := range strings.Split(s, ",") {
+		if strings.EqualFold(strings.TrimSpace(m), mode) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dialect returns the flavor of MySQL the session of the context emulates, or Dialect_MySQL if there is no session.
+func (c *Context) Dialect() Dialect {
+	if c.Session == nil {
+		return Dialect_MySQL
+	}
+	return c.Session.Dialect()
+}
+
+// InExplicitTransaction returns whether the statement of the context runs in a transaction started by BEGIN or START
+// TRANSACTION, which is the case for the statements of a stored procedure called from such a transaction too. Returns
+// false if there is no session.
+func (c *Context) InExplicitTransaction() bool {
+	if c.Session == nil {
+		return false
+	}
+	return c.Session.InExplicitTransaction()
+}
+
+// LongQueryTime returns the duration above which a statement is slow, given in fractional seconds by the
+// long_query_time session variable. Returns the default of 10 seconds if the variable is unset or not a number, and 0,
+// making every statement slow, if it's negative.
+func (c *Context) LongQueryTime() time.Duration {
+	if c.Session == nil {
+		return 10 * time.Second
+	}
+	_, val := c.Get(LongQueryTimeSessionVar)
+	if val == nil {
+		return 10 * time.Second
+	}
+	secs, err := Float64.Convert(val)
+	if err != nil {
+		return 10 * time.Second
+	}
+	if secs.(float64) <= 0 {
+		return 0
+	}
+	return time.Duration(secs.(float64) * float64(time.Second))
+}
+
+// BinlogFormat returns the format DML statements are logged in, given by the binlog_format session variable. Returns
+// BinlogFormatRow, the default, if the variable is unset or isn't a known format.
+func (c *Context) BinlogFormat() BinlogFormat {
+	if c.Session == nil {
+		return BinlogFormatRow
+	}
+	_, val := c.Get(BinlogFormatSessionVar)
+	s, _ := val.(string)
+	switch format := BinlogFormat(strings.ToUpper(s)); format {
+	case BinlogFormatStatement, BinlogFormatRow, BinlogFormatMixed:
+		return format
+	default:
+		return BinlogFormatRow
+	}
+}
+
+// AutoIncrementStep returns the interval between the values generated for AUTO_INCREMENT columns and the value they
+// start from, given by the auto_increment_increment and auto_increment_offset session variables. Both are 1, the
+// default, if the variables are unset or not positive numbers.
+func (c *Context) AutoIncrementStep() (increment, offset uint64) {
+	increment, offset = 1, 1
+	if c.Session == nil {
+		return increment, offset
+	}
+	if _, val := c.Get(AutoIncrementIncrementSessionVar); val != nil {
+		if v, err := Int64.Convert(val); err == nil && v.(int64) > 0 {
+			increment = uint64(v.(int64))
+		}
+	}
+	if _, val := c.Get(AutoIncrementOffsetSessionVar); val != nil {
+		if v, err := Int64.Convert(val); err == nil && v.(int64) > 0 {
+			offset = uint64(v.(int64))
+		}
+	}
+	return increment, offset
+}
+
+// WithStatementTimeout returns a context for a statement that must finish within the timeout given. Once the timeout
+// elapses, the context is done and its CancellationError is ErrQueryTimeout. The function returned releases the
+// resources of the timeout, and must be called once the statement is done.
+func (c *Context) WithStatementTimeout(timeout time.Duration) (*Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(c.Context, timeout)
+	return c.WithContext(ctx), cancel
+}
+
+// CheckInterrupted returns ErrQueryInterrupted if the session of the context was killed. Long operations call it
+// between steps, in addition to checking for the cancellation of the context.
+func (c *Context) CheckInterrupted() error {
+	if c.Session != nil && c.IsKilled() {
+		return ErrQueryInterrupted.New()
+	}
+	return nil
+}
+
 // Span creates a new tracing span with the given context.
 // It will return the span and a new context that should be passed to all
 // children of this span.
@@ -538,9 +1501,59 @@ func (c *Context) Span(
 		pid:           c.Pid(),
 		query:         c.Query(),
 		queryTime:     c.queryTime,
+		nowFunc:       c.nowFunc,
 		tracer:        c.tracer,
 		rootSpan:      c.rootSpan,
+		deferred:      c.deferred,
+		rand:          c.rand,
+		metadata:      c.metadata,
+		cancel:        c.cancel,
+		effectiveUser: c.effectiveUser,
+		spillStore:    c.spillStore,
+		binlogEvent:   c.binlogEvent,
+		exprCache:     c.exprCache,
+	}
+}
+
+// SpillStore returns the store operators keep their temporary files in: the one given with WithSpillStore, or else
+// one rooted at the directory of the tmpdir session variable.
+func (c *Context) SpillStore() SpillStore {
+	if c.spillStore != nil {
+		return c.spillStore
+	}
+	var dir string
+	if c.Session != nil {
+		if _, v := c.Get("tmpdir"); v != nil {
+			dir, _ = v.(string)
+		}
 	}
+	return NewOSSpillStore(dir)
+}
+
+// BinlogEvent returns the binlog event the changes of the DML statement run with the context are recorded in, given
+// with WithBinlogEvent, or nil if the statement isn't logged.
+func (c *Context) BinlogEvent() *BinlogEvent {
+	return c.binlogEvent
+}
+
+// WithBinlogEvent returns a copy of this context whose DML statement records its changes in the binlog event given.
+func (c *Context) WithBinlogEvent(event *BinlogEvent) *Context {
+	nc := c.WithContext(c.Context)
+	nc.binlogEvent = event
+	return nc
+}
+
+// RowExpressionCache returns the cache of the results of memoized expressions for the rows the context evaluates, or
+// nil if memoization is disabled for the context.
+func (c *Context) RowExpressionCache() *RowExpressionCache {
+	return c.exprCache
+}
+
+// WithRowExpressionCache returns a new context evaluating memoized expressions with the cache given.
+func (c *Context) WithRowExpressionCache(cache *RowExpressionCache) *Context {
+	nc := c.WithContext(c.Context)
+	nc.exprCache = cache
+	return nc
 }
 
 // NewSubContext creates a new sub-context with the current context as parent. Returns the resulting context.CancelFunc
@@ -556,11 +1569,63 @@ func (c *Context) NewSubContext() (*Context, context.CancelFunc) {
 		pid:           c.Pid(),
 		query:         c.Query(),
 		queryTime:     c.queryTime,
+		nowFunc:       c.nowFunc,
 		tracer:        c.tracer,
 		rootSpan:      c.rootSpan,
+		deferred:      c.deferred,
+		rand:          c.rand,
+		metadata:      c.metadata,
+		cancel:        c.cancel,
+		effectiveUser: c.effectiveUser,
+		spillStore:    c.spillStore,
+		binlogEvent:   c.binlogEvent,
+		exprCache:     c.exprCache,
 	}, cancelFunc
 }
 
+// NewSubContextWithCancelReason creates a new sub-context with the current context as parent, like NewSubContext, but
+// the function returned to cancel it takes the reason of the cancellation, as reported by CancellationReason and
+// CancellationError of the new context and of the contexts derived from it.
+func (c *Context) NewSubContextWithCancelReason() (*Context, CancelWithReasonFunc) {
+	subCtx, cancelFunc := c.NewSubContext()
+	cancel := &cancellation{parent: c.cancel}
+	subCtx.cancel = cancel
+	return subCtx, func(reason CancelReason) {
+		cancel.setReason(reason)
+		cancelFunc()
+	}
+}
+
+// CancellationReason returns the reason this context was cancelled for, given to the CancelWithReasonFunc that
+// cancelled it or one of its parents. Returns CancelReason_None if the context wasn't cancelled with a reason.
+func (c *Context) CancellationReason() CancelReason {
+	return c.cancel.getReason()
+}
+
+// CancellationError returns the error a statement run with this context fails with once the context is done, which
+// depends on its CancellationReason: ErrQueryTimeout, ErrQueryInterrupted or ErrConnectionClosed. Without a reason, it's
+// ErrQueryTimeout if the deadline of the context passed, or else the error of the underlying context.Context, such as
+// context.Canceled. Returns nil if the context isn't done.
+func (c *Context) CancellationError() error {
+	err := c.Err()
+	if err == nil {
+		return nil
+	}
+	switch c.CancellationReason() {
+	case CancelReason_Timeout:
+		return ErrQueryTimeout.New()
+	case CancelReason_Killed:
+		return ErrQueryInterrupted.New()
+	case CancelReason_ConnectionClosed:
+		return ErrConnectionClosed.New()
+	default:
+		if err == context.DeadlineExceeded {
+			return ErrQueryTimeout.New()
+		}
+		return err
+	}
+}
+
 func (c *Context) WithCurrentDB(db string) *Context {
 	c.SetCurrentDatabase(db)
 	return c
@@ -577,32 +1642,344 @@ func (c *Context) WithContext(ctx context.Context) *Context {
 		pid:           c.Pid(),
 		query:         c.Query(),
 		queryTime:     c.queryTime,
+		nowFunc:       c.nowFunc,
 		tracer:        c.tracer,
 		rootSpan:      c.rootSpan,
+		deferred:      c.deferred,
+		rand:          c.rand,
+		metadata:      c.metadata,
+		cancel:        c.cancel,
+		effectiveUser: c.effectiveUser,
+		spillStore:    c.spillStore,
+		binlogEvent:   c.binlogEvent,
+		exprCache:     c.exprCache,
 	}
 }
 
+// EffectiveUser returns the user whose privileges the statement runs with, as returned by CURRENT_USER(). This is the
+// user of the session, unless the statement runs in the body of a SQL SECURITY DEFINER procedure, which runs with the
+// privileges of its definer.
+func (c *Context) EffectiveUser() string {
+	if c.effectiveUser != nil {
+		return *c.effectiveUser
+	}
+	if c.Session == nil {
+		return ""
+	}
+	return c.Client().User
+}
+
+// WithEffectiveUser returns a copy of this context whose statements run with the privileges of the user given, such as
+// for the body of a SQL SECURITY DEFINER procedure. The effective user of this context is left untouched, so it's
+// restored once the statements run with the copy are done, whether they succeed or not.
+func (c *Context) WithEffectiveUser(user string) *Context {
+	nc := c.WithContext(c.Context)
+	nc.effectiveUser = &user
+	return nc
+}
+
 // RootSpan returns the root span, if any.
 func (c *Context) RootSpan() opentracing.Span {
 	return c.rootSpan
 }
 
-// Error adds an error as warning to the session.
+// RandFloat64 returns a pseudo-random number in [0.0,1.0) from the random source of this context, as set with
+// WithRandSeed, or from the global source if the context has none.
+func (c *Context) RandFloat64() float64 {
+	if c.rand == nil {
+		return rand.Float64()
+	}
+	return c.rand.Float64()
+}
+
+// lockedRand is a random source that's safe to share between the goroutines executing a query.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+// SetMetadata stores a request-scoped value under the key given, for integrators to pass data such as a tenant ID to
+// their own tables and functions. Keys are plain strings, so they never collide with the keys of the underlying
+// context.Context. Metadata is shared by every context derived from this one.
+func (c *Context) SetMetadata(key string, val interface{}) {
+	c.metadata.set(key, val)
+}
+
+// Metadata returns the value stored with SetMetadata under the key given, and whether there is one.
+func (c *Context) Metadata(key string) (interface{}, bool) {
+	return c.metadata.get(key)
+}
+
+// contextMetadata is the metadata shared between a context and all the contexts derived from it.
+type contextMetadata struct {
+	mu             sync.RWMutex
+	values         map[string]interface{}
+	statementStart time.Time
+	// rowsExamined is accessed atomically.
+	rowsExamined int64
+	// the analyzed subtrees of the current statement, by the subtree they were analyzed from. See AnalyzedNode.
+	analyzed map[Node]Node
+	// the WarningBuffers holding warnings, flushed before a warning is given to the session directly
+	warningBuffers []*WarningBuffer
+}
+
+func (m *contextMetadata) set(key string, val interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	m.values[key] = val
+}
+
+func (m *contextMetadata) get(key string) (interface{}, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	val, ok := m.values[key]
+	return val, ok
+}
+
+// CancelReason is the reason a context was cancelled for, which determines the error of the statement it was running.
+type CancelReason byte
+
+const (
+	// CancelReason_None is the reason of contexts that weren't cancelled or that were cancelled without a reason, such
+	// as when their statement finished.
+	CancelReason_None CancelReason = iota
+	// CancelReason_Timeout is the reason of contexts cancelled because their statement ran for too long.
+	CancelReason_Timeout
+	// CancelReason_Killed is the reason of contexts cancelled by a KILL statement.
+	CancelReason_Killed
+	// CancelReason_ConnectionClosed is the reason of contexts cancelled because their client closed the connection.
+	CancelReason_ConnectionClosed
+)
+
+// String returns a description of the reason.
+func (r CancelReason) String() string {
+	switch r {
+	case CancelReason_None:
+		return "none"
+	case CancelReason_Timeout:
+		return "timeout"
+	case CancelReason_Killed:
+		return "killed"
+	case CancelReason_ConnectionClosed:
+		return "connection closed"
+	default:
+		return "unknown"
+	}
+}
+
+// CancelWithReasonFunc cancels a context for the reason given. Only the first reason a context is cancelled for is
+// kept.
+type CancelWithReasonFunc func(reason CancelReason)
+
+// cancellation holds the reason a context and the contexts derived from it were cancelled for. Contexts created with
+// NewSubContextWithCancelReason have their own, and inherit the reason of their parent when they have none.
+type cancellation struct {
+	mu     sync.Mutex
+	reason CancelReason
+	parent *cancellation
+}
+
+func (c *cancellation) setReason(reason CancelReason) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reason == CancelReason_None {
+		c.reason = reason
+	}
+}
+
+func (c *cancellation) getReason() CancelReason {
+	for ; c != nil; c = c.parent {
+		c.mu.Lock()
+		reason := c.reason
+		c.mu.Unlock()
+		if reason != CancelReason_None {
+			return reason
+		}
+	}
+	return CancelReason_None
+}
+
+// Defer registers a cleanup function to be run when the query associated with this context finishes, either because
+// its top-level iterator was closed or because the context was cancelled. Deferred functions are shared by every
+// context derived from this one and run in LIFO order.
+func (c *Context) Defer(fn func() error) {
+	c.deferred.push(fn)
+}
+
+// RunDeferred runs and forgets all the cleanup functions registered with Defer, most recent first. A function that
+// returns an error or panics does not prevent the rest from running; all the errors are aggregated in the result.
+func (c *Context) RunDeferred() error {
+	return c.deferred.run()
+}
+
+// deferredFuncs is a stack of cleanup functions shared between a context and all the contexts derived from it.
+type deferredFuncs struct {
+	mu    sync.Mutex
+	funcs []func() error
+}
+
+func (d *deferredFuncs) push(fn func() error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.funcs = append(d.funcs, fn)
+}
+
+func (d *deferredFuncs) run() error {
+	d.mu.Lock()
+	funcs := d.funcs
+	d.funcs = nil
+	d.mu.Unlock()
+
+	var errs []error
+	for i := len(funcs) - 1; i >= 0; i-- {
+		if err := runDeferredFunc(funcs[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return ErrDeferredCleanup.New(len(errs), errs)
+	}
+}
+
+func runDeferredFunc(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic in deferred cleanup: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// Error adds an error as warning to the session, with the SQLSTATE of its code, after the warnings held by the
+// WarningBuffers of the context.
 func (c *Context) Error(code int, msg string, args ...interface{}) {
+	c.flushWarningBuffers()
 	c.Session.Warn(&Warning{
-		Level:   "Error",
-		Code:    code,
-		Message: fmt.Sprintf(msg, args...),
+		Level:    "Error",
+		Code:     code,
+		Message:  fmt.Sprintf(msg, args...),
+		SQLState: SQLStateForCode(code),
 	})
 }
 
-// Warn adds a warning to the session.
+// Warn adds a warning to the session, with the SQLSTATE of its code, after the warnings held by the WarningBuffers of
+// the context.
 func (c *Context) Warn(code int, msg string, args ...interface{}) {
+	c.flushWarningBuffers()
 	c.Session.Warn(&Warning{
-		Level:   "Warning",
-		Code:    code,
-		Message: fmt.Sprintf(msg, args...),
+		Level:    "Warning",
+		Code:     code,
+		Message:  fmt.Sprintf(msg, args...),
+		SQLState: SQLStateForCode(code),
+	})
+}
+
+// DefaultWarningBufferSize is the number of warnings a WarningBuffer holds before handing them to the session.
+const DefaultWarningBufferSize = 256
+
+// WarningBuffer collects the warnings of a single operator and hands them to the session in batches, so that an
+// operator emitting a warning for every row doesn't need to take the session lock each time. Warnings keep the order
+// in which they were emitted, including among the ones given to the session with Context.Warn or Context.Error, which
+// flush the buffers of their context first. Operators must call Flush once they are done, usually when their iterator
+// is closed.
+type WarningBuffer struct {
+	ctx  *Context
+	size int
+
+	mu       sync.Mutex
+	warnings []*Warning
+}
+
+// NewWarningBuffer returns a WarningBuffer for the session of the context given that flushes every size warnings. A
+// size lower than 1 means DefaultWarningBufferSize.
+func NewWarningBuffer(ctx *Context, size int) *WarningBuffer {
+	if size < 1 {
+		size = DefaultWarningBufferSize
+	}
+	return &WarningBuffer{ctx: ctx, size: size}
+}
+
+// Warn adds a warning to the buffer, with the SQLSTATE of its code, flushing it if it's full.
+func (b *WarningBuffer) Warn(code int, msg string, args ...interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.warnings) == 0 {
+		b.ctx.metadata.addWarningBuffer(b)
+	}
+	b.warnings = append(b.warnings, &Warning{
+		Level:    "Warning",
+		Code:     code,
+		Message:  fmt.Sprintf(msg, args...),
+		SQLState: SQLStateForCode(code),
 	})
+	if len(b.warnings) >= b.size {
+		b.flush()
+	}
+}
+
+// Flush hands all the buffered warnings to the session.
+func (b *WarningBuffer) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.flush()
+}
+
+// flush hands all the buffered warnings to the session. The caller must hold b.mu.
+func (b *WarningBuffer) flush() {
+	if len(b.warnings) == 0 {
+		return
+	}
+	b.ctx.Session.WarnBatch(b.warnings)
+	b.warnings = nil
+	b.ctx.metadata.removeWarningBuffer(b)
+}
+
+// flushWarningBuffers flushes the WarningBuffers of the context holding warnings, in the order they got their first
+// one.
+func (c *Context) flushWarningBuffers() {
+	c.metadata.mu.RLock()
+	if len(c.metadata.warningBuffers) == 0 {
+		c.metadata.mu.RUnlock()
+		return
+	}
+	buffers := append([]*WarningBuffer(nil), c.metadata.warningBuffers...)
+	c.metadata.mu.RUnlock()
+
+	for _, b := range buffers {
+		b.Flush()
+	}
+}
+
+func (m *contextMetadata) addWarningBuffer(b *WarningBuffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warningBuffers = append(m.warningBuffers, b)
+}
+
+func (m *contextMetadata) removeWarningBuffer(b *WarningBuffer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, other := range m.warningBuffers {
+		if other == b {
+			m.warningBuffers = append(m.warningBuffers[:i], m.warningBuffers[i+1:]...)
+			return
+		}
+	}
 }
 
 // NewSpanIter creates a RowIter executed in the given span.
@@ -613,6 +1990,9 @@ func NewSpanIter(span opentracing.Span, iter RowIter) RowIter {
 	if (span.Tracer() == opentracing.NoopTracer{}) {
 		return iter
 	} else {
+		if ci, ok := iter.(ConditionStatsIter); ok {
+			ci.EnableConditionStats()
+		}
 		return &spanIter{
 			span: span,
 			iter: iter,
@@ -620,6 +2000,19 @@ func NewSpanIter(span opentracing.Span, iter RowIter) RowIter {
 	}
 }
 
+// ConditionStatsIter is a RowIter that skips the rows of its child not matching a condition, such as the iterator of
+// a filter, and can report how selective the condition was. When run in a traced span, the stats are logged along with
+// the timings of the span as rows_passed, rows_filtered and eval_time.
+type ConditionStatsIter interface {
+	RowIter
+	// EnableConditionStats makes the iterator collect its stats. It's only called when tracing is enabled, so that the
+	// stats cost nothing otherwise.
+	EnableConditionStats()
+	// ConditionStats returns the number of rows that matched the condition and were passed on, the number of rows
+	// that were skipped, and the total time spent evaluating the condition.
+	ConditionStats() (passed, filtered int, evalTime time.Duration)
+}
+
 type spanIter struct {
 	span  opentracing.Span
 	iter  RowIter
@@ -668,17 +2061,27 @@ func (i *spanIter) finish() {
 		avg = i.total / time.Duration(i.count)
 	}
 
+	fields := []log.Field{
+		log.Int("rows", i.count),
+		log.String("total_time", i.total.String()),
+		log.String("max_time", i.max.String()),
+		log.String("min_time", i.min.String()),
+		log.String("avg_time", avg.String()),
+	}
+	if ci, ok := i.iter.(ConditionStatsIter); ok {
+		passed, filtered, evalTime := ci.ConditionStats()
+		fields = append(fields,
+			log.Int("rows_passed", passed),
+			log.Int("rows_filtered", filtered),
+			log.String("eval_time", evalTime.String()),
+		)
+	}
+
 	i.span.FinishWithOptions(opentracing.FinishOptions{
 		LogRecords: []opentracing.LogRecord{
 			{
 				Timestamp: time.Now(),
-				Fields: []log.Field{
-					log.Int("rows", i.count),
-					log.String("total_time", i.total.String()),
-					log.String("max_time", i.max.String()),
-					log.String("min_time", i.min.String()),
-					log.String("avg_time", avg.String()),
-				},
+				Fields:    fields,
 			},
 		},
 	})
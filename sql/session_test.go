@@ -16,10 +16,16 @@ package sql
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dolthub/vitess/go/mysql"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/src-d/go-errors.v1"
 )
 
 func TestSessionConfig(t *testing.T) {
@@ -50,6 +56,208 @@ func TestSessionConfig(t *testing.T) {
 	require.Equal(1, sess.Warnings()[2].Code)
 }
 
+func TestSessionClientAttributes(t *testing.T) {
+	require := require.New(t)
+
+	for _, sess := range []Session{NewSession("foo", "baz", "bar", 1), NewBaseSession()} {
+		require.NotNil(sess.Client().Attributes)
+		require.Empty(sess.Client().Attributes)
+
+		attrs := map[string]string{"program_name": "mysql", "_client_name": "libmysql"}
+		sess.SetClientAttributes(attrs)
+		require.Equal(attrs, sess.Client().Attributes)
+
+		// the attributes are copied
+		attrs["program_name"] = "other"
+		require.Equal("mysql", sess.Client().Attributes["program_name"])
+
+		sess.SetClientAttributes(nil)
+		require.NotNil(sess.Client().Attributes)
+		require.Empty(sess.Client().Attributes)
+	}
+
+	client := NewSession("foo", "baz", "bar", 1).Client()
+	require.Equal("bar", client.User)
+	require.Equal("baz", client.Address)
+}
+
+func TestSessionChangeUser(t *testing.T) {
+	require := require.New(t)
+
+	dirty := func(sess Session) {
+		require.NoError(sess.Set(context.Background(), "v", Int64, int64(1)))
+		sess.Warn(&Warning{Code: 1})
+		sess.SetInExplicitTransaction(true)
+		sess.SetClientAttributes(map[string]string{"program_name": "mysql"})
+	}
+	requireClean := func(sess Session) {
+		typ, v := sess.Get("v")
+		require.Equal(Null, typ)
+		require.Nil(v)
+		require.Equal(uint16(0), sess.WarningCount())
+		require.False(sess.InExplicitTransaction())
+	}
+
+	// Reset keeps the client, including its user
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	dirty(sess)
+	require.NoError(sess.Reset(ctx))
+	requireClean(sess)
+	require.Equal("bar", sess.Client().User)
+	require.Equal(map[string]string{"program_name": "mysql"}, sess.Client().Attributes)
+
+	// ChangeUser binds the session to the new client
+	dirty(sess)
+	require.NoError(sess.ChangeUser(ctx, Client{User: "qux", Address: "baz"}))
+	requireClean(sess)
+	require.Equal(uint32(1), sess.ID())
+	require.Equal("qux", sess.Client().User)
+	require.Equal("baz", sess.Client().Address)
+	require.NotNil(sess.Client().Attributes)
+	require.Empty(sess.Client().Attributes)
+}
+
+// rollbackSession is a session whose transactions write rows, which are undone when rolled back.
+type rollbackSession struct {
+	*BaseSession
+	rows      []Row
+	committed int
+}
+
+func (s *rollbackSession) CommitTransaction(*Context, string) error {
+	s.committed = len(s.rows)
+	return nil
+}
+
+func (s *rollbackSession) RollbackTransaction(*Context, string) error {
+	s.rows = s.rows[:s.committed]
+	return nil
+}
+
+func TestSessionResetRollsBack(t *testing.T) {
+	require := require.New(t)
+
+	sess := &rollbackSession{BaseSession: NewSession("foo", "baz", "bar", 1).(*BaseSession)}
+	ctx := NewContext(context.Background(), WithSession(sess))
+	sess.SetStrictFoundRows(true)
+
+	sess.rows = append(sess.rows, NewRow(int64(1)))
+	require.NoError(sess.CommitTransaction(ctx, ""))
+	sess.SetInExplicitTransaction(true)
+	sess.rows = append(sess.rows, NewRow(int64(2)))
+	sess.PendingChanges().Record("mydb", "t", 1)
+	sess.SetLastQueryInfo(FoundRows, 5)
+
+	// the row written by the open transaction disappears, the committed one stays
+	require.NoError(sess.Reset(ctx))
+	require.Equal([]Row{{int64(1)}}, sess.rows)
+	require.False(sess.InExplicitTransaction())
+	require.True(sess.PendingChanges().Empty())
+
+	// strict FOUND_ROWS() semantics survive the reset
+	require.True(sess.StrictFoundRows())
+	require.Equal(int64(0), sess.GetLastQueryInfo(FoundRows))
+}
+
+func TestSessionIdleTime(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+
+	before := time.Now()
+	ctx.StartStatement()
+	now := sess.LastActivity()
+	require.False(now.Before(before))
+	require.Equal(time.Duration(0), IdleTime(sess, now))
+	require.Equal(90*time.Second, IdleTime(sess, now.Add(90*time.Second)))
+
+	// a new statement resets the idle time
+	ctx.StartStatement()
+	require.False(sess.LastActivity().Before(now))
+	now = sess.LastActivity()
+	require.Equal(30*time.Second, IdleTime(sess, now.Add(30*time.Second)))
+	require.Equal(time.Duration(0), IdleTime(sess, now.Add(-time.Second)))
+
+	require.Equal(8*time.Hour, IdleTimeout(sess))
+	require.False(IsIdleExpired(sess, now.Add(8*time.Hour)))
+	require.True(IsIdleExpired(sess, now.Add(8*time.Hour+time.Second)))
+
+	require.NoError(sess.Set(ctx, WaitTimeoutSessionVar, Int64, int64(60)))
+	require.Equal(time.Minute, IdleTimeout(sess))
+	require.True(IsIdleExpired(sess, now.Add(2*time.Minute)))
+
+	// a session with a transaction open doesn't expire
+	sess.PendingChanges().Record("db", "t", 1)
+	require.False(IsIdleExpired(sess, now.Add(2*time.Minute)))
+	sess.PendingChanges().Discard()
+	require.True(IsIdleExpired(sess, now.Add(2*time.Minute)))
+
+	// neither does one in an explicit transaction with no changes yet
+	sess.SetInExplicitTransaction(true)
+	require.False(IsIdleExpired(sess, now.Add(2*time.Minute)))
+	sess.SetInExplicitTransaction(false)
+	require.True(IsIdleExpired(sess, now.Add(2*time.Minute)))
+
+	require.NoError(sess.Set(ctx, WaitTimeoutSessionVar, Int64, int64(0)))
+	require.Equal(time.Duration(0), IdleTimeout(sess))
+	require.False(IsIdleExpired(sess, now.Add(24*time.Hour)))
+}
+
+func TestInteractiveIdleTimeout(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	sess := NewSession("", "", "", 1)
+	sess.SetLastActivity(now)
+	require.NoError(sess.Set(ctx, WaitTimeoutSessionVar, Int64, int64(60)))
+	require.NoError(sess.Set(ctx, InteractiveTimeoutSessionVar, Int64, int64(600)))
+
+	// a non-interactive client expires after wait_timeout
+	require.False(sess.Client().Interactive)
+	require.Equal(time.Minute, EffectiveIdleTimeout(sess))
+	require.True(IsIdleExpired(sess, now.Add(2*time.Minute)))
+
+	// an interactive one after interactive_timeout
+	sess.SetClientInteractive(true)
+	require.True(sess.Client().Interactive)
+	require.Equal(10*time.Minute, EffectiveIdleTimeout(sess))
+	require.False(IsIdleExpired(sess, now.Add(2*time.Minute)))
+	require.True(IsIdleExpired(sess, now.Add(11*time.Minute)))
+
+	// the timeouts of other sessions are unaffected
+	other := NewSession("", "", "", 2)
+	require.NoError(sess.Set(ctx, InteractiveTimeoutSessionVar, Int64, int64(0)))
+	require.Equal(time.Duration(0), EffectiveIdleTimeout(sess))
+	require.Equal(8*time.Hour, EffectiveIdleTimeout(other))
+}
+
+func TestOverriddenVariables(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	require.Empty(sess.OverriddenVariables())
+
+	require.NoError(sess.Set(ctx, "sql_mode", LongText, "ANSI_QUOTES"))
+	require.NoError(sess.Set(ctx, "auto_increment_increment", Int64, int64(5)))
+	require.NoError(sess.Set(ctx, "myvar", Int64, int64(1)))
+	require.Equal(map[string]TypedValue{
+		"sql_mode":                 {LongText, "ANSI_QUOTES"},
+		"auto_increment_increment": {Int64, int64(5)},
+		"myvar":                    {Int64, int64(1)},
+	}, sess.OverriddenVariables())
+
+	// a variable set back to its default is no longer overridden
+	require.NoError(sess.Set(ctx, "auto_increment_increment", Int64, int64(1)))
+	require.Equal(map[string]TypedValue{
+		"sql_mode": {LongText, "ANSI_QUOTES"},
+		"myvar":    {Int64, int64(1)},
+	}, sess.OverriddenVariables())
+}
+
 func TestHasDefaultValue(t *testing.T) {
 	require := require.New(t)
 	sess := NewSession("foo", "baz", "bar", 1)
@@ -65,6 +273,368 @@ func TestHasDefaultValue(t *testing.T) {
 	require.False(HasDefaultValue(sess, "non_existing_key"))
 }
 
+func TestContextDefer(t *testing.T) {
+	require := require.New(t)
+	ctx := NewEmptyContext()
+
+	var order []int
+	ctx.Defer(func() error {
+		order = append(order, 1)
+		return nil
+	})
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	subCtx.Defer(func() error {
+		order = append(order, 2)
+		return fmt.Errorf("cleanup 2 failed")
+	})
+	ctx.Defer(func() error {
+		order = append(order, 3)
+		panic("cleanup 3 panicked")
+	})
+	ctx.Defer(func() error {
+		order = append(order, 4)
+		return nil
+	})
+
+	err := ctx.RunDeferred()
+	require.Error(err)
+	require.True(ErrDeferredCleanup.Is(err))
+	require.Contains(err.Error(), "cleanup 2 failed")
+	require.Contains(err.Error(), "cleanup 3 panicked")
+	require.Equal([]int{4, 3, 2, 1}, order)
+
+	// Deferred functions only run once
+	require.NoError(ctx.RunDeferred())
+	require.Equal([]int{4, 3, 2, 1}, order)
+}
+
+func TestSessionResourceGroup(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	require.Equal("", sess.ResourceGroup())
+
+	ctx := NewContext(context.Background(), WithSession(sess))
+	ctx.SetResourceGroup("tenant_a")
+
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	require.Equal("tenant_a", subCtx.ResourceGroup())
+
+	pl := NewProcessList()
+	procCtx, err := pl.AddProcess(subCtx, QueryProcess, "SELECT 1")
+	require.NoError(err)
+	require.Equal("tenant_a", procCtx.ResourceGroup())
+	require.Equal("tenant_a", pl.Processes()[0].ResourceGroup)
+}
+
+func TestSessionKilled(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	require.NoError(ctx.CheckInterrupted())
+
+	sess.MarkKilled()
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	require.True(subCtx.IsKilled())
+	require.True(ErrQueryInterrupted.Is(subCtx.CheckInterrupted()))
+
+	sqlErr, _ := CastSQLError(subCtx.CheckInterrupted())
+	require.Equal(mysql.ERQueryInterrupted, sqlErr.Number())
+
+	sess.ClearKilled()
+	require.NoError(ctx.CheckInterrupted())
+}
+
+func TestStrictFoundRows(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1).(*BaseSession)
+	require.False(sess.StrictFoundRows())
+	require.Equal(int64(1), sess.GetLastQueryInfo(FoundRows))
+
+	sess.SetStrictFoundRows(true)
+	require.True(sess.StrictFoundRows())
+	require.Equal(int64(0), sess.GetLastQueryInfo(FoundRows))
+
+	sess.SetLastQueryInfo(FoundRows, 5)
+	require.Equal(int64(5), sess.GetLastQueryInfo(FoundRows))
+
+	sess = NewBaseSession().(*BaseSession)
+	sess.SetStrictFoundRows(true)
+	sess.SetStrictFoundRows(false)
+	require.Equal(int64(1), sess.GetLastQueryInfo(FoundRows))
+}
+
+func TestOptimizerSwitch(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	sess := NewBaseSession()
+	flags := sess.OptimizerSwitch()
+	require.Len(flags, len(optimizerFlags))
+	require.True(flags["index_merge"])
+	require.False(flags["batched_key_access"])
+
+	require.NoError(sess.SetOptimizerFlag("INDEX_MERGE", false))
+	require.NoError(sess.SetOptimizerFlag("batched_key_access", true))
+	require.False(sess.OptimizerSwitch()["index_merge"])
+	require.True(sess.OptimizerSwitch()["batched_key_access"])
+
+	// the value is rewritten with every flag, in the same order
+	_, val := sess.Get(OptimizerSwitchSessionVar)
+	expected := strings.Replace(DefaultOptimizerSwitch(), "index_merge=on,", "index_merge=off,", 1)
+	expected = strings.Replace(expected, "batched_key_access=off", "batched_key_access=on", 1)
+	require.Equal(expected, val)
+
+	require.NoError(sess.SetOptimizerFlag("index_merge", true))
+	require.NoError(sess.SetOptimizerFlag("batched_key_access", false))
+	_, val = sess.Get(OptimizerSwitchSessionVar)
+	require.Equal(DefaultOptimizerSwitch(), val)
+
+	require.True(ErrUnknownOptimizerFlag.Is(sess.SetOptimizerFlag("no_such_flag", true)))
+
+	// values set directly may list some flags only, and use the default keyword
+	require.NoError(sess.Set(ctx, OptimizerSwitchSessionVar, LongText, "index_merge=off, hash_join=off"))
+	flags = sess.OptimizerSwitch()
+	require.False(flags["index_merge"])
+	require.False(flags["hash_join"])
+	require.True(flags["skip_scan"])
+
+	require.NoError(sess.Set(ctx, OptimizerSwitchSessionVar, LongText, "index_merge=off,hash_join=off,index_merge=default"))
+	flags = sess.OptimizerSwitch()
+	require.True(flags["index_merge"])
+	require.False(flags["hash_join"])
+
+	require.NoError(sess.Set(ctx, OptimizerSwitchSessionVar, LongText, "hash_join=off,default,skip_scan=off"))
+	flags = sess.OptimizerSwitch()
+	require.True(flags["hash_join"])
+	require.False(flags["skip_scan"])
+
+	// merged values keep the current value of the flags they don't list, and reject any entry that isn't valid
+	_, val = sess.Get(OptimizerSwitchSessionVar)
+	merged, err := MergeOptimizerSwitch(val.(string), "index_merge=off")
+	require.NoError(err)
+	flags = parseOptimizerSwitch(merged)
+	require.False(flags["index_merge"])
+	require.False(flags["skip_scan"])
+	require.Equal(formatOptimizerSwitch(flags), merged)
+
+	merged, err = MergeOptimizerSwitch(merged, "default,hash_join=off")
+	require.NoError(err)
+	flags = parseOptimizerSwitch(merged)
+	require.True(flags["index_merge"])
+	require.False(flags["hash_join"])
+
+	_, err = MergeOptimizerSwitch(merged, "no_such_flag=on")
+	require.True(ErrUnknownOptimizerFlag.Is(err))
+	_, err = MergeOptimizerSwitch(merged, "index_merge=maybe")
+	require.True(ErrInvalidOptimizerSwitch.Is(err))
+}
+
+func TestContextMaxExecutionTime(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	ctx := NewContext(context.Background(), WithSession(sess))
+	require.Equal(time.Duration(0), ctx.MaxExecutionTime())
+
+	require.NoError(sess.Set(ctx, MaxExecutionTimeSessionVar, Int64, int64(1500)))
+	require.Equal(1500*time.Millisecond, ctx.MaxExecutionTime())
+
+	require.NoError(sess.Set(ctx, MaxExecutionTimeSessionVar, LongText, "250"))
+	require.Equal(250*time.Millisecond, ctx.MaxExecutionTime())
+
+	require.NoError(sess.Set(ctx, MaxExecutionTimeSessionVar, Int64, int64(0)))
+	require.Equal(time.Duration(0), ctx.MaxExecutionTime())
+
+	require.NoError(sess.Set(ctx, MaxExecutionTimeSessionVar, Int64, int64(-5)))
+	require.Equal(time.Duration(0), ctx.MaxExecutionTime())
+
+	require.NoError(sess.Set(ctx, MaxExecutionTimeSessionVar, Null, nil))
+	require.Equal(time.Duration(0), ctx.MaxExecutionTime())
+}
+
+func TestContextResultsCharacterSet(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	ctx := NewContext(context.Background(), WithSession(sess))
+	cs, converted := ctx.ResultsCharacterSet()
+	require.Equal(Collation_Default.CharacterSet(), cs)
+	require.True(converted)
+
+	require.NoError(sess.Set(ctx, CharacterSetResultsSessionVar, LongText, "latin1"))
+	cs, converted = ctx.ResultsCharacterSet()
+	require.Equal(CharacterSet_latin1, cs)
+	require.True(converted)
+
+	// An explicit NULL turns the conversion off
+	require.NoError(sess.Set(ctx, CharacterSetResultsSessionVar, LongText, nil))
+	_, converted = ctx.ResultsCharacterSet()
+	require.False(converted)
+
+	// While a variable that isn't set gets the default
+	require.NoError(sess.Set(ctx, CharacterSetResultsSessionVar, Null, nil))
+	cs, converted = ctx.ResultsCharacterSet()
+	require.Equal(Collation_Default.CharacterSet(), cs)
+	require.True(converted)
+}
+
+func TestContextStatementStartTime(t *testing.T) {
+	require := require.New(t)
+
+	clock := time.Unix(100, 0)
+	ctx := NewContext(context.Background(), WithNowFunc(func() time.Time {
+		return clock
+	}))
+	queryTime := ctx.QueryTime()
+	require.Equal(queryTime, ctx.StatementStartTime())
+
+	// The start is read from the system clock, not the one of NOW()
+	before := time.Now()
+	ctx.StartStatement()
+	start := ctx.StatementStartTime()
+	require.False(start.Before(before))
+	require.False(start.After(time.Now()))
+	require.Equal(start, ctx.Session.LastActivity())
+	require.Equal(queryTime, ctx.QueryTime())
+
+	// The start is shared with derived contexts
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	require.Equal(start, subCtx.StatementStartTime())
+	ctx.StartStatement()
+	require.False(subCtx.StatementStartTime().Before(start))
+	require.Equal(ctx.StatementStartTime(), subCtx.StatementStartTime())
+}
+
+func TestContextCancellationReason(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewEmptyContext()
+	require.Equal(CancelReason_None, ctx.CancellationReason())
+	require.NoError(ctx.CancellationError())
+
+	tests := []struct {
+		reason CancelReason
+		err    *errors.Kind
+	}{
+		{CancelReason_Timeout, ErrQueryTimeout},
+		{CancelReason_Killed, ErrQueryInterrupted},
+		{CancelReason_ConnectionClosed, ErrConnectionClosed},
+	}
+	for _, test := range tests {
+		parent, cancel := ctx.NewSubContextWithCancelReason()
+		child, cancelChild := parent.NewSubContextWithCancelReason()
+		require.NoError(child.CancellationError())
+
+		// The reason of the parent propagates to its children
+		cancel(test.reason)
+		cancel(CancelReason_Timeout)
+		<-child.Done()
+		require.Equal(test.reason, parent.CancellationReason(), test.reason.String())
+		require.Equal(test.reason, child.CancellationReason(), test.reason.String())
+		require.True(test.err.Is(parent.CancellationError()), test.reason.String())
+		require.True(test.err.Is(child.CancellationError()), test.reason.String())
+		require.Equal(CancelReason_None, ctx.CancellationReason())
+		cancelChild(CancelReason_None)
+	}
+
+	// A child cancelled for its own reason doesn't change its parent
+	parent, cancel := ctx.NewSubContextWithCancelReason()
+	defer cancel(CancelReason_None)
+	child, cancelChild := parent.NewSubContextWithCancelReason()
+	cancelChild(CancelReason_Timeout)
+	require.True(ErrQueryTimeout.Is(child.CancellationError()))
+	require.Equal(CancelReason_None, parent.CancellationReason())
+	require.NoError(parent.CancellationError())
+
+	// Without a reason, the error is the one of the underlying context
+	subCtx, cancelFunc := ctx.NewSubContext()
+	cancelFunc()
+	require.Equal(context.Canceled, subCtx.CancellationError())
+}
+
+func TestTypedValueConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    TypedValue
+		target   Type
+		expected interface{}
+		err      bool
+	}{
+		{"string to int32", TypedValue{LongText, "1"}, Int32, int32(1), false},
+		{"int to int8", TypedValue{Int64, 1}, Int8, int8(1), false},
+		{"int64 to int8 out of range", TypedValue{Int64, int64(300)}, Int8, nil, true},
+		{"ON to int8", TypedValue{LongText, "ON"}, Int8, int8(1), false},
+		{"off to int8", TypedValue{LongText, "off"}, Int8, int8(0), false},
+		{"bool to int64", TypedValue{Boolean, true}, Int64, int64(1), false},
+		{"int to text", TypedValue{Int64, int64(12)}, LongText, "12", false},
+		{"ON to text", TypedValue{LongText, "ON"}, LongText, "ON", false},
+		{"invalid string to int32", TypedValue{LongText, "abc"}, Int32, nil, true},
+		{"nil", TypedValue{Null, nil}, Int32, nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			converted, err := test.value.Convert(test.target)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.target, converted.Typ)
+			require.Equal(t, test.expected, converted.Value)
+		})
+	}
+}
+
+func TestContextMetadata(t *testing.T) {
+	require := require.New(t)
+
+	ctx := NewContext(context.WithValue(context.Background(), QueryKey, "SELECT 1"))
+	_, ok := ctx.Metadata("tenant")
+	require.False(ok)
+
+	ctx.SetMetadata("tenant", "tenant_a")
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	span, spanCtx := subCtx.Span("test")
+	defer span.Finish()
+	derived := spanCtx.WithContext(context.Background())
+
+	for _, c := range []*Context{subCtx, spanCtx, derived} {
+		val, ok := c.Metadata("tenant")
+		require.True(ok)
+		require.Equal("tenant_a", val)
+	}
+
+	// metadata is shared, and separate from the values of the underlying context
+	derived.SetMetadata("trace", 42)
+	val, ok := ctx.Metadata("trace")
+	require.True(ok)
+	require.Equal(42, val)
+	require.Equal("SELECT 1", spanCtx.Value(QueryKey))
+}
+
+func TestContextRandSeed(t *testing.T) {
+	require := require.New(t)
+
+	expected := NewContext(context.Background(), WithRandSeed(1))
+	ctx := NewContext(context.Background(), WithRandSeed(1))
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+
+	// The sub context draws from the same source as its parent
+	require.Equal(expected.RandFloat64(), ctx.RandFloat64())
+	require.Equal(expected.RandFloat64(), subCtx.RandFloat64())
+	require.Equal(expected.RandFloat64(), ctx.RandFloat64())
+}
+
 type testNode struct{}
 
 func (*testNode) Resolved() bool {
@@ -113,6 +683,117 @@ func (t *testNodeIterator) Close(*Context) error {
 	panic("not implemented")
 }
 
+func TestWarningBuffer(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	sess.Warn(&Warning{Level: "Warning", Code: 1, Message: "first"})
+
+	buf := NewWarningBuffer(ctx, 2)
+	buf.Warn(2, "second")
+	require.Equal(uint16(1), sess.WarningCount())
+	buf.Warn(3, "third")
+	require.Equal(uint16(3), sess.WarningCount())
+	buf.Warn(4, "fourth")
+	require.Equal(uint16(3), sess.WarningCount())
+	buf.Flush()
+	buf.Flush()
+
+	var codes []int
+	for _, w := range sess.Warnings() {
+		codes = append(codes, w.Code)
+	}
+	require.Equal([]int{4, 3, 2, 1}, codes)
+
+	sess.WarnBatch(nil)
+	require.Equal(uint16(4), sess.WarningCount())
+
+	// warnings given to the session through a context, even a derived one, come after the buffered ones
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	buf.Warn(5, "fifth")
+	subCtx.Warn(6, "sixth")
+	buf.Warn(7, "seventh")
+	ctx.Error(8, "eighth")
+	buf.Flush()
+
+	codes = nil
+	for _, w := range sess.Warnings() {
+		codes = append(codes, w.Code)
+	}
+	require.Equal([]int{8, 7, 6, 5, 4, 3, 2, 1}, codes)
+}
+
+func TestMergeWarnings(t *testing.T) {
+	require := require.New(t)
+
+	codes := func(sess Session) []int {
+		var codes []int
+		for _, w := range sess.Warnings() {
+			codes = append(codes, w.Code)
+		}
+		return codes
+	}
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	sess.Warn(&Warning{Level: "Warning", Code: 1, Message: "first"})
+
+	nested := NewSession("foo", "baz", "bar", 1)
+	nested.Warn(&Warning{Level: "Warning", Code: 2, Message: "second"})
+	nested.Warn(&Warning{Level: "Note", Code: 3, Message: "third"})
+
+	sess.MergeWarnings(nested)
+	require.Equal([]int{3, 2, 1}, codes(sess))
+	require.Equal([]int{3, 2}, codes(nested))
+
+	// Merging a session into itself doesn't count its warnings twice
+	sess.MergeWarnings(sess)
+	sess.MergeWarnings(nil)
+	sess.MergeWarnings(NewBaseSession())
+	require.Equal(uint16(3), sess.WarningCount())
+
+	full := NewBaseSession()
+	warns := make([]*Warning, math.MaxUint16-1)
+	for i := range warns {
+		warns[i] = &Warning{Level: "Warning", Code: 1}
+	}
+	full.WarnBatch(warns)
+	full.MergeWarnings(nested)
+	require.Equal(uint16(math.MaxUint16), full.WarningCount())
+	require.Equal(2, full.Warnings()[0].Code)
+
+	// Warn and WarnBatch drop the warnings beyond the cap too
+	full.Warn(&Warning{Level: "Warning", Code: 4})
+	full.WarnBatch([]*Warning{{Level: "Warning", Code: 5}})
+	require.Equal(uint16(math.MaxUint16), full.WarningCount())
+	require.Equal(2, full.Warnings()[0].Code)
+}
+
+func BenchmarkWarnings(b *testing.B) {
+	const rows = 10000
+
+	b.Run("per row", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			ctx := NewContext(context.Background(), WithSession(NewSession("foo", "baz", "bar", 1)))
+			for i := 0; i < rows; i++ {
+				ctx.Warn(1265, "Data truncated")
+			}
+		}
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			ctx := NewContext(context.Background(), WithSession(NewSession("foo", "baz", "bar", 1)))
+			buf := NewWarningBuffer(ctx, DefaultWarningBufferSize)
+			for i := 0; i < rows; i++ {
+				buf.Warn(1265, "Data truncated")
+			}
+			buf.Flush()
+		}
+	})
+}
+
 func TestSessionIterator(t *testing.T) {
 	require := require.New(t)
 	ctx, cancelFunc := context.WithCancel(context.TODO())
@@ -62,11 +62,19 @@ func (s SortField) String() string {
 }
 
 func (s SortField) DebugString() string {
-	nullOrdering := "nullsFirst"
-	if s.NullOrdering == NullsLast {
-		nullOrdering = "nullsLast"
+	return fmt.Sprintf("%s %s %s", DebugString(s.Column), DebugString(s.Order), s.NullOrdering)
+}
+
+// NullsFirst returns whether null values of the field go before the other values.
+func (s SortField) NullsFirst() bool {
+	switch s.NullOrdering {
+	case NullsFirst:
+		return true
+	case NullsLast:
+		return false
+	default:
+		return s.Order != Descending
 	}
-	return fmt.Sprintf("%s %s %s", DebugString(s.Column), DebugString(s.Order), nullOrdering)
 }
 
 // ErrUnableSort is thrown when something happens on sorting
@@ -97,8 +105,24 @@ func (s SortOrder) String() string {
 type NullOrdering byte
 
 const (
-	// NullsFirst puts the null values before any other values.
-	NullsFirst NullOrdering = iota
-	// NullsLast puts the null values after all other values.
-	NullsLast NullOrdering = 2
+	// NullsDefault orders null values as MySQL does, as if they were smaller than any other value: first in ascending
+	// order, and last in descending order.
+	NullsDefault NullOrdering = iota
+	// NullsFirst puts the null values before any other values, whatever the sort order.
+	NullsFirst
+	// NullsLast puts the null values after all other values, whatever the sort order.
+	NullsLast
 )
+
+func (n NullOrdering) String() string {
+	switch n {
+	case NullsDefault:
+		return "nullsDefault"
+	case NullsFirst:
+		return "nullsFirst"
+	case NullsLast:
+		return "nullsLast"
+	default:
+		return "invalid NullOrdering"
+	}
+}
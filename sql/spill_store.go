@@ -0,0 +1,168 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ErrSpillFileNotFound is returned by SpillStore.Open when there is no file with the name given.
+var ErrSpillFileNotFound = errors.NewKind("spill file not found: %s")
+
+// ErrInvalidSpillFileName is returned when the name of a spill file isn't a plain file name, such as a path.
+var ErrInvalidSpillFileName = errors.NewKind("invalid spill file name: %s")
+
+// SpillStore stores the temporary files operators spill data to when it doesn't fit in memory. Names are plain file
+// names, unique within the store. The default store of a Context keeps them in the directory of the tmpdir session
+// variable, but integrators can supply another with WithSpillStore, such as one in memory or in an object store.
+type SpillStore interface {
+	// Create creates the file with the name given, replacing any file with that name, and returns a writer for it.
+	Create(name string) (io.WriteCloser, error)
+	// Open returns a reader for the file with the name given. Returns ErrSpillFileNotFound if there is none.
+	Open(name string) (io.ReadCloser, error)
+	// Remove removes the file with the name given. Removing a file that doesn't exist isn't an error, so a file can be
+	// removed by both the operator that created it and the cleanup of its query.
+	Remove(name string) error
+}
+
+// NewOSSpillStore returns a SpillStore that keeps its files in the directory given, or in the default directory for
+// temporary files of the OS if it's empty.
+func NewOSSpillStore(dir string) SpillStore {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return osSpillStore{dir}
+}
+
+type osSpillStore struct {
+	dir string
+}
+
+var _ SpillStore = osSpillStore{}
+
+// Create implements SpillStore.
+func (s osSpillStore) Create(name string) (io.WriteCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// Open implements SpillStore.
+func (s osSpillStore) Open(name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrSpillFileNotFound.New(name)
+	}
+	return f, err
+}
+
+// Remove implements SpillStore.
+func (s osSpillStore) Remove(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path returns the path of the file with the name given, which mustn't point outside of the directory of the store.
+func (s osSpillStore) path(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return "", ErrInvalidSpillFileName.New(name)
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+// NewMemorySpillStore returns a SpillStore that keeps its files in memory. A file is visible to Open once the writer
+// returned by Create is closed.
+func NewMemorySpillStore() *MemorySpillStore {
+	return &MemorySpillStore{files: make(map[string][]byte)}
+}
+
+// MemorySpillStore is a SpillStore that keeps its files in memory.
+type MemorySpillStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+var _ SpillStore = (*MemorySpillStore)(nil)
+
+// Create implements SpillStore.
+func (s *MemorySpillStore) Create(name string) (io.WriteCloser, error) {
+	if name == "" {
+		return nil, ErrInvalidSpillFileName.New(name)
+	}
+	return &memorySpillFile{store: s, name: name}, nil
+}
+
+// Open implements SpillStore.
+func (s *MemorySpillStore) Open(name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, ErrSpillFileNotFound.New(name)
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Remove implements SpillStore.
+func (s *MemorySpillStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, name)
+	return nil
+}
+
+// Files returns the names of the files in the store, in no particular order.
+func (s *MemorySpillStore) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	return names
+}
+
+// memorySpillFile buffers the data written to a file of a MemorySpillStore until it's closed.
+type memorySpillFile struct {
+	bytes.Buffer
+	store *MemorySpillStore
+	name  string
+}
+
+func (f *memorySpillFile) Close() error {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+	f.store.files[f.name] = f.Bytes()
+	return nil
+}
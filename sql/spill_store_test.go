@@ -0,0 +1,109 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testSpillStore(t *testing.T, store SpillStore) {
+	require := require.New(t)
+
+	w, err := store.Create("spill1")
+	require.NoError(err)
+	_, err = w.Write([]byte("hello "))
+	require.NoError(err)
+	_, err = w.Write([]byte("world"))
+	require.NoError(err)
+	require.NoError(w.Close())
+
+	r, err := store.Open("spill1")
+	require.NoError(err)
+	data, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.NoError(r.Close())
+	require.Equal("hello world", string(data))
+
+	require.NoError(store.Remove("spill1"))
+	_, err = store.Open("spill1")
+	require.True(ErrSpillFileNotFound.Is(err), "unexpected error %v", err)
+
+	// removing a file twice is fine
+	require.NoError(store.Remove("spill1"))
+}
+
+func TestOSSpillStore(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "spill")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	store := NewOSSpillStore(dir)
+	testSpillStore(t, store)
+
+	w, err := store.Create("spill2")
+	require.NoError(err)
+	require.NoError(w.Close())
+	_, err = os.Stat(filepath.Join(dir, "spill2"))
+	require.NoError(err)
+
+	for _, name := range []string{"", "..", "../spill", filepath.Join("sub", "spill")} {
+		_, err = store.Create(name)
+		require.True(ErrInvalidSpillFileName.Is(err), "unexpected error %v for %q", err, name)
+	}
+}
+
+func TestMemorySpillStore(t *testing.T) {
+	require := require.New(t)
+	store := NewMemorySpillStore()
+	testSpillStore(t, store)
+
+	// a file is only visible once its writer is closed
+	w, err := store.Create("spill2")
+	require.NoError(err)
+	_, err = store.Open("spill2")
+	require.True(ErrSpillFileNotFound.Is(err))
+	require.NoError(w.Close())
+	require.Equal([]string{"spill2"}, store.Files())
+}
+
+func TestContextSpillStore(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "spill")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// by default, files go to the directory of the tmpdir session variable
+	ctx := NewContext(context.Background())
+	require.NoError(ctx.Set(ctx, "tmpdir", LongText, dir))
+	w, err := ctx.SpillStore().Create("spill")
+	require.NoError(err)
+	require.NoError(w.Close())
+	_, err = os.Stat(filepath.Join(dir, "spill"))
+	require.NoError(err)
+
+	// a store given to the context is used instead, including by the contexts derived from it
+	store := NewMemorySpillStore()
+	ctx = NewContext(context.Background(), WithSpillStore(store))
+	subCtx, cancel := ctx.NewSubContext()
+	defer cancel()
+	require.Equal(store, subCtx.SpillStore())
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "github.com/dolthub/vitess/go/mysql"
+
+// sqlStates maps MySQL error codes to their standard SQLSTATE. Codes whose SQLSTATE is HY000, the one of errors without
+// a more specific class, are left out.
+var sqlStates = map[int]string{
+	1022: "23000", // ER_DUP_KEY
+	1044: "42000", // ER_DBACCESS_DENIED_ERROR
+	1045: "28000", // ER_ACCESS_DENIED_ERROR
+	1046: "3D000", // ER_NO_DB_ERROR
+	1047: "08S01", // ER_UNKNOWN_COM_ERROR
+	1048: "23000", // ER_BAD_NULL_ERROR
+	1049: "42000", // ER_BAD_DB_ERROR
+	1050: "42S01", // ER_TABLE_EXISTS_ERROR
+	1051: "42S02", // ER_BAD_TABLE_ERROR
+	1052: "23000", // ER_NON_UNIQ_ERROR
+	1054: "42S22", // ER_BAD_FIELD_ERROR
+	1060: "42S21", // ER_DUP_FIELDNAME
+	1061: "42000", // ER_DUP_KEYNAME
+	1062: "23000", // ER_DUP_ENTRY
+	1064: "42000", // ER_PARSE_ERROR
+	1066: "42000", // ER_NONUNIQ_TABLE
+	1091: "42000", // ER_CANT_DROP_FIELD_OR_KEY
+	1136: "21S01", // ER_WRONG_VALUE_COUNT_ON_ROW
+	1142: "42000", // ER_TABLEACCESS_DENIED_ERROR
+	1146: "42S02", // ER_NO_SUCH_TABLE
+	1149: "42000", // ER_SYNTAX_ERROR
+	1213: "40001", // ER_LOCK_DEADLOCK
+	1216: "23000", // ER_NO_REFERENCED_ROW
+	1217: "23000", // ER_ROW_IS_REFERENCED
+	1227: "42000", // ER_SPECIFIC_ACCESS_DENIED_ERROR
+	1242: "21000", // ER_SUBQUERY_NO_1_ROW
+	1253: "42000", // ER_COLLATION_CHARSET_MISMATCH
+	1264: "22003", // ER_WARN_DATA_OUT_OF_RANGE
+	1265: "01000", // WARN_DATA_TRUNCATED
+	1286: "42000", // ER_UNKNOWN_STORAGE_ENGINE
+	1292: "22007", // ER_TRUNCATED_WRONG_VALUE
+	1305: "42000", // ER_SP_DOES_NOT_EXIST
+	1317: "70100", // ER_QUERY_INTERRUPTED
+	1365: "22012", // ER_DIVISION_BY_ZERO
+	1406: "22001", // ER_DATA_TOO_LONG
+	1451: "23000", // ER_ROW_IS_REFERENCED_2
+	1452: "23000", // ER_NO_REFERENCED_ROW_2
+	1461: "42000", // ER_MAX_PREPARED_STMT_COUNT_REACHED
+	1690: "22003", // ER_DATA_OUT_OF_RANGE
+	1792: "25006", // ER_CANT_EXECUTE_IN_READ_ONLY_TRANSACTION
+}
+
+// SQLStateForCode returns the SQLSTATE of the MySQL error code given, or HY000 if it has no more specific one, as is
+// the case for the codes of the errors of integrators.
+func SQLStateForCode(code int) string {
+	if state, ok := sqlStates[code]; ok {
+		return state
+	}
+	return mysql.SSUnknownSQLState
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Names of the status variables counted by StatusCounters.
+const (
+	StatusQuestions = "Questions"
+	StatusComSelect = "Com_select"
+	StatusComInsert = "Com_insert"
+	StatusComUpdate = "Com_update"
+	StatusComDelete = "Com_delete"
+)
+
+// statusVariables are the names of the status variables counted by StatusCounters.
+var statusVariables = []string{StatusQuestions, StatusComSelect, StatusComInsert, StatusComUpdate, StatusComDelete}
+
+// StatusCounters holds the counters of the status variables of a session, or of the server for the global ones. The
+// counters incremented together, such as the ones counting a statement, are seen together: Reset and All never see
+// some of those increments but not the others.
+type StatusCounters struct {
+	// mu is held for reading by increments, which update the counters atomically, and for writing by Reset and All
+	mu       sync.RWMutex
+	counters map[string]*int64
+}
+
+// NewStatusCounters returns a new set of status counters, all of them zero.
+func NewStatusCounters() *StatusCounters {
+	counters := make(map[string]*int64, len(statusVariables))
+	for _, name := range statusVariables {
+		counters[name] = new(int64)
+	}
+	return &StatusCounters{counters: counters}
+}
+
+// Increment adds one to each of the status variables with the names given, as a group. Unknown names are ignored.
+func (s *StatusCounters) Increment(names ...string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, name := range names {
+		if c, ok := s.counters[name]; ok {
+			atomic.AddInt64(c, 1)
+		}
+	}
+}
+
+// Get returns the value of the status variable with the name given, or 0 if it's unknown.
+func (s *StatusCounters) Get(name string) int64 {
+	if c, ok := s.counters[name]; ok {
+		return atomic.LoadInt64(c)
+	}
+	return 0
+}
+
+// Reset sets every counter to zero, as FLUSH STATUS does. Every group of increments is either made entirely before the
+// reset or entirely after it.
+func (s *StatusCounters) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.counters {
+		atomic.StoreInt64(c, 0)
+	}
+}
+
+// StatusVariable is the value of a status variable, as returned by StatusCounters.All.
+type StatusVariable struct {
+	Name  string
+	Value int64
+}
+
+// All returns the value of every status variable, sorted by name.
+func (s *StatusCounters) All() []StatusVariable {
+	s.mu.Lock()
+	all := make([]StatusVariable, 0, len(s.counters))
+	for name, c := range s.counters {
+		all = append(all, StatusVariable{Name: name, Value: atomic.LoadInt64(c)})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
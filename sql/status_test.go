@@ -0,0 +1,114 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCounters(t *testing.T) {
+	require := require.New(t)
+
+	status := NewStatusCounters()
+	require.Equal(int64(0), status.Get(StatusQuestions))
+
+	status.Increment(StatusQuestions)
+	status.Increment(StatusQuestions)
+	status.Increment(StatusComSelect)
+	status.Increment("Unknown_status")
+	require.Equal(int64(2), status.Get(StatusQuestions))
+	require.Equal(int64(1), status.Get(StatusComSelect))
+	require.Equal(int64(0), status.Get("Unknown_status"))
+	require.Equal([]StatusVariable{
+		{StatusComDelete, 0},
+		{StatusComInsert, 0},
+		{StatusComSelect, 1},
+		{StatusComUpdate, 0},
+		{StatusQuestions, 2},
+	}, status.All())
+
+	status.Reset()
+	for _, v := range status.All() {
+		require.Equal(int64(0), v.Value, v.Name)
+	}
+
+	status.Increment(StatusQuestions)
+	require.Equal(int64(1), status.Get(StatusQuestions))
+}
+
+func TestStatusCountersConcurrentReset(t *testing.T) {
+	require := require.New(t)
+
+	status := NewStatusCounters()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				status.Increment(StatusQuestions)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		status.Reset()
+		require.True(status.Get(StatusQuestions) <= 4000)
+	}
+	wg.Wait()
+	require.True(status.Get(StatusQuestions) <= 4000)
+
+	status.Reset()
+	status.Increment(StatusQuestions)
+	require.Equal(int64(1), status.Get(StatusQuestions))
+}
+
+func TestStatusCountersGroupedIncrements(t *testing.T) {
+	require := require.New(t)
+
+	status := NewStatusCounters()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				status.Increment(StatusQuestions, StatusComSelect)
+			}
+		}()
+	}
+	// the counters incremented together are reset and read together
+	for i := 0; i < 100; i++ {
+		status.Reset()
+		values := make(map[string]int64)
+		for _, v := range status.All() {
+			values[v.Name] = v.Value
+		}
+		require.Equal(values[StatusQuestions], values[StatusComSelect])
+	}
+	wg.Wait()
+}
+
+func TestSessionResetStats(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	sess.Status().Increment(StatusQuestions)
+	require.Equal(int64(1), sess.Status().Get(StatusQuestions))
+	sess.ResetStats()
+	require.Equal(int64(0), sess.Status().Get(StatusQuestions))
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sort"
+	"strings"
+)
+
+// SystemVariableScope represents the scope a system variable value is read from.
+type SystemVariableScope byte
+
+const (
+	// SystemVariableScope_Session is the scope of values set for a single session.
+	SystemVariableScope_Session SystemVariableScope = iota
+	// SystemVariableScope_Global is the scope of the server-wide values that sessions start with. Global values are
+	// currently the read-only defaults given by DefaultSessionConfig.
+	SystemVariableScope_Global
+)
+
+// String returns the scope as it is written in a variable qualifier.
+func (s SystemVariableScope) String() string {
+	switch s {
+	case SystemVariableScope_Session:
+		return "SESSION"
+	case SystemVariableScope_Global:
+		return "GLOBAL"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ResolveSystemVariable returns the type and value of the system variable with the name given, along with the scope it
+// was read from. As in MySQL, the session value takes precedence over the global one. Names qualified with
+// `global.`, `session.` or `local.`, optionally preceded by `@@`, read only from the scope given. Returns
+// ErrUnknownSystemVariable if no scope consulted has the variable.
+func (c *Context) ResolveSystemVariable(name string) (Type, interface{}, SystemVariableScope, error) {
+	varName := strings.ToLower(strings.TrimLeft(name, "@"))
+
+	checkSession, checkGlobal := true, true
+	switch {
+	case strings.HasPrefix(varName, "global."):
+		varName = strings.TrimPrefix(varName, "global.")
+		checkSession = false
+	case strings.HasPrefix(varName, "session."):
+		varName = strings.TrimPrefix(varName, "session.")
+		checkGlobal = false
+	case strings.HasPrefix(varName, "local."):
+		varName = strings.TrimPrefix(varName, "local.")
+		checkGlobal = false
+	}
+
+	if checkSession && c.Session != nil {
+		// Sessions report unset variables as a NULL value of type Null
+		if typ, val := c.Get(varName); typ != Null || val != nil {
+			return typ, val, SystemVariableScope_Session, nil
+		}
+	}
+
+	if checkGlobal {
+		if v, ok := DefaultSessionConfig()[varName]; ok {
+			return v.Typ, v.Value, SystemVariableScope_Global, nil
+		}
+	}
+
+	return nil, nil, SystemVariableScope_Session, ErrUnknownSystemVariable.New(varName)
+}
+
+// readOnlySystemVariables are the system variables that can't be set, because they describe the server.
+var readOnlySystemVariables = map[string]bool{
+	"ndbinfo_version":  true,
+	"secure_file_priv": true,
+	"system_time_zone": true,
+	"tmpdir":           true,
+	"version":          true,
+	"version_comment":  true,
+}
+
+// globalOnlySystemVariables are the system variables that can only be set with SET GLOBAL, as they have no session
+// value in MySQL.
+var globalOnlySystemVariables = map[string]bool{
+	ReadOnlySessionVar:      true,
+	SuperReadOnlySessionVar: true,
+}
+
+// IsGlobalOnlySystemVariable returns whether the system variable with the name given can only be set with SET GLOBAL.
+func IsGlobalOnlySystemVariable(name string) bool {
+	return globalOnlySystemVariables[strings.ToLower(name)]
+}
+
+// SystemVariable describes a system variable and its current value.
+type SystemVariable struct {
+	// Name is the name of the variable.
+	Name string
+	// Type is the type of the variable.
+	Type Type
+	// Value is the current value of the variable, which may be nil.
+	Value interface{}
+	// Scope is the scope the current value comes from.
+	Scope SystemVariableScope
+	// ReadOnly is true if the variable can't be set.
+	ReadOnly bool
+}
+
+// SessionVariable is a variable set in a session, as returned by SortedVariables.
+type SessionVariable struct {
+	Name  string
+	Value TypedValue
+}
+
+// SortedVariables returns the variables set in the session given sorted by name, so that their order is the same on
+// every call, unlike that of GetAll.
+func SortedVariables(s Session) []SessionVariable {
+	return sortedVariables(s.GetAll())
+}
+
+// sortedVariables returns the variables of the config given sorted by name.
+func sortedVariables(config map[string]TypedValue) []SessionVariable {
+	vars := make([]SessionVariable, 0, len(config))
+	for name, v := range config {
+		vars = append(vars, SessionVariable{Name: name, Value: v})
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+	return vars
+}
+
+// AllSystemVariables returns every system variable known to the session given with its current value, sorted by name.
+// Session values, in the order of SortedVariables, take precedence over the global defaults, and variables only set in
+// the session are included. Filtering, such as for a LIKE clause, is left to the caller.
+func AllSystemVariables(s Session) []SystemVariable {
+	defaults := sortedVariables(DefaultSessionConfig())
+	var session []SessionVariable
+	if s != nil {
+		session = SortedVariables(s)
+	}
+
+	all := make([]SystemVariable, 0, len(defaults)+len(session))
+	appendVar := func(v SessionVariable, scope SystemVariableScope) {
+		all = append(all, SystemVariable{
+			Name:     v.Name,
+			Type:     v.Value.Typ,
+			Value:    v.Value.Value,
+			Scope:    scope,
+			ReadOnly: readOnlySystemVariables[v.Name],
+		})
+	}
+	for len(defaults) > 0 || len(session) > 0 {
+		switch {
+		case len(session) == 0 || (len(defaults) > 0 && defaults[0].Name < session[0].Name):
+			appendVar(defaults[0], SystemVariableScope_Global)
+			defaults = defaults[1:]
+		default:
+			if len(defaults) > 0 && defaults[0].Name == session[0].Name {
+				defaults = defaults[1:]
+			}
+			appendVar(session[0], SystemVariableScope_Session)
+			session = session[1:]
+		}
+	}
+
+	return all
+}
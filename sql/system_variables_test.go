@@ -0,0 +1,112 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSystemVariable(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	require.NoError(sess.Set(ctx, "sql_mode", LongText, "ANSI_QUOTES"))
+	require.NoError(sess.Set(ctx, "custom_var", Int64, int64(3)))
+
+	// the session value takes precedence over the global one
+	typ, val, scope, err := ctx.ResolveSystemVariable("sql_mode")
+	require.NoError(err)
+	require.Equal(LongText, typ)
+	require.Equal("ANSI_QUOTES", val)
+	require.Equal(SystemVariableScope_Session, scope)
+
+	// the global value is used when the session has none
+	delete(sess.(*BaseSession).config, "max_allowed_packet")
+	_, val, scope, err = ctx.ResolveSystemVariable("@@MAX_ALLOWED_PACKET")
+	require.NoError(err)
+	require.Equal(DefaultSessionConfig()["max_allowed_packet"].Value, val)
+	require.Equal(SystemVariableScope_Global, scope)
+
+	// qualifiers bypass the merge
+	_, val, scope, err = ctx.ResolveSystemVariable("@@global.sql_mode")
+	require.NoError(err)
+	require.Equal("", val)
+	require.Equal(SystemVariableScope_Global, scope)
+
+	_, val, scope, err = ctx.ResolveSystemVariable("@@session.sql_mode")
+	require.NoError(err)
+	require.Equal("ANSI_QUOTES", val)
+	require.Equal(SystemVariableScope_Session, scope)
+
+	_, _, _, err = ctx.ResolveSystemVariable("@@session.max_allowed_packet")
+	require.True(ErrUnknownSystemVariable.Is(err))
+
+	_, _, _, err = ctx.ResolveSystemVariable("@@global.custom_var")
+	require.True(ErrUnknownSystemVariable.Is(err))
+
+	_, _, _, err = ctx.ResolveSystemVariable("no_such_var")
+	require.True(ErrUnknownSystemVariable.Is(err))
+}
+
+func TestAllSystemVariables(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	require.NoError(sess.Set(ctx, "sql_mode", LongText, "ANSI_QUOTES"))
+	require.NoError(sess.Set(ctx, "custom_var", Int64, int64(3)))
+	delete(sess.(*BaseSession).config, "gtid_mode")
+
+	vars := make(map[string]SystemVariable)
+	var names []string
+	for _, v := range AllSystemVariables(sess) {
+		vars[v.Name] = v
+		names = append(names, v.Name)
+	}
+	require.True(sort.StringsAreSorted(names))
+	require.Len(vars, len(DefaultSessionConfig())+1)
+
+	require.Equal(SystemVariable{Name: "sql_mode", Type: LongText, Value: "ANSI_QUOTES", Scope: SystemVariableScope_Session}, vars["sql_mode"])
+	require.Equal(SystemVariable{Name: "custom_var", Type: Int64, Value: int64(3), Scope: SystemVariableScope_Session}, vars["custom_var"])
+	require.Equal(SystemVariable{Name: "gtid_mode", Type: Int32, Value: int32(0), Scope: SystemVariableScope_Global}, vars["gtid_mode"])
+	require.Equal(SystemVariable{Name: "secure_file_priv", Type: LongText, Value: nil, Scope: SystemVariableScope_Session, ReadOnly: true}, vars["secure_file_priv"])
+}
+
+func TestSortedVariables(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewSession("foo", "baz", "bar", 1)
+	ctx := NewContext(context.Background(), WithSession(sess))
+	require.NoError(sess.Set(ctx, "zz_custom", Int64, int64(1)))
+	require.NoError(sess.Set(ctx, "aa_custom", Int64, int64(2)))
+
+	vars := SortedVariables(sess)
+	require.Len(vars, len(sess.GetAll()))
+	require.True(sort.SliceIsSorted(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	}))
+	require.Equal(SessionVariable{"aa_custom", TypedValue{Int64, int64(2)}}, vars[0])
+	require.Equal(SessionVariable{"zz_custom", TypedValue{Int64, int64(1)}}, vars[len(vars)-1])
+
+	// the order is the same on every call
+	for i := 0; i < 10; i++ {
+		require.Equal(vars, SortedVariables(sess))
+	}
+}
@@ -25,16 +25,21 @@ type TableRowIter struct {
 	partitions PartitionIter
 	partition  Partition
 	rows       RowIter
+	// virtual is whether the table has virtual generated columns, whose values are computed for every row read.
+	virtual bool
 }
 
 // NewTableRowIter returns a new iterator over the rows in the partitions of the table given.
 func NewTableRowIter(ctx *Context, table Table, partitions PartitionIter) *TableRowIter {
-	return &TableRowIter{ctx: ctx, table: table, partitions: partitions}
+	return &TableRowIter{ctx: ctx, table: table, partitions: partitions, virtual: table.Schema().HasVirtualColumns()}
 }
 
 func (i *TableRowIter) Next() (Row, error) {
-	if i.ctx.Err() != nil {
-		return nil, i.ctx.Err()
+	if err := i.ctx.CheckInterrupted(); err != nil {
+		return nil, err
+	}
+	if err := i.ctx.CancellationError(); err != nil {
+		return nil, err
 	}
 
 	if i.partition == nil {
@@ -62,6 +67,12 @@ func (i *TableRowIter) Next() (Row, error) {
 	}
 
 	row, err := i.rows.Next()
+	if err == nil {
+		i.ctx.AddRowsExamined(1)
+		if i.virtual {
+			return i.table.Schema().ComputeVirtualColumns(i.ctx, row)
+		}
+	}
 	if err != nil && err == io.EOF {
 		if err = i.rows.Close(i.ctx); err != nil {
 			return nil, err
@@ -0,0 +1,80 @@
+// Copyright 2020-2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// MaxTableAliasDepth is the maximum number of TableAlias redirections followed to resolve a single table name.
+const MaxTableAliasDepth = 16
+
+// ErrTableAliasLoop is returned when a table name can't be resolved without following more than MaxTableAliasDepth
+// aliases, which usually means the aliases form a cycle.
+var ErrTableAliasLoop = errors.NewKind("too many levels of table aliases resolving %s.%s")
+
+// TableResolver resolves table names before the databases of a Catalog are consulted, for integrators with tables
+// that aren't in any database of the catalog, such as synonyms or federated tables. Resolve returns an error of kind
+// ErrTableNotFound to fall through to the databases of the catalog, or a TableAlias to resolve the name as another
+// table. AS OF lookups don't consult it.
+type TableResolver interface {
+	// Resolve returns the table with the name given in the database given.
+	Resolve(ctx *Context, db, name string) (Table, error)
+}
+
+// TableAlias is returned as an error by TableResolver.Resolve to resolve a name as the table given instead, which is
+// looked up again, starting with the resolver. An empty Database keeps the database the name was looked up in.
+type TableAlias struct {
+	Database string
+	Name     string
+}
+
+func (a TableAlias) Error() string {
+	return fmt.Sprintf("table alias for %s.%s", a.Database, a.Name)
+}
+
+// resolveTable looks up the table given with the resolver of the catalog, following aliases. Returns a nil table and
+// the name to look up in the databases of the catalog when the resolver doesn't know it.
+func (c *Catalog) resolveTable(ctx *Context, db, table string) (Table, string, string, error) {
+	resolver := c.TableResolver
+	if resolver == nil {
+		return nil, db, table, nil
+	}
+
+	origDb, origTable := db, table
+	for depth := 0; ; depth++ {
+		if depth > MaxTableAliasDepth {
+			return nil, "", "", ErrTableAliasLoop.New(origDb, origTable)
+		}
+
+		t, err := resolver.Resolve(ctx, db, table)
+		if alias, ok := err.(TableAlias); ok {
+			if alias.Database != "" {
+				db = alias.Database
+			}
+			table = alias.Name
+			continue
+		}
+		if ErrTableNotFound.Is(err) {
+			return nil, db, table, nil
+		}
+		if err != nil {
+			return nil, "", "", err
+		}
+		return t, db, table, nil
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2020-2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"strings"
+	"sync"
+)
+
+type temporaryTableKey struct {
+	dbName, tableName string
+}
+
+func newTemporaryTableKey(dbName, tableName string) temporaryTableKey {
+	return temporaryTableKey{strings.ToLower(dbName), strings.ToLower(tableName)}
+}
+
+// TemporaryTableRegistry holds the temporary tables of a session, by database. A temporary table shadows any table of
+// its database with the same name for the session owning it, and is never visible to other sessions. A nil registry
+// has no tables.
+type TemporaryTableRegistry struct {
+	mutex  sync.RWMutex
+	tables map[temporaryTableKey]Table
+}
+
+// NewTemporaryTableRegistry creates an empty TemporaryTableRegistry.
+func NewTemporaryTableRegistry() *TemporaryTableRegistry {
+	return &TemporaryTableRegistry{
+		tables: make(map[temporaryTableKey]Table),
+	}
+}
+
+// Register adds the temporary table given to the database named, returning ErrTableAlreadyExists if the database
+// already has a temporary table with that name.
+func (r *TemporaryTableRegistry) Register(dbName string, table Table) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := newTemporaryTableKey(dbName, table.Name())
+	if _, ok := r.tables[key]; ok {
+		return ErrTableAlreadyExists.New(table.Name())
+	}
+
+	r.tables[key] = table
+	return nil
+}
+
+// Table returns the temporary table of the database named with the name given, case-insensitively.
+func (r *TemporaryTableRegistry) Table(dbName, tableName string) (Table, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	table, ok := r.tables[newTemporaryTableKey(dbName, tableName)]
+	return table, ok
+}
+
+// Delete removes the temporary table of the database named with the name given, returning whether there was one.
+func (r *TemporaryTableRegistry) Delete(dbName, tableName string) bool {
+	if r == nil {
+		return false
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := newTemporaryTableKey(dbName, tableName)
+	if _, ok := r.tables[key]; !ok {
+		return false
+	}
+
+	delete(r.tables, key)
+	return true
+}
+
+// Clear removes every temporary table, as done when the session owning them ends.
+func (r *TemporaryTableRegistry) Clear() {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.tables = make(map[temporaryTableKey]Table)
+}
+
+// Len returns the number of temporary tables in the registry.
+func (r *TemporaryTableRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.tables)
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SystemTimeZone is the value of the time_zone variable that refers to the time zone of the server.
+const SystemTimeZone = "SYSTEM"
+
+var timeZoneOffsetRegex = regexp.MustCompile(`^([+-])(\d{1,2}):(\d{2})$`)
+
+// The range of offsets accepted by MySQL for time zones given as offsets.
+const (
+	minTimeZoneOffset = -(13*time.Hour + 59*time.Minute)
+	maxTimeZoneOffset = 14 * time.Hour
+)
+
+// ParseTimeZone returns the location for a value of the time_zone variable. It can be SYSTEM for the time zone of the
+// server, an offset from UTC such as "+05:30", or a named time zone such as "Europe/Madrid".
+func ParseTimeZone(tz string) (*time.Location, error) {
+	if strings.EqualFold(tz, SystemTimeZone) {
+		return time.Local, nil
+	}
+
+	if matches := timeZoneOffsetRegex.FindStringSubmatch(tz); matches != nil {
+		hours, _ := strconv.Atoi(matches[2])
+		minutes, _ := strconv.Atoi(matches[3])
+		offset := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+		if matches[1] == "-" {
+			offset = -offset
+		}
+		if minutes > 59 || offset < minTimeZoneOffset || offset > maxTimeZoneOffset {
+			return nil, ErrUnknownTimeZone.New(tz)
+		}
+		return time.FixedZone(tz, int(offset/time.Second)), nil
+	}
+
+	// time.LoadLocation treats the empty string and "Local" specially, neither of which are valid names here
+	if tz == "" || tz == "Local" {
+		return nil, ErrUnknownTimeZone.New(tz)
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrUnknownTimeZone.New(tz)
+	}
+	return loc, nil
+}
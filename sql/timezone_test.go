@@ -0,0 +1,84 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimeZone(t *testing.T) {
+	ref := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		tz     string
+		offset int
+		err    bool
+	}{
+		{tz: "SYSTEM", offset: func() int { _, o := ref.In(time.Local).Zone(); return o }()},
+		{tz: "system", offset: func() int { _, o := ref.In(time.Local).Zone(); return o }()},
+		{tz: "UTC", offset: 0},
+		{tz: "+00:00", offset: 0},
+		{tz: "+05:30", offset: 5*3600 + 30*60},
+		{tz: "-8:00", offset: -8 * 3600},
+		{tz: "+14:00", offset: 14 * 3600},
+		{tz: "-13:59", offset: -(13*3600 + 59*60)},
+		{tz: "Asia/Tokyo", offset: 9 * 3600},
+		{tz: "+14:01", err: true},
+		{tz: "-14:00", err: true},
+		{tz: "+05:60", err: true},
+		{tz: "Local", err: true},
+		{tz: "", err: true},
+		{tz: "Not/A_Zone", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tz, func(t *testing.T) {
+			loc, err := ParseTimeZone(tt.tz)
+			if tt.err {
+				require.True(t, ErrUnknownTimeZone.Is(err))
+				return
+			}
+			require.NoError(t, err)
+			_, offset := ref.In(loc).Zone()
+			require.Equal(t, tt.offset, offset)
+		})
+	}
+}
+
+func TestSessionTimeZone(t *testing.T) {
+	require := require.New(t)
+	sess := NewBaseSession()
+
+	loc, err := sess.TimeZone()
+	require.NoError(err)
+	require.Equal(time.Local, loc)
+
+	require.NoError(sess.Set(context.Background(), TimeZoneSessionVar, LongText, "Asia/Tokyo"))
+	loc, err = sess.TimeZone()
+	require.NoError(err)
+	require.Equal("Asia/Tokyo", loc.String())
+
+	cached, err := sess.TimeZone()
+	require.NoError(err)
+	require.True(loc == cached)
+
+	require.NoError(sess.Set(context.Background(), TimeZoneSessionVar, LongText, "bad"))
+	_, err = sess.TimeZone()
+	require.True(ErrUnknownTimeZone.Is(err))
+}
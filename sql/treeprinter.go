@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 )
 
 // TreePrinter is a printer for tree nodes.
@@ -109,3 +111,21 @@ func (p *TreePrinter) WriteChildren(children ...string) error {
 func (p *TreePrinter) String() string {
 	return p.buf.String()
 }
+
+// QuoteIdentifier returns the identifier given quoted with backticks if it needs to be quoted to be parsed back, such
+// as when it contains spaces or is a reserved word. Backticks in the identifier are escaped by doubling them.
+func QuoteIdentifier(id string) string {
+	if id == "" {
+		return "``"
+	}
+	return sqlparser.String(sqlparser.NewColIdent(id))
+}
+
+// QuoteIdentifiers returns the identifiers given, each quoted with QuoteIdentifier.
+func QuoteIdentifiers(ids []string) []string {
+	quoted := make([]string, len(ids))
+	for i, id := range ids {
+		quoted[i] = QuoteIdentifier(id)
+	}
+	return quoted
+}
@@ -54,3 +54,24 @@ func TestTreePrinter(t *testing.T) {
 
 	require.Equal(t, expectedTree, p.String())
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected string
+	}{
+		{"mytable", "mytable"},
+		{"my_col2", "my_col2"},
+		{"my col", "`my col`"},
+		{"select", "`select`"},
+		{"1col", "`1col`"},
+		{"a`b", "`a``b`"},
+		{"", "``"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.id, func(t *testing.T) {
+			require.Equal(t, tt.expected, QuoteIdentifier(tt.id))
+		})
+	}
+}
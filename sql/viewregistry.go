@@ -65,16 +65,22 @@ func NewViewKey(databaseName, viewName string) ViewKey {
 }
 
 // ViewRegistry is a map of ViewKey to View whose access is protected by a
-// RWMutex.
+// RWMutex. It also holds an optional cache of resolved view definitions,
+// along with the tables and views each cached definition was resolved
+// against, so that the cache can be invalidated when those change.
 type ViewRegistry struct {
-	mutex sync.RWMutex
-	views map[ViewKey]View
+	mutex    sync.RWMutex
+	views    map[ViewKey]View
+	resolved map[ViewKey]Node
+	deps     map[ViewKey][]ViewKey
 }
 
 // NewViewRegistry creates an empty ViewRegistry.
 func NewViewRegistry() *ViewRegistry {
 	return &ViewRegistry{
-		views: make(map[ViewKey]View),
+		views:    make(map[ViewKey]View),
+		resolved: make(map[ViewKey]Node),
+		deps:     make(map[ViewKey][]ViewKey),
 	}
 }
 
@@ -107,6 +113,7 @@ func (r *ViewRegistry) Delete(databaseName, viewName string) error {
 	}
 
 	delete(r.views, key)
+	r.invalidate(key)
 	return nil
 }
 
@@ -128,6 +135,7 @@ func (r *ViewRegistry) DeleteList(keys []ViewKey, errIfNotExists bool) error {
 
 	for _, key := range keys {
 		delete(r.views, key)
+		r.invalidate(key)
 	}
 
 	return nil
@@ -148,6 +156,79 @@ func (r *ViewRegistry) View(databaseName, viewName string) (*View, error) {
 	return nil, ErrNonExistingView.New(databaseName, viewName)
 }
 
+// CacheResolvedView stores the resolved definition of the view specified by
+// the pair {databaseName, viewName}. The dependencies are the keys, built with
+// NewViewKey, of every table and view the definition was resolved against;
+// invalidating any of them through InvalidateForTable discards the cached
+// definition. It returns an error if the view does not exist.
+func (r *ViewRegistry) CacheResolvedView(databaseName, viewName string, definition Node, dependencies []ViewKey) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := NewViewKey(databaseName, viewName)
+
+	if _, ok := r.views[key]; !ok {
+		return ErrNonExistingView.New(databaseName, viewName)
+	}
+
+	deps := make([]ViewKey, len(dependencies))
+	for i, dep := range dependencies {
+		deps[i] = NewViewKey(dep.dbName, dep.viewName)
+	}
+
+	r.resolved[key] = definition
+	r.deps[key] = deps
+	return nil
+}
+
+// ResolvedView returns the cached resolved definition of the view specified by
+// the pair {databaseName, viewName}, if there is one.
+func (r *ViewRegistry) ResolvedView(databaseName, viewName string) (Node, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	n, ok := r.resolved[NewViewKey(databaseName, viewName)]
+	return n, ok
+}
+
+// InvalidateForTable discards the cached resolved definitions of all the views
+// that depend on the table specified by the pair {databaseName, tableName},
+// either directly or through other views. Integrators should call it whenever
+// a table is altered or dropped.
+func (r *ViewRegistry) InvalidateForTable(databaseName, tableName string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.invalidate(NewViewKey(databaseName, tableName))
+}
+
+// invalidate discards the cached definitions of every view depending on the
+// given key, transitively. The key itself may name a table or a view.
+func (r *ViewRegistry) invalidate(key ViewKey) {
+	pending := []ViewKey{key}
+	for len(pending) > 0 {
+		changed := pending[0]
+		pending = pending[1:]
+
+		for view, deps := range r.deps {
+			for _, dep := range deps {
+				if dep == changed {
+					delete(r.resolved, view)
+					delete(r.deps, view)
+					pending = append(pending, view)
+					break
+				}
+			}
+		}
+	}
+
+	// A view that is being deleted loses its own cached definition too.
+	if _, ok := r.views[key]; !ok {
+		delete(r.resolved, key)
+		delete(r.deps, key)
+	}
+}
+
 // AllViews returns the map of all views in the registry.
 func (r *ViewRegistry) AllViews() map[ViewKey]View {
 	r.mutex.RLock()
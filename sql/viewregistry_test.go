@@ -237,3 +237,58 @@ func TestExistsOnNonExistingView(t *testing.T) {
 
 	require.False(registry.Exists("non", "existing"))
 }
+
+// Tests that altering a base table invalidates the cached definitions of the
+// views that depend on it, directly or through other views, and no others.
+func TestInvalidateForTable(t *testing.T) {
+	require := require.New(t)
+
+	registry := NewViewRegistry()
+	for _, name := range []string{"v1", "v2", "v3", "v4"} {
+		require.NoError(registry.Register(dbName, NewView(name, nil, "")))
+	}
+
+	require.NoError(registry.CacheResolvedView(dbName, "v1", mockView.Definition(), []ViewKey{NewViewKey(dbName, "T1")}))
+	require.NoError(registry.CacheResolvedView(dbName, "v2", mockView.Definition(), []ViewKey{NewViewKey(dbName, "v1")}))
+	require.NoError(registry.CacheResolvedView(dbName, "v3", mockView.Definition(), []ViewKey{NewViewKey(dbName, "t2")}))
+	require.NoError(registry.CacheResolvedView(dbName, "v4", mockView.Definition(), []ViewKey{NewViewKey(dbName, "v2"), NewViewKey(dbName, "v3")}))
+
+	err := registry.CacheResolvedView(dbName, "nonexistent", nil, nil)
+	require.True(ErrNonExistingView.Is(err))
+
+	registry.InvalidateForTable(dbName, "t1")
+
+	_, ok := registry.ResolvedView(dbName, "v1")
+	require.False(ok)
+	_, ok = registry.ResolvedView(dbName, "v2")
+	require.False(ok)
+	_, ok = registry.ResolvedView(dbName, "v3")
+	require.True(ok)
+	_, ok = registry.ResolvedView(dbName, "v4")
+	require.False(ok)
+
+	registry.InvalidateForTable("otherdb", "t2")
+	_, ok = registry.ResolvedView(dbName, "v3")
+	require.True(ok)
+
+	require.Equal(4, len(registry.AllViews()))
+}
+
+// Tests that deleting a view invalidates the cached definitions of the views
+// built on top of it.
+func TestDeleteViewInvalidatesDependents(t *testing.T) {
+	require := require.New(t)
+
+	registry := NewViewRegistry()
+	require.NoError(registry.Register(dbName, NewView("v1", nil, "")))
+	require.NoError(registry.Register(dbName, NewView("v2", nil, "")))
+	require.NoError(registry.CacheResolvedView(dbName, "v1", nil, []ViewKey{NewViewKey(dbName, "t1")}))
+	require.NoError(registry.CacheResolvedView(dbName, "v2", nil, []ViewKey{NewViewKey(dbName, "v1")}))
+
+	require.NoError(registry.Delete(dbName, "v1"))
+
+	_, ok := registry.ResolvedView(dbName, "v1")
+	require.False(ok)
+	_, ok = registry.ResolvedView(dbName, "v2")
+	require.False(ok)
+}
@@ -0,0 +1,55 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// WarningsSchema is the schema of the result of SHOW WARNINGS.
+var WarningsSchema = Schema{
+	&Column{Name: "Level", Type: LongText, Nullable: false},
+	&Column{Name: "Code", Type: Uint32, Nullable: false},
+	&Column{Name: "Message", Type: LongText, Nullable: false},
+}
+
+// WarningsToRows returns the rows and schema of the result of SHOW WARNINGS for the warnings given, one row per
+// warning in the same order, which for the warnings returned by Session.Warnings is most recent first. Messages are
+// returned whole, however long they are.
+func WarningsToRows(ws []*Warning) ([]Row, Schema) {
+	rows := make([]Row, len(ws))
+	for i, w := range ws {
+		rows[i] = NewRow(w.Level, uint32(w.Code), w.Message)
+	}
+	return rows, WarningsSchema
+}
+
+// DiagnosticsArea holds the conditions raised by the statements since the warnings of a session were last cleared, as
+// read by GET DIAGNOSTICS.
+type DiagnosticsArea struct {
+	// Conditions are the conditions of the area, from the most recent, so that condition 1 of GET DIAGNOSTICS is the
+	// last one raised.
+	Conditions []*Warning
+}
+
+// Number returns the number of conditions of the area, as read by the NUMBER item of GET DIAGNOSTICS.
+func (d DiagnosticsArea) Number() int {
+	return len(d.Conditions)
+}
+
+// Condition returns the condition with the number given, starting from 1 for the most recent, as read by GET
+// DIAGNOSTICS CONDITION. Returns false if there is no condition with that number.
+func (d DiagnosticsArea) Condition(n int) (*Warning, bool) {
+	if n < 1 || n > len(d.Conditions) {
+		return nil, false
+	}
+	return d.Conditions[n-1], true
+}
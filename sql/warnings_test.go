@@ -0,0 +1,102 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarningsToRows(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	longMessage := strings.Repeat("a", 70000)
+	sess.Warn(&Warning{Level: "Note", Code: 1, Message: "first"})
+	sess.Warn(&Warning{Level: "Warning", Code: 1292, Message: longMessage})
+
+	rows, schema := WarningsToRows(sess.Warnings())
+	require.Equal(Schema{
+		{Name: "Level", Type: LongText},
+		{Name: "Code", Type: Uint32},
+		{Name: "Message", Type: LongText},
+	}, schema)
+	require.Equal([]Row{
+		{"Warning", uint32(1292), longMessage},
+		{"Note", uint32(1), "first"},
+	}, rows)
+
+	rows, _ = WarningsToRows(nil)
+	require.Empty(rows)
+}
+
+func TestDiagnostics(t *testing.T) {
+	require := require.New(t)
+
+	sess := NewBaseSession()
+	require.Equal(0, sess.Diagnostics().Number())
+
+	sess.Warn(&Warning{Level: "Note", Code: 1051, Message: "Unknown table 'mydb.t'", SQLState: "42S02"})
+	sess.Warn(&Warning{Level: "Warning", Code: 1292, Message: "Truncated incorrect DOUBLE value: 'a'", SQLState: "22007"})
+
+	diag := sess.Diagnostics()
+	require.Equal(2, diag.Number())
+	cond, ok := diag.Condition(1)
+	require.True(ok)
+	require.Equal("Truncated incorrect DOUBLE value: 'a'", cond.Message)
+	require.Equal(1292, cond.Code)
+	require.Equal("22007", cond.SQLState)
+	cond, ok = diag.Condition(2)
+	require.True(ok)
+	require.Equal(1051, cond.Code)
+	require.Equal("42S02", cond.SQLState)
+	_, ok = diag.Condition(0)
+	require.False(ok)
+	_, ok = diag.Condition(3)
+	require.False(ok)
+
+	// like the warnings, conditions survive the first ClearWarnings after they were raised and are cleared by the next
+	sess.ClearWarnings()
+	require.Equal(2, sess.Diagnostics().Number())
+	sess.ClearWarnings()
+	require.Equal(0, sess.Diagnostics().Number())
+}
+
+func TestWarningSQLState(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("42S02", SQLStateForCode(1146))
+	require.Equal("23000", SQLStateForCode(1062))
+	require.Equal("22007", SQLStateForCode(1292))
+	require.Equal("HY000", SQLStateForCode(1105))
+	// codes of integrators without a known SQLSTATE
+	require.Equal("HY000", SQLStateForCode(50000))
+
+	ctx := NewContext(context.Background(), WithSession(NewBaseSession()))
+	ctx.Warn(1292, "Truncated incorrect %s value: '%s'", "DOUBLE", "a")
+	ctx.Error(50000, "custom error")
+	buf := NewWarningBuffer(ctx, 0)
+	buf.Warn(1264, "Out of range value")
+	buf.Flush()
+
+	var states []string
+	for _, w := range ctx.Session.Warnings() {
+		states = append(states, w.SQLState)
+	}
+	require.Equal([]string{"22003", "HY000", "22007"}, states)
+}